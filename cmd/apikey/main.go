@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"go-fiber-hexagonal-product/internal/adapters/repositories"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/pkg/config"
+	"go-fiber-hexagonal-product/pkg/database"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Membuat API key langsung di database, dipakai untuk membuat key admin pertama
+func main() {
+	name := flag.String("name", "", "nama pemilik API key")
+	scopes := flag.String("scopes", "", "daftar scope dipisah koma, misalnya admin,products:read")
+	tenant := flag.String("tenant", "", "tenant pemegang key, kosong berarti tidak terikat ke satu tenant")
+	ttl := flag.Duration("ttl", 0, "masa berlaku key, 0 berarti tidak kedaluwarsa")
+	flag.Parse()
+
+	// Konfigurasi dari file (GOPRODUCT_CONFIG_FILE) dan environment, flag dipakai oleh perintah ini
+	cfg, _ := config.MustLoad("apikey", nil)
+
+	mongoClient, err := database.ConnectWithRetry(context.Background(), "mongodb", cfg.ConnectRetry(), func(ctx context.Context) (*mongo.Client, error) {
+		return database.NewMongoDBConnection(ctx, cfg.MongoURI, cfg.MongoOptions())
+	})
+	if err != nil {
+		fatal("failed to connect to mongodb", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	apiKeyRepo := repositories.NewMongoAPIKeyRepository(mongoClient.Database(cfg.MongoDatabaseName).Collection("api_keys"))
+	if err := apiKeyRepo.EnsureIndexes(context.Background()); err != nil {
+		fatal("failed to create api key indexes", err)
+	}
+
+	request := domain.APIKeyRequest{Name: *name, TenantID: *tenant}
+	if *scopes != "" {
+		request.Scopes = strings.Split(*scopes, ",")
+	}
+	if *ttl > 0 {
+		expiresAt := time.Now().Add(*ttl).UTC()
+		request.ExpiresAt = &expiresAt
+	}
+
+	ctx := domain.ContextWithActor(context.Background(), "cli")
+	issued, err := services.NewAPIKeyService(apiKeyRepo).IssueAPIKey(ctx, request)
+	if err != nil {
+		fatal("failed to issue api key", err)
+	}
+
+	// Key asli hanya ditampilkan sekali
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(issued); err != nil {
+		fatal("failed to write api key", err)
+	}
+}
+
+// Mencatat error lalu menghentikan proses
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
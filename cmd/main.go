@@ -2,43 +2,311 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"go-fiber-hexagonal-product/internal/adapters/auth"
+	"go-fiber-hexagonal-product/internal/adapters/cache"
+	"go-fiber-hexagonal-product/internal/adapters/idempotency"
+	"go-fiber-hexagonal-product/internal/adapters/idgen"
+	"go-fiber-hexagonal-product/internal/adapters/logging"
+	"go-fiber-hexagonal-product/internal/adapters/metrics"
+	"go-fiber-hexagonal-product/internal/adapters/ratelimit"
 	"go-fiber-hexagonal-product/internal/adapters/repositories"
+	"go-fiber-hexagonal-product/internal/adapters/resilience"
+	"go-fiber-hexagonal-product/internal/adapters/tracing"
 	"go-fiber-hexagonal-product/internal/app"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"go-fiber-hexagonal-product/internal/core/services"
 	"go-fiber-hexagonal-product/pkg/config"
 	"go-fiber-hexagonal-product/pkg/database"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func main() {
-    // Load configuration
-    cfg := config.LoadConfig()
-
-    // Inisialisasi repository MongoDB
-    mongoClient, err := database.NewMongoDBConnection(cfg.MongoURI)
-    if err != nil {
-        log.Fatalf("Gagal terhubung ke MongoDB: %v", err)
-    }
-    defer mongoClient.Disconnect(context.Background())
-    
-    // Dapatkan koleksi produk dari MongoDB
-    mongoCollection := mongoClient.Database(cfg.MongoDatabaseName).Collection("products")
-    
-    // Buat repository produk MongoDB baru
-    mongoRepo := repositories.NewMongoProductRepository(mongoCollection)
-
-    // Inisialisasi repository MySQL
-    mysqlDB, err := database.NewMySQLConnection(cfg.MySQLDSN)
-    if err != nil {
-        log.Fatalf("Gagal terhubung ke MySQL: %v", err)
-    }
-    defer mysqlDB.Close()
-    
-    // Buat repository produk MySQL baru
-    mysqlRepo := repositories.NewMySQLProductRepository(mysqlDB)
-
-    // Inisialisasi aplikasi dengan kedua repository
-    application := app.NewApp(cfg, mongoRepo, mysqlRepo)
-    
-    // Mulai aplikasi
-    log.Fatal(application.Start())
-}
\ No newline at end of file
+	// Konfigurasi berlapis: default, file, environment lalu flag
+	cfg, loader := config.MustLoad("goproduct", os.Args[1:])
+
+	// Logger terstruktur untuk seluruh aplikasi, level bisa diubah lewat hot reload
+	var logLevel slog.LevelVar
+	logger, err := logging.New(os.Stdout, logging.Options{Level: cfg.LogLevel, Format: cfg.LogFormat, LevelVar: &logLevel})
+	if err != nil {
+		fatal("invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
+	// Hot reload: file konfigurasi dipantau dan SIGHUP memicu reload
+	configManager := config.NewManager(loader, cfg)
+	configManager.OnReload(func(_, current *config.Config) {
+		if level, err := logging.ParseLevel(current.LogLevel); err == nil {
+			logLevel.Set(level)
+		}
+	})
+
+	// Tracing OpenTelemetry untuk HTTP, service dan kedua database
+	var shutdownTracing func(context.Context) error
+	if cfg.TracingExporter != "" {
+		shutdownTracing, err = tracing.Setup(context.Background(), tracing.Options{
+			Exporter:    cfg.TracingExporter,
+			ServiceName: cfg.TracingServiceName,
+			Endpoint:    cfg.TracingEndpoint,
+			Insecure:    cfg.TracingInsecure,
+			File:        cfg.TracingFile,
+			SampleRatio: cfg.TracingSampleRatio,
+		})
+		if err != nil {
+			fatal("invalid tracing configuration", err)
+		}
+	}
+
+	// SIGINT/SIGTERM membatalkan percobaan koneksi saat startup dan menghentikan server
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Inisialisasi repository MongoDB, dicoba ulang dengan backoff sampai database siap
+	mongoClient, err := database.ConnectWithRetry(ctx, "mongodb", cfg.ConnectRetry(), func(ctx context.Context) (*mongo.Client, error) {
+		return database.NewMongoDBConnection(ctx, cfg.MongoURI, cfg.MongoOptions())
+	})
+	if err != nil {
+		fatal("failed to connect to mongodb", err)
+	}
+
+	// Dapatkan koleksi produk dari MongoDB
+	mongoCollection := mongoClient.Database(cfg.MongoDatabaseName).Collection("products")
+
+	// Buat repository produk MongoDB baru
+	mongoRepo := repositories.NewMongoProductRepository(mongoCollection)
+	if err := mongoRepo.EnsureIndexes(context.Background()); err != nil {
+		fatal("failed to create product indexes", err)
+	}
+
+	// Inisialisasi repository MySQL
+	mysqlDB, err := database.ConnectWithRetry(ctx, "mysql", cfg.ConnectRetry(), func(ctx context.Context) (*sql.DB, error) {
+		return database.NewMySQLConnection(ctx, cfg.MySQLDSN, cfg.MySQLOptions())
+	})
+	if err != nil {
+		fatal("failed to connect to mysql", err)
+	}
+
+	// Buat repository produk MySQL baru
+	mysqlStore := repositories.NewMySQLProductRepository(mysqlDB)
+	var mysqlRepo ports.MySQLProductRepository = mysqlStore
+
+	// Metrics Prometheus untuk HTTP, repository, connection pool dan jumlah produk
+	var opts []app.Option
+	var productRepo ports.MongoProductRepository = mongoRepo
+	if cfg.MetricsEnabled {
+		appMetrics := metrics.New()
+		if err := appMetrics.RegisterDBStats("mysql", mysqlDB); err != nil {
+			fatal("failed to register mysql pool metrics", err)
+		}
+		if err := appMetrics.RegisterProductStats(mongoRepo, cfg.LowStockThreshold, 5*time.Second); err != nil {
+			fatal("failed to register product metrics", err)
+		}
+		productRepo = appMetrics.InstrumentMongoProductRepository(mongoRepo)
+		mysqlRepo = appMetrics.InstrumentMySQLProductRepository(mysqlRepo)
+		opts = append(opts, app.WithMetrics(appMetrics))
+	}
+	if cfg.TracingExporter != "" {
+		productRepo = tracing.InstrumentMongoProductRepository(productRepo)
+		mysqlRepo = tracing.InstrumentMySQLProductRepository(mysqlRepo)
+		opts = append(opts, app.WithTracing())
+	}
+
+	// Circuit breaker, retry dan bulkhead per store sebagai lapisan terluar,
+	// sehingga setiap percobaan tetap tercatat di metrics dan trace
+	if cfg.ResilienceEnabled {
+		mongoPolicy := resilience.NewPolicy("mongodb", resilienceOptions(cfg, cfg.BulkheadMongoMaxConcurrent), resilience.IsMongoTransient)
+		mysqlPolicy := resilience.NewPolicy("mysql", resilienceOptions(cfg, cfg.BulkheadMySQLMaxConcurrent), resilience.IsMySQLTransient)
+		productRepo = resilience.ProtectMongoProductRepository(productRepo, mongoPolicy)
+		mysqlRepo = resilience.ProtectMySQLProductRepository(mysqlRepo, mysqlPolicy)
+		opts = append(opts,
+			app.WithResiliencePolicy("mongodb", mongoPolicy),
+			app.WithResiliencePolicy("mysql", mysqlPolicy),
+		)
+	}
+
+	// Jalankan sinkronisasi change stream di dalam proses jika diaktifkan.
+	// Untuk menjalankannya sebagai proses terpisah gunakan cmd/syncer.
+	if cfg.ChangeStreamSyncEnabled {
+		tokenStore := repositories.NewMongoResumeTokenStore(mongoClient.Database(cfg.MongoDatabaseName).Collection("sync_resume_tokens"))
+		syncer := repositories.NewMongoChangeStreamSyncer(mongoCollection, mysqlRepo, tokenStore, cfg.ChangeStreamSyncName)
+		opts = append(opts, app.WithBackgroundWorker("change-stream-sync", syncer.Run))
+	}
+
+	// ID produk dibuat sebelum penulisan agar MongoDB dan MySQL ditulis bersamaan
+	switch cfg.IDStrategy {
+	case "objectid":
+		opts = append(opts, app.WithIDGenerator(idgen.NewObjectIDGenerator()))
+	case "uuidv7":
+		opts = append(opts, app.WithIDGenerator(idgen.NewUUIDv7Generator()))
+	case "ulid":
+		opts = append(opts, app.WithIDGenerator(idgen.NewULIDGenerator()))
+	case "snowflake":
+		generator, err := idgen.NewSnowflakeGenerator(int64(cfg.SnowflakeNodeID))
+		if err != nil {
+			fatal("failed to create snowflake id generator", err)
+		}
+		opts = append(opts, app.WithIDGenerator(generator))
+	}
+
+	// Koneksi Redis dibuat sekali jika dipakai oleh cache atau rate limit
+	var redisClient *redis.Client
+	if cfg.CacheBackend == "redis" || cfg.RateLimitBackend == "redis" || cfg.IdempotencyBackend == "redis" {
+		redisClient, err = database.NewRedisConnection(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		if err != nil {
+			fatal("failed to connect to redis", err)
+		}
+	}
+
+	// Readiness: MongoDB wajib tersedia, tanpa MySQL service berjalan dalam mode degraded
+	opts = append(opts,
+		app.WithHealthCheck("mongodb", mongoRepo, true),
+		app.WithHealthCheck("mysql", mysqlStore, false),
+	)
+
+	// Inisialisasi cache pembacaan produk sesuai konfigurasi
+	switch cfg.CacheBackend {
+	case "memory":
+		opts = append(opts, app.WithProductCache(cache.NewMemoryCache(cfg.CacheMaxEntries)))
+	case "redis":
+		opts = append(opts, app.WithProductCache(cache.NewRedisCache(redisClient, "goproduct:")))
+	}
+
+	// Rate limit per client dan per tenant
+	switch cfg.RateLimitBackend {
+	case "memory":
+		opts = append(opts, app.WithRateLimiter(ratelimit.NewMemoryRateLimiter()))
+	case "redis":
+		opts = append(opts, app.WithRateLimiter(ratelimit.NewRedisRateLimiter(redisClient, "goproduct:ratelimit:")))
+	}
+
+	// Idempotency-Key untuk pembuatan produk
+	switch cfg.IdempotencyBackend {
+	case "memory":
+		opts = append(opts, app.WithIdempotencyStore(idempotency.NewMemoryStore()))
+	case "redis":
+		opts = append(opts, app.WithIdempotencyStore(idempotency.NewRedisStore(redisClient, "goproduct:idempotency:")))
+	}
+
+	// Audit log penulisan produk (append-only)
+	auditRepo := repositories.NewMongoAuditRepository(mongoClient.Database(cfg.MongoDatabaseName).Collection("product_audit"))
+	if err := auditRepo.EnsureIndexes(context.Background()); err != nil {
+		fatal("failed to create audit indexes", err)
+	}
+	opts = append(opts, app.WithAuditRepository(auditRepo))
+
+	// Riwayat revisi produk untuk as_of dan restore
+	revisionRepo := repositories.NewMongoRevisionRepository(mongoClient.Database(cfg.MongoDatabaseName).Collection("product_revisions"))
+	if err := revisionRepo.EnsureIndexes(context.Background()); err != nil {
+		fatal("failed to create revision indexes", err)
+	}
+	opts = append(opts, app.WithRevisionRepository(revisionRepo))
+
+	// Autentikasi JWT untuk endpoint /api
+	if cfg.AuthEnabled {
+		jwtConfig := auth.JWTConfig{
+			HS256Secret: []byte(cfg.JWTHS256Secret),
+			Issuer:      cfg.JWTIssuer,
+			Audience:    cfg.JWTAudience,
+			Leeway:      cfg.JWTLeeway,
+			TenantClaim: cfg.JWTTenantClaim,
+		}
+		switch {
+		case cfg.JWKSFile != "":
+			jwtConfig.KeySet = auth.NewJWKSFileKeySet(cfg.JWKSFile)
+		case cfg.JWKSURL != "":
+			jwtConfig.KeySet = auth.NewJWKSURLKeySet(cfg.JWKSURL, &http.Client{
+				Timeout:   5 * time.Second,
+				Transport: tracing.Transport(nil),
+			})
+		}
+		if jwtConfig.KeySet != nil {
+			if err := jwtConfig.KeySet.Load(context.Background()); err != nil {
+				fatal("failed to load jwks", err)
+			}
+		}
+		verifier, err := auth.NewJWTVerifier(jwtConfig)
+		if err != nil {
+			fatal("invalid jwt configuration", err)
+		}
+		opts = append(opts, app.WithTokenVerifier(verifier))
+	}
+
+	// API key untuk batch tool internal
+	if cfg.APIKeysEnabled {
+		apiKeyRepo := repositories.NewMongoAPIKeyRepository(mongoClient.Database(cfg.MongoDatabaseName).Collection("api_keys"))
+		if err := apiKeyRepo.EnsureIndexes(context.Background()); err != nil {
+			fatal("failed to create api key indexes", err)
+		}
+		opts = append(opts, app.WithAPIKeyRepository(apiKeyRepo))
+	}
+
+	// Policy role/scope untuk principal yang sudah terautentikasi
+	if cfg.AuthEnabled || cfg.APIKeysEnabled {
+		policy := services.DefaultPolicy()
+		if cfg.PolicyFile != "" {
+			if policy, err = config.LoadPolicyFile(cfg.PolicyFile); err != nil {
+				fatal("failed to load policy", err)
+			}
+		}
+		opts = append(opts, app.WithAuthorizer(services.NewPolicyAuthorizer(policy)))
+	}
+
+	opts = append(opts,
+		app.WithConfigManager(configManager),
+		app.WithBackgroundWorker("config-watcher", configManager.Watch),
+	)
+
+	// Koneksi ditutup setelah request dan worker selesai: Redis, MySQL, MongoDB,
+	// lalu span yang tersisa dikirim ke exporter
+	if redisClient != nil {
+		opts = append(opts, app.WithCloser("redis", func(context.Context) error { return redisClient.Close() }))
+	}
+	opts = append(opts,
+		app.WithCloser("mysql", func(context.Context) error { return mysqlDB.Close() }),
+		app.WithCloser("mongodb", mongoClient.Disconnect),
+	)
+	if shutdownTracing != nil {
+		opts = append(opts, app.WithCloser("tracing", shutdownTracing))
+	}
+
+	// Inisialisasi aplikasi dengan kedua repository
+	application := app.NewApp(cfg, productRepo, mysqlRepo, opts...)
+
+	// Hentikan server saat menerima SIGINT/SIGTERM dan tunggu shutdown selesai
+	if err := application.Run(ctx); err != nil {
+		fatal("server stopped with error", err)
+	}
+}
+
+// Pengaturan resilience satu store dari konfigurasi
+func resilienceOptions(cfg *config.Config, maxConcurrent int) resilience.Options {
+	return resilience.Options{
+		Breaker: resilience.BreakerOptions{
+			FailureThreshold: cfg.BreakerFailureThreshold,
+			OpenTimeout:      cfg.BreakerOpenTimeout,
+			SuccessThreshold: cfg.BreakerSuccessThreshold,
+		},
+		Retry: resilience.RetryOptions{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay,
+			MaxDelay:    cfg.RetryMaxDelay,
+		},
+		MaxConcurrent: maxConcurrent,
+		MaxWait:       cfg.BulkheadMaxWait,
+		Timeout:       cfg.RepositoryTimeout,
+	}
+}
+
+// Mencatat error lalu menghentikan proses
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
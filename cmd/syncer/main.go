@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"go-fiber-hexagonal-product/internal/adapters/logging"
+	"go-fiber-hexagonal-product/internal/adapters/repositories"
+	"go-fiber-hexagonal-product/pkg/config"
+	"go-fiber-hexagonal-product/pkg/database"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Proses mandiri untuk sinkronisasi perubahan koleksi produk MongoDB ke MySQL
+func main() {
+	// Konfigurasi berlapis: default, file, environment lalu flag
+	cfg, _ := config.MustLoad("syncer", os.Args[1:])
+
+	// Logger terstruktur untuk seluruh aplikasi
+	logger, err := logging.New(os.Stdout, logging.Options{Level: cfg.LogLevel, Format: cfg.LogFormat})
+	if err != nil {
+		fatal("invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
+	// Hentikan consumer saat menerima SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Inisialisasi koneksi MongoDB
+	mongoClient, err := database.ConnectWithRetry(ctx, "mongodb", cfg.ConnectRetry(), func(ctx context.Context) (*mongo.Client, error) {
+		return database.NewMongoDBConnection(ctx, cfg.MongoURI, cfg.MongoOptions())
+	})
+	if err != nil {
+		fatal("failed to connect to mongodb", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	// Inisialisasi repository MySQL sebagai tujuan sinkronisasi
+	mysqlDB, err := database.ConnectWithRetry(ctx, "mysql", cfg.ConnectRetry(), func(ctx context.Context) (*sql.DB, error) {
+		return database.NewMySQLConnection(ctx, cfg.MySQLDSN, cfg.MySQLOptions())
+	})
+	if err != nil {
+		fatal("failed to connect to mysql", err)
+	}
+	defer mysqlDB.Close()
+	mysqlRepo := repositories.NewMySQLProductRepository(mysqlDB)
+
+	mongoDatabase := mongoClient.Database(cfg.MongoDatabaseName)
+	tokenStore := repositories.NewMongoResumeTokenStore(mongoDatabase.Collection("sync_resume_tokens"))
+	syncer := repositories.NewMongoChangeStreamSyncer(mongoDatabase.Collection("products"), mysqlRepo, tokenStore, cfg.ChangeStreamSyncName)
+
+	// Jalankan consumer sampai proses dihentikan
+	if err := syncer.Run(ctx); err != nil {
+		fatal("sync failed", err)
+	}
+	slog.Info("sync stopped")
+}
+
+// Mencatat error lalu menghentikan proses
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Interval refresh JWKS
+const (
+	// Key set dimuat ulang secara berkala agar rotasi key terbaca
+	jwksRefreshInterval = 10 * time.Minute
+	// Batas minimum antar refresh saat token memakai kid yang belum dikenal
+	jwksMinRefreshInterval = 30 * time.Second
+)
+
+// Key set RSA dari dokumen JWKS (file lokal atau endpoint HTTP).
+// Dokumen diambil di luar lock sehingga verifikasi token lain tidak ikut menunggu,
+// dan refresh yang bersamaan digabung menjadi satu pengambilan (singleflight).
+type JWKSKeySet struct {
+	fetch func(ctx context.Context) ([]byte, error)
+	group singleflight.Group
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	refreshedAt time.Time
+}
+
+// Satu key di dalam dokumen JWKS
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Membuat key set dari file JWKS
+func NewJWKSFileKeySet(path string) *JWKSKeySet {
+	return &JWKSKeySet{
+		fetch: func(ctx context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+}
+
+// Membuat key set dari endpoint JWKS
+func NewJWKSURLKeySet(url string, client *http.Client) *JWKSKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &JWKSKeySet{
+		fetch: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("jwks endpoint returned %s", resp.Status)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		},
+	}
+}
+
+// Memuat key set pertama kali, dipakai saat startup agar konfigurasi yang salah langsung terlihat
+func (s *JWKSKeySet) Load(ctx context.Context) error {
+	return s.refresh(ctx)
+}
+
+// Mengambil public key berdasarkan kid.
+// Jika kid belum dikenal, key set dimuat ulang (dibatasi jwksMinRefreshInterval).
+func (s *JWKSKeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	keys, refreshedAt := s.snapshot()
+	if keys == nil || time.Since(refreshedAt) > jwksRefreshInterval {
+		if err := s.refresh(ctx); err != nil && keys == nil {
+			return nil, err
+		}
+		keys, refreshedAt = s.snapshot()
+	}
+	if key := lookup(keys, kid); key != nil {
+		return key, nil
+	}
+	if time.Since(refreshedAt) > jwksMinRefreshInterval {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+		keys, _ = s.snapshot()
+		if key := lookup(keys, kid); key != nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// Key aktif beserta waktu refresh terakhir
+func (s *JWKSKeySet) snapshot() (map[string]*rsa.PublicKey, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys, s.refreshedAt
+}
+
+// Token tanpa kid hanya diterima jika key set berisi satu key
+func lookup(keys map[string]*rsa.PublicKey, kid string) *rsa.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// Mengambil dan mem-parsing dokumen JWKS tanpa memegang lock, lalu mengganti key di bawah
+// write lock. Pengambilan tidak ikut dibatalkan oleh request yang memicunya karena hasilnya
+// dibagikan ke pemanggil lain; endpoint HTTP tetap dibatasi timeout client.
+func (s *JWKSKeySet) refresh(ctx context.Context) error {
+	_, err, _ := s.group.Do("jwks", func() (interface{}, error) {
+		s.mu.Lock()
+		s.refreshedAt = time.Now()
+		s.mu.Unlock()
+
+		data, err := s.fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		keys, err := ParseJWKS(data)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.keys = keys
+		s.mu.Unlock()
+		return nil, nil
+	})
+	return err
+}
+
+// Membaca key RSA dari dokumen JWKS, key dengan tipe lain diabaikan
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range doc.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwks key %q: %w", jwk.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwks key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Konfigurasi verifikasi JWT
+type JWTConfig struct {
+	// Secret untuk token HS256, kosong berarti HS256 tidak diterima
+	HS256Secret []byte
+
+	// Key set untuk token RS256, nil berarti RS256 tidak diterima
+	KeySet *JWKSKeySet
+
+	// Nilai claim iss yang wajib, kosong berarti tidak diperiksa
+	Issuer string
+
+	// Nilai claim aud yang wajib, kosong berarti tidak diperiksa
+	Audience string
+
+	// Toleransi selisih jam untuk exp/nbf/iat
+	Leeway time.Duration
+
+	// Nama claim tenant, kosong berarti "tenant_id"
+	TenantClaim string
+}
+
+// Verifier JWT untuk token HS256 dan RS256
+type JWTVerifier struct {
+	config  JWTConfig
+	methods []string
+}
+
+// Membuat instance baru dari JWTVerifier
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	var methods []string
+	if len(config.HS256Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if config.KeySet != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("jwt: no HS256 secret or JWKS configured")
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant_id"
+	}
+	return &JWTVerifier{config: config, methods: methods}, nil
+}
+
+// Memverifikasi token dan mengembalikan principal dari claim sub, scope dan roles
+func (v *JWTVerifier) VerifyToken(ctx context.Context, tokenString string) (*domain.Principal, error) {
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.config.Leeway),
+	}
+	if v.config.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.config.Issuer))
+	}
+	if v.config.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			return v.config.HS256Secret, nil
+		case jwt.SigningMethodRS256.Alg():
+			kid, _ := token.Header["kid"].(string)
+			return v.config.KeySet.Key(ctx, kid)
+		default:
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	}, parserOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	tenant, _ := claims[v.config.TenantClaim].(string)
+	if tenant != "" && !domain.ValidTenantID(tenant) {
+		return nil, domain.ErrInvalidTenant
+	}
+	return &domain.Principal{
+		Subject:  subject,
+		Scopes:   stringListClaim(claims, "scope", "scp", "scopes"),
+		Roles:    stringListClaim(claims, "roles"),
+		Method:   domain.AuthMethodJWT,
+		TenantID: tenant,
+	}, nil
+}
+
+// Membaca claim berupa string dipisah spasi (format OAuth2) atau array string.
+// Nama claim pertama yang ada yang dipakai.
+func stringListClaim(claims jwt.MapClaims, names ...string) []string {
+	for _, name := range names {
+		switch value := claims[name].(type) {
+		case string:
+			return strings.Fields(value)
+		case []interface{}:
+			values := make([]string, 0, len(value))
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache yang disimpan di server dengan protokol Redis
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// Membuat instance baru dari RedisCache, semua key diberi prefix agar tidak bentrok
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Mengambil nilai dari Redis
+func (c *RedisCache) Get(key string) ([]byte, bool, error) {
+	value, err := c.client.Get(context.TODO(), c.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Menyimpan nilai ke Redis, ttl <= 0 berarti tidak kedaluwarsa
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return c.client.Set(context.TODO(), c.prefix+key, value, ttl).Err()
+}
+
+// Menghapus key dari Redis
+func (c *RedisCache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(context.TODO(), prefixed...).Err()
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk pengelolaan API key
+type APIKeyHandler struct {
+	apiKeyService ports.APIKeyService
+}
+
+// Membuat instance baru dari APIKeyHandler
+func NewAPIKeyHandler(apiKeyService ports.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Membuat API key baru, key asli hanya dikembalikan di response ini
+func (h *APIKeyHandler) IssueAPIKey(c *fiber.Ctx) error {
+	var request domain.APIKeyRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	issued, err := h.apiKeyService.IssueAPIKey(c.UserContext(), request)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+// Mendapatkan semua API key
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyService.ListAPIKeys(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(keys)
+}
+
+// Mengganti key rahasia dari API key
+func (h *APIKeyHandler) RotateAPIKey(c *fiber.Ctx) error {
+	issued, err := h.apiKeyService.RotateAPIKey(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "API key not found"})
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(issued)
+}
+
+// Mencabut API key
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := h.apiKeyService.RevokeAPIKey(c.UserContext(), c.Params("id")); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "API key not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "API key revoked successfully"})
+}
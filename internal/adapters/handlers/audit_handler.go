@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk audit log produk
+type AuditHandler struct {
+	auditService ports.AuditService
+}
+
+// Membuat instance baru dari AuditHandler
+func NewAuditHandler(auditService ports.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// Mendapatkan riwayat audit satu produk
+func (h *AuditHandler) ListProductAudit(c *fiber.Ctx) error {
+	entries, err := h.auditService.ListProductAudit(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(entries)
+}
+
+// Mencari audit log dengan parameter actor, from, to (RFC 3339) dan limit
+func (h *AuditHandler) QueryAudit(c *fiber.Ctx) error {
+	query := domain.AuditQuery{
+		Actor: c.Query("actor"),
+		Limit: c.QueryInt("limit"),
+	}
+
+	// Parse rentang waktu
+	var err error
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid from: " + err.Error()})
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid to: " + err.Error()})
+		}
+	}
+
+	entries, err := h.auditService.QueryAudit(c.UserContext(), query)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(entries)
+}
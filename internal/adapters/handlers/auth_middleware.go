@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header yang membawa API key
+const HeaderAPIKey = "X-API-Key"
+
+// Scope (atau role) untuk endpoint /admin
+const ScopeAdmin = domain.ScopeAdmin
+
+// Scope (atau role) untuk principal tanpa claim tenant yang boleh memilih tenant
+// melalui header X-Tenant-ID atau subdomain
+const ScopeCrossTenant = domain.ScopeCrossTenant
+
+// Realm pada header WWW-Authenticate
+const authRealm = "api"
+
+// Middleware autentikasi dengan bearer token (JWT) atau header X-API-Key.
+// Metode yang tidak dikonfigurasi (nil) tidak diterima. Principal hasil verifikasi
+// disimpan ke context service dan subject-nya menjadi actor.
+func AuthMiddleware(verifier ports.TokenVerifier, apiKeys ports.APIKeyAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var principal *domain.Principal
+		var err error
+
+		if key := c.Get(HeaderAPIKey); key != "" && apiKeys != nil {
+			principal, err = apiKeys.AuthenticateAPIKey(c.UserContext(), key)
+			if err != nil {
+				if !errors.Is(err, domain.ErrInvalidAPIKey) {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+				}
+				c.Set(fiber.HeaderWWWAuthenticate, `APIKey realm="`+authRealm+`", error="invalid_key"`)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+			}
+		} else if verifier != nil {
+			token, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+			if !ok {
+				return unauthorized(c, "", "missing bearer token")
+			}
+			principal, err = verifier.VerifyToken(c.UserContext(), token)
+			if err != nil {
+				return unauthorized(c, "invalid_token", err.Error())
+			}
+		} else {
+			c.Set(fiber.HeaderWWWAuthenticate, `APIKey realm="`+authRealm+`"`)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing api key"})
+		}
+
+		c.SetUserContext(domain.ContextWithPrincipal(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// Middleware yang mewajibkan principal memiliki scope atau role tertentu
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := domain.PrincipalFromContext(c.UserContext())
+		if principal == nil {
+			return unauthorized(c, "", "authentication required")
+		}
+		if !principal.HasScope(scope) && !principal.HasRole(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "missing scope " + scope})
+		}
+		return c.Next()
+	}
+}
+
+// Mengambil token dari header "Authorization: Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// Response 401 dengan header WWW-Authenticate sesuai RFC 6750
+func unauthorized(c *fiber.Ctx, errorCode, description string) error {
+	challenge := `Bearer realm="` + authRealm + `"`
+	if errorCode != "" {
+		challenge += `, error="` + errorCode + `", error_description="` + strings.ReplaceAll(description, `"`, `'`) + `"`
+	}
+	c.Set(fiber.HeaderWWWAuthenticate, challenge)
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": description})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk melihat dan me-reload konfigurasi aktif
+type ConfigHandler struct {
+	manager *config.Manager
+}
+
+// Membuat instance baru dari ConfigHandler
+func NewConfigHandler(manager *config.Manager) *ConfigHandler {
+	return &ConfigHandler{
+		manager: manager,
+	}
+}
+
+// Menampilkan versi, konfigurasi aktif (secret disamarkan) dan riwayat reload
+func (h *ConfigHandler) GetConfig(c *fiber.Ctx) error {
+	status, err := h.manager.Status()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(status)
+}
+
+// Membaca ulang konfigurasi, 422 jika konfigurasi baru tidak valid dan konfigurasi lama tetap dipakai
+func (h *ConfigHandler) ReloadConfig(c *fiber.Ctx) error {
+	event := h.manager.Reload(config.TriggerManual)
+	if event.Status == config.ReloadRejected {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(event)
+	}
+	return c.JSON(event)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk endpoint liveness dan readiness
+type HealthHandler struct {
+	healthService ports.HealthService
+}
+
+// Membuat instance baru dari HealthHandler
+func NewHealthHandler(healthService ports.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Liveness: proses berjalan, tidak memeriksa dependency
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readiness: 200 jika semua dependency tersedia atau degraded, 503 jika dependency kritis
+// tidak tersedia atau service sedang berhenti
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	report := h.healthService.Readiness(c.UserContext())
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	if !report.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+	return c.JSON(report)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Nama route untuk konfigurasi Cache-Control
+const (
+	RouteGetProduct   = "products.get"
+	RouteListProducts = "products.list"
+)
+
+// Opsi tambahan untuk ProductHandler
+type ProductHandlerOption func(*ProductHandler)
+
+// Mengatur nilai header Cache-Control per route (lihat RouteGetProduct, RouteListProducts)
+func WithCacheControl(cacheControl map[string]string) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		for route, value := range cacheControl {
+			h.cacheControl[route] = value
+		}
+	}
+}
+
+// Mengatur nilai header Cache-Control per route untuk tenant tertentu,
+// route yang tidak diatur memakai nilai dari WithCacheControl
+func WithTenantCacheControl(tenantCacheControl map[string]map[string]string) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		for tenant, cacheControl := range tenantCacheControl {
+			routes := make(map[string]string, len(cacheControl))
+			for route, value := range cacheControl {
+				routes[route] = value
+			}
+			h.tenantCacheControl[tenant] = routes
+		}
+	}
+}
+
+// Nilai Cache-Control untuk route, override tenant didahulukan
+func (h *ProductHandler) cacheControlFor(c *fiber.Ctx, route string) string {
+	tenant := domain.TenantFromContext(c.UserContext())
+	if value, ok := h.tenantCacheControl[tenant][route]; ok {
+		return value
+	}
+	return h.cacheControl[route]
+}
+
+// Mengirim body JSON dengan validator HTTP caching (ETag strong dan Last-Modified).
+// Jika validator dari request cocok, response 304 Not Modified dikirim tanpa body.
+func (h *ProductHandler) sendCacheable(c *fiber.Ctx, route string, body interface{}, lastModified time.Time) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// ETag strong dihitung dari representasi JSON yang dikirim
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Set(fiber.HeaderETag, etag)
+	// Response stale sudah diberi Cache-Control no-store oleh setReadSourceHeaders
+	if value := h.cacheControlFor(c, route); value != "" && c.GetRespHeader(HeaderDataStale) == "" {
+		c.Set(fiber.HeaderCacheControl, value)
+	}
+	// Header HTTP hanya memiliki presisi detik
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(data)
+}
+
+// Mengevaluasi If-None-Match dan If-Modified-Since sesuai RFC 9110.
+// If-Modified-Since diabaikan jika request juga mengirim If-None-Match.
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince)
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+// If-None-Match memakai perbandingan weak, jadi prefix W/ diabaikan
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header Idempotency-Key dan penanda response yang dikirim ulang
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+)
+
+// Panjang maksimum Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// Masa berlaku record pending, agar key tidak terkunci selamanya jika proses berhenti
+// sebelum response tersimpan. Selama request masih diproses, masa berlakunya diperpanjang
+// setiap idempotencyLockRefresh sehingga penulisan yang lambat (write_timeout, retry)
+// tidak membuat key bisa dipakai ulang sebelum selesai.
+const (
+	idempotencyLockTTL     = time.Minute
+	idempotencyLockRefresh = idempotencyLockTTL / 3
+)
+
+// Middleware Idempotency-Key untuk endpoint penulisan (misalnya POST /api/products).
+// Request pertama dengan sebuah key diproses dan response-nya disimpan selama ttl;
+// request berikutnya dengan key dan payload yang sama menerima response yang sama
+// tanpa diproses ulang. Payload berbeda ditolak dengan 422, dan request yang masih
+// diproses ditolak dengan 409. Response 5xx tidak disimpan agar request bisa diulang,
+// kecuali jika service sudah menyimpan perubahan (domain.RecordWriteCommitted).
+// Key berlaku per tenant dan per client, request tanpa header diteruskan apa adanya.
+func IdempotencyMiddleware(store ports.IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return sendProblem(c, Problem{
+				Title:  "Bad Request",
+				Status: fiber.StatusBadRequest,
+				Detail: "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		ctx := c.UserContext()
+		storeKey := domain.TenantFromContext(ctx) + ":" + clientIdentity(c) + ":" + key
+		fingerprint := requestFingerprint(c)
+
+		existing, reserved, err := store.Reserve(ctx, storeKey, &domain.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Pending:     true,
+			CreatedAt:   time.Now().UTC(),
+		}, idempotencyLockTTL)
+		if err != nil {
+			// Tanpa penyimpanan key, request tidak bisa dijamin tidak terduplikasi
+			slog.ErrorContext(ctx, "failed to reserve idempotency key", "error", err)
+			return sendProblem(c, Problem{
+				Title:  "Service Unavailable",
+				Status: fiber.StatusServiceUnavailable,
+				Detail: "idempotency store unavailable",
+			})
+		}
+		if !reserved {
+			return replayIdempotent(c, existing, fingerprint)
+		}
+
+		writeCtx, write := domain.ContextWithWriteRecord(ctx)
+		c.SetUserContext(writeCtx)
+		stopRefresh := refreshIdempotencyLock(ctx, store, storeKey)
+		err = c.Next()
+		stopRefresh()
+		if err != nil {
+			if !write.Committed {
+				releaseIdempotencyKey(c, store, storeKey)
+				return err
+			}
+			// Response error dibuat di sini agar bisa disimpan seperti response lainnya
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		// Mengulang request setelah perubahan tersimpan bisa membuat duplikat,
+		// sehingga response 5xx hanya dilepas jika belum ada yang tersimpan
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError && !write.Committed {
+			releaseIdempotencyKey(c, store, storeKey)
+			return nil
+		}
+		record := &domain.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        bytes.Clone(c.Response().Body()),
+			CreatedAt:   time.Now().UTC(),
+		}
+		if err := store.Complete(ctx, storeKey, record, ttl); err != nil {
+			slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+		}
+		return nil
+	}
+}
+
+// Mengirim ulang response tersimpan, atau menolak request yang tidak cocok
+func replayIdempotent(c *fiber.Ctx, record *domain.IdempotencyRecord, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		return sendProblem(c, Problem{
+			Title:  "Unprocessable Entity",
+			Status: fiber.StatusUnprocessableEntity,
+			Detail: "Idempotency-Key was already used with a different request payload",
+		})
+	}
+	if record.Pending {
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return sendProblem(c, Problem{
+			Title:  "Conflict",
+			Status: fiber.StatusConflict,
+			Detail: "a request with this Idempotency-Key is still being processed",
+		})
+	}
+
+	c.Set(HeaderIdempotencyReplayed, "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.Status).Send(record.Body)
+}
+
+// Memperpanjang record pending secara berkala sampai stop dipanggil. stop menunggu
+// perpanjangan terakhir selesai agar tidak menimpa masa berlaku response yang disimpan.
+func refreshIdempotencyLock(ctx context.Context, store ports.IdempotencyStore, key string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(idempotencyLockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.Extend(ctx, key, idempotencyLockTTL); err != nil {
+					slog.WarnContext(ctx, "failed to extend idempotency key", "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// Menghapus key agar request bisa diulang
+func releaseIdempotencyKey(c *fiber.Ctx, store ports.IdempotencyStore, key string) {
+	if err := store.Release(c.UserContext(), key); err != nil {
+		slog.ErrorContext(c.UserContext(), "failed to release idempotency key", "error", err)
+	}
+}
+
+// Hash dari method, URL dan body request
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"log/slog"
+	"net/netip"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Header yang membawa identitas actor dari gateway/client
+const HeaderActorID = "X-Actor-ID"
+
+// Middleware untuk meneruskan identitas actor ke context service saat autentikasi nonaktif.
+// Header X-Actor-ID hanya diterima dari gateway pada jaringan trustedProxies, berdasarkan
+// alamat koneksi dan bukan X-Forwarded-For. Request lain tetap memakai actor anonymous.
+func ActorMiddleware(trustedProxies []netip.Prefix) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := c.Get(HeaderActorID)
+		if actor != "" && trustedRemote(c, trustedProxies) {
+			c.SetUserContext(domain.ContextWithActor(c.UserContext(), actor))
+		}
+		return c.Next()
+	}
+}
+
+// Memeriksa apakah alamat koneksi request berada di salah satu jaringan yang dipercaya
+func trustedRemote(c *fiber.Ctx, trusted []netip.Prefix) bool {
+	if len(trusted) == 0 {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(c.Context().RemoteIP())
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format X-Request-ID dari client yang diterima, selain itu ID baru dibuat
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware ID request: memakai header X-Request-ID dari client jika formatnya valid,
+// selain itu membuat ID baru. ID dikirim kembali di header response.
+func RequestIDMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = utils.UUIDv4()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+		return c.Next()
+	}
+}
+
+// Middleware log akses terstruktur, satu baris per request setelah response selesai.
+// Field request_id, tenant dan actor ditambahkan oleh logger dari context.
+func RequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(c.UserContext(), level, "http request",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("ip", c.IP()),
+		)
+		return err
+	}
+}
+
+// Middleware untuk meneruskan ID request dan IP asal ke context service.
+// Dipasang setelah RequestIDMiddleware agar ID request sudah tersedia.
+func RequestInfoMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(domain.ContextWithRequestInfo(c.UserContext(), domain.RequestInfo{
+			RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+			SourceIP:  c.IP(),
+		}))
+		return c.Next()
+	}
+}
+
+// Identitas client untuk rate limit dan Idempotency-Key:
+// principal jika sudah diautentikasi, selain itu IP
+func clientIdentity(c *fiber.Ctx) string {
+	if principal := domain.PrincipalFromContext(c.UserContext()); principal != nil {
+		return principal.Method + ":" + principal.Subject
+	}
+	return "ip:" + c.IP()
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Content type untuk response error RFC 7807
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Nilai Retry-After (detik) saat store sementara tidak tersedia
+const storeUnavailableRetryAfter = "5"
+
+// Response error RFC 7807
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// Permission yang tidak dimiliki pemanggil (untuk 403)
+	MissingPermission string `json:"missing_permission,omitempty"`
+}
+
+// Mengirim response problem+json
+func sendProblem(c *fiber.Ctx, problem Problem) error {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	c.Status(problem.Status)
+	return c.JSON(problem, MIMEApplicationProblemJSON)
+}
+
+// Mengambil PermissionError dari error service, nil jika bukan error permission
+func permissionError(err error) *domain.PermissionError {
+	var permErr *domain.PermissionError
+	if errors.As(err, &permErr) {
+		return permErr
+	}
+	return nil
+}
+
+// Mengirim 403 dengan permission yang tidak dimiliki pemanggil
+func sendForbidden(c *fiber.Ctx, permErr *domain.PermissionError) error {
+	return sendProblem(c, Problem{
+		Title:             "Forbidden",
+		Status:            fiber.StatusForbidden,
+		Detail:            permErr.Error(),
+		MissingPermission: permErr.Permission,
+	})
+}
+
+// Mengirim 503 saat store sementara tidak tersedia (circuit breaker terbuka atau bulkhead penuh)
+func sendUnavailable(c *fiber.Ctx, err error) error {
+	c.Set(fiber.HeaderRetryAfter, storeUnavailableRetryAfter)
+	return sendProblem(c, Problem{
+		Title:  "Service Unavailable",
+		Status: fiber.StatusServiceUnavailable,
+		Detail: err.Error(),
+	})
+}
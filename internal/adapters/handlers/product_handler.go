@@ -1,32 +1,138 @@
 package handlers
 
 import (
+	"errors"
 	"go-fiber-hexagonal-product/internal/core/domain"
 	"go-fiber-hexagonal-product/internal/core/ports"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // Handler untuk produk
 type ProductHandler struct {
-	productService ports.ProductService
+	productService  ports.ProductService
+	revisionService ports.RevisionService
+	cacheControl    map[string]string
+
+	// Override Cache-Control per tenant, lalu per route
+	tenantCacheControl map[string]map[string]string
+}
+
+// Mengaktifkan parameter ?as_of= pada GetProduct
+func WithRevisionService(revisionService ports.RevisionService) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.revisionService = revisionService
+	}
 }
 
 // Membuat instance baru dari ProductHandler
-func NewProductHandler(productService ports.ProductService) *ProductHandler {
-	return &ProductHandler{
-		productService: productService,
+func NewProductHandler(productService ports.ProductService, opts ...ProductHandlerOption) *ProductHandler {
+	h := &ProductHandler{
+		productService:     productService,
+		cacheControl:       make(map[string]string),
+		tenantCacheControl: make(map[string]map[string]string),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Mendapatkan produk berdasarkan ID
 func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	id := c.Params("id")
-	product, err := h.productService.GetProduct(id)
+	if asOf := c.Query("as_of"); asOf != "" {
+		return h.getProductAsOf(c, id, asOf)
+	}
+	ctx, source := domain.ContextWithReadSource(c.UserContext())
+	product, err := h.productService.GetProduct(ctx, id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrStoreUnavailable) {
+			return sendUnavailable(c, err)
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
-	return c.JSON(product)
+	if product == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+	}
+	setReadSourceHeaders(c, source)
+	return h.sendCacheable(c, RouteGetProduct, product, product.UpdatedAt)
+}
+
+// Mendapatkan produk berdasarkan SKU
+func (h *ProductHandler) GetProductBySKU(c *fiber.Ctx) error {
+	return h.findProduct(c, domain.LookupSKU, c.Params("sku"))
+}
+
+// Mendapatkan produk berdasarkan barcode GTIN-8, UPC-A, EAN-13 atau GTIN-14
+func (h *ProductHandler) GetProductByBarcode(c *fiber.Ctx) error {
+	return h.findProduct(c, domain.LookupBarcode, c.Params("code"))
+}
+
+// Mendapatkan produk berdasarkan slug
+func (h *ProductHandler) GetProductBySlug(c *fiber.Ctx) error {
+	return h.findProduct(c, domain.LookupSlug, c.Params("slug"))
+}
+
+// Mencari produk berdasarkan identifier selain ID, memakai Cache-Control yang sama dengan GetProduct
+func (h *ProductHandler) findProduct(c *fiber.Ctx, lookup domain.ProductLookup, value string) error {
+	ctx, source := domain.ContextWithReadSource(c.UserContext())
+	product, err := h.productService.FindProduct(ctx, lookup, value)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrStoreUnavailable) {
+			return sendUnavailable(c, err)
+		}
+		if errors.Is(err, domain.ErrInvalidProduct) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	setReadSourceHeaders(c, source)
+	return h.sendCacheable(c, RouteGetProduct, product, product.UpdatedAt)
+}
+
+// Response 409 untuk ID, SKU, barcode atau slug yang sudah dipakai produk lain
+func sendProductExists(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Product with the same id, sku, barcode or slug already exists"})
+}
+
+// Mendapatkan kondisi produk pada waktu tertentu (RFC 3339) dari riwayat revisi
+func (h *ProductHandler) getProductAsOf(c *fiber.Ctx, id, asOf string) error {
+	if h.revisionService == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "as_of is not supported"})
+	}
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid as_of: " + err.Error()})
+	}
+
+	product, err := h.revisionService.GetProductAsOf(c.UserContext(), id, at)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrStoreUnavailable) {
+			return sendUnavailable(c, err)
+		}
+		if errors.Is(err, domain.ErrRevisionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return h.sendCacheable(c, RouteGetProduct, product, product.UpdatedAt)
 }
 
 // Membuat produk baru
@@ -35,7 +141,16 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 	if err := c.BodyParser(product); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
-	if err := h.productService.CreateProduct(product); err != nil {
+	if err := h.productService.CreateProduct(c.UserContext(), product); err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrInvalidProductID) || errors.Is(err, domain.ErrInvalidProduct) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrProductExists) {
+			return sendProductExists(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	// Return product with status 201 Created
@@ -56,9 +171,17 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	product.ID = id
 
 	// Pastikan kita tidak mengubah field _id saat update
-	if err := h.productService.UpdateProduct(product); err != nil {
-		// Periksa apakah error disebabkan karena produk tidak ditemukan
-		if err.Error() == "product not found" {
+	if err := h.productService.UpdateProduct(c.UserContext(), product); err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrInvalidProduct) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrProductExists) {
+			return sendProductExists(c)
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
@@ -71,9 +194,11 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 // Menghapus produk berdasarkan ID
 func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.productService.DeleteProduct(id); err != nil {
-		// Periksa apakah error disebabkan karena produk tidak ditemukan
-		if err.Error() == "product not found" {
+	if err := h.productService.DeleteProduct(c.UserContext(), id); err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
@@ -83,8 +208,21 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 
 // Mendapatkan daftar produk
 func (h *ProductHandler) ListProducts(c *fiber.Ctx) error {
-	products, err := h.productService.ListProducts()
+	// Parameter sort, misalnya ?sort=-updated_at
+	opts, err := domain.ParseProductSort(c.Query("sort"))
 	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx, source := domain.ContextWithReadSource(c.UserContext())
+	products, err := h.productService.ListProducts(ctx, opts)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrStoreUnavailable) {
+			return sendUnavailable(c, err)
+		}
 		if err.Error() == "products not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Products not found",
@@ -100,5 +238,8 @@ func (h *ProductHandler) ListProducts(c *fiber.Ctx) error {
 		products = []*domain.Product{}
 	}
 
-	return c.JSON(products)
-}
\ No newline at end of file
+	// Daftar produk hanya memakai ETag: penghapusan produk tidak menggeser perubahan terbaru
+	// di antara produknya, sehingga Last-Modified dan If-Modified-Since bisa menghasilkan 304 yang basi
+	setReadSourceHeaders(c, source)
+	return h.sendCacheable(c, RouteListProducts, products, time.Time{})
+}
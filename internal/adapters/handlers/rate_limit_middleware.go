@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header rate limit (draft IETF RateLimit header fields)
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// Konfigurasi rate limit, batas yang tidak diisi tidak diperiksa
+type RateLimitConfig struct {
+	// Batas per client (API key, subject token atau IP) untuk request baca dan tulis
+	Read  domain.RateLimit
+	Write domain.RateLimit
+
+	// Batas gabungan semua client dalam satu tenant
+	TenantRead  domain.RateLimit
+	TenantWrite domain.RateLimit
+}
+
+// Middleware rate limit token bucket. GET/HEAD/OPTIONS memakai budget baca, method lain
+// budget tulis. Harus dipasang setelah AuthMiddleware dan TenantMiddleware agar client
+// dan tenant sudah diketahui. Token hanya diambil jika bucket client dan tenant sama-sama
+// masih memiliki token. Jika backend rate limit bermasalah, request tetap diteruskan.
+func RateLimitMiddleware(limiter ports.RateLimiter, config RateLimitConfig) fiber.Handler {
+	return DynamicRateLimitMiddleware(limiter, func() RateLimitConfig { return config })
+}
+
+// Sama seperti RateLimitMiddleware, tetapi batas dibaca setiap request
+// sehingga perubahan konfigurasi (hot reload) langsung berlaku
+func DynamicRateLimitMiddleware(limiter ports.RateLimiter, currentConfig func() RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		config := currentConfig()
+		budget, clientLimit, tenantLimit := "write", config.Write, config.TenantWrite
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			budget, clientLimit, tenantLimit = "read", config.Read, config.TenantRead
+		}
+
+		var buckets []domain.RateLimitBucket
+		if clientLimit.Enabled() {
+			buckets = append(buckets, domain.RateLimitBucket{Key: "client:" + budget + ":" + clientIdentity(c), Limit: clientLimit})
+		}
+		if tenantLimit.Enabled() {
+			buckets = append(buckets, domain.RateLimitBucket{Key: "tenant:" + budget + ":" + domain.TenantFromContext(c.UserContext()), Limit: tenantLimit})
+		}
+		return checkRateLimit(c, limiter, budget, buckets)
+	}
+}
+
+// Middleware rate limit per alamat IP untuk semua method. Dipasang sebelum AuthMiddleware
+// agar request dengan kredensial tidak valid juga dibatasi sebelum diperiksa.
+func IPRateLimitMiddleware(limiter ports.RateLimiter, currentLimit func() domain.RateLimit) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := currentLimit()
+		if !limit.Enabled() {
+			return c.Next()
+		}
+		return checkRateLimit(c, limiter, "ip", []domain.RateLimitBucket{{Key: "ip:" + c.IP(), Limit: limit}})
+	}
+}
+
+// Mengambil token dari semua bucket lalu mengirim header RateLimit-* atau response 429
+func checkRateLimit(c *fiber.Ctx, limiter ports.RateLimiter, budget string, buckets []domain.RateLimitBucket) error {
+	if len(buckets) == 0 {
+		return c.Next()
+	}
+	ctx := c.UserContext()
+	results, err := limiter.AllowAll(ctx, buckets)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit check failed", "budget", budget, "error", err)
+		return c.Next()
+	}
+
+	// Header dikirim dari bucket yang paling ketat
+	var reported *domain.RateLimitResult
+	var reportedLimit domain.RateLimit
+	var denied bool
+	for i := range results {
+		res := &results[i]
+		if !res.Allowed && (!denied || res.RetryAfter > reported.RetryAfter) {
+			denied = true
+			reported, reportedLimit = res, buckets[i].Limit
+		} else if !denied && (reported == nil || res.Remaining < reported.Remaining) {
+			reported, reportedLimit = res, buckets[i].Limit
+		}
+	}
+
+	c.Set(HeaderRateLimitLimit, strconv.Itoa(reported.Limit))
+	c.Set(HeaderRateLimitRemaining, strconv.Itoa(reported.Remaining))
+	c.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(reported.Reset)))
+	c.Set(HeaderRateLimitPolicy, strconv.Itoa(reportedLimit.Limit)+";w="+strconv.Itoa(ceilSeconds(reportedLimit.Period)))
+	if !denied {
+		return c.Next()
+	}
+
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, ceilSeconds(reported.RetryAfter))))
+	return sendProblem(c, Problem{
+		Title:  "Too Many Requests",
+		Status: fiber.StatusTooManyRequests,
+		Detail: budget + " rate limit exceeded",
+	})
+}
+
+// Durasi dalam detik, dibulatkan ke atas
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/internal/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header sumber data pembacaan produk
+const (
+	// Store yang melayani pembacaan: mongodb, mysql atau cache
+	HeaderDataSource = "X-Data-Source"
+	// "true" jika data dibaca dari store sekunder dan mungkin tertinggal
+	HeaderDataStale = "X-Data-Stale"
+)
+
+// Nilai header Warning untuk response stale (RFC 7234)
+const staleWarning = `110 - "Response is Stale"`
+
+// Menandai response dengan sumber datanya. Response stale dari store sekunder
+// tidak boleh disimpan oleh cache client maupun proxy.
+func setReadSourceHeaders(c *fiber.Ctx, source *domain.ReadSource) {
+	if source.Source == "" {
+		return
+	}
+	c.Set(HeaderDataSource, source.Source)
+	if source.Stale {
+		c.Set(HeaderDataStale, "true")
+		c.Set(fiber.HeaderWarning, staleWarning)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler untuk revisi produk
+type RevisionHandler struct {
+	revisionService ports.RevisionService
+}
+
+// Membuat instance baru dari RevisionHandler
+func NewRevisionHandler(revisionService ports.RevisionService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+// Mendapatkan semua revisi produk
+func (h *RevisionHandler) ListRevisions(c *fiber.Ctx) error {
+	revisions, err := h.revisionService.ListRevisions(c.UserContext(), c.Params("id"))
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(revisions)
+}
+
+// Mengembalikan produk ke kondisi pada revisi tertentu
+func (h *RevisionHandler) RestoreRevision(c *fiber.Ctx) error {
+	revision, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || revision < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid revision"})
+	}
+
+	product, err := h.revisionService.RestoreRevision(c.UserContext(), c.Params("id"), revision)
+	if err != nil {
+		if permErr := permissionError(err); permErr != nil {
+			return sendForbidden(c, permErr)
+		}
+		if errors.Is(err, domain.ErrRevisionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Revision not found"})
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(product)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header untuk memilih tenant
+const HeaderTenantID = "X-Tenant-ID"
+
+// Konfigurasi resolusi tenant per request
+type TenantResolverConfig struct {
+	// Domain dasar storefront, misalnya "shop.example.com" sehingga
+	// "acme.shop.example.com" menjadi tenant "acme". Kosong berarti subdomain tidak dipakai.
+	BaseDomain string
+
+	// Tenant yang diterima selain DefaultTenant, kosong berarti semua tenant ID yang valid diterima
+	Tenants []string
+}
+
+// Middleware yang menentukan tenant request dan menyimpannya ke context service.
+// Claim tenant dari principal (token atau API key) selalu dipakai jika ada; header
+// X-Tenant-ID atau subdomain yang menunjuk tenant lain ditolak dengan 403.
+// Principal tanpa claim tenant hanya berlaku untuk DefaultTenant, kecuali memiliki
+// ScopeCrossTenant. Principal tersebut dan request tanpa autentikasi memakai header,
+// lalu subdomain, lalu DefaultTenant.
+func TenantMiddleware(config TenantResolverConfig) fiber.Handler {
+	var known map[string]bool
+	if len(config.Tenants) > 0 {
+		known = map[string]bool{domain.DefaultTenant: true}
+		for _, tenant := range config.Tenants {
+			known[tenant] = true
+		}
+	}
+	baseDomain := strings.ToLower(strings.Trim(config.BaseDomain, "."))
+
+	return func(c *fiber.Ctx) error {
+		requested := strings.TrimSpace(c.Get(HeaderTenantID))
+		if requested == "" && baseDomain != "" {
+			requested = subdomainTenant(c.Hostname(), baseDomain)
+		}
+
+		tenant := requested
+		if principal := domain.PrincipalFromContext(c.UserContext()); principal != nil {
+			// Tenant yang boleh diakses principal, kosong berarti tenant mana pun
+			if allowed := principal.AllowedTenant(); allowed != "" {
+				if requested != "" && requested != allowed {
+					return sendProblem(c, Problem{
+						Title:  "Forbidden",
+						Status: fiber.StatusForbidden,
+						Detail: "credentials are not valid for tenant " + requested,
+					})
+				}
+				tenant = allowed
+			}
+		}
+		if tenant == "" {
+			tenant = domain.DefaultTenant
+		}
+
+		if !domain.ValidTenantID(tenant) {
+			return sendProblem(c, Problem{
+				Title:  "Bad Request",
+				Status: fiber.StatusBadRequest,
+				Detail: domain.ErrInvalidTenant.Error(),
+			})
+		}
+		if known != nil && !known[tenant] {
+			return sendProblem(c, Problem{
+				Title:  "Not Found",
+				Status: fiber.StatusNotFound,
+				Detail: "unknown tenant " + tenant,
+			})
+		}
+
+		c.SetUserContext(domain.ContextWithTenant(c.UserContext(), tenant))
+		return c.Next()
+	}
+}
+
+// Mengambil label subdomain tepat di bawah baseDomain, kosong jika host tidak cocok
+func subdomainTenant(host, baseDomain string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	label, ok := strings.CutSuffix(host, "."+baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
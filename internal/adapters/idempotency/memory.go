@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"sync"
+	"time"
+)
+
+// Penyimpanan Idempotency-Key di memory proses, hanya berlaku per instance aplikasi
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+}
+
+type memoryRecord struct {
+	record    domain.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// Membuat instance baru dari MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]memoryRecord),
+	}
+}
+
+// Menyimpan record pending jika key belum ada atau sudah kedaluwarsa
+func (s *MemoryStore) Reserve(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if existing, ok := s.records[key]; ok {
+		stored := existing.record
+		return &stored, false, nil
+	}
+	s.records[key] = memoryRecord{record: *record, expiresAt: now.Add(ttl)}
+	return nil, true, nil
+}
+
+// Memperpanjang masa berlaku key yang belum kedaluwarsa
+func (s *MemoryStore) Extend(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if existing, ok := s.records[key]; ok && now.Before(existing.expiresAt) {
+		existing.expiresAt = now.Add(ttl)
+		s.records[key] = existing
+	}
+	return nil
+}
+
+// Menyimpan response akhir untuk key
+func (s *MemoryStore) Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = memoryRecord{record: *record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Menghapus key
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// Menghapus record yang sudah kedaluwarsa
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, record := range s.records {
+		if !now.Before(record.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
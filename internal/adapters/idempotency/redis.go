@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Penyimpanan Idempotency-Key di server dengan protokol Redis, berlaku untuk semua instance aplikasi
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// Membuat instance baru dari RedisStore, semua key diberi prefix agar tidak bentrok
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Menyimpan record pending dengan SET NX
+func (s *RedisStore) Reserve(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+	reserved, err := s.client.SetNX(ctx, s.prefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if reserved {
+		return nil, true, nil
+	}
+
+	stored, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Key kedaluwarsa di antara SET NX dan GET, coba lagi
+			return s.Reserve(ctx, key, record, ttl)
+		}
+		return nil, false, err
+	}
+	var existing domain.IdempotencyRecord
+	if err := json.Unmarshal(stored, &existing); err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// Memperpanjang masa berlaku key, PEXPIRE tidak membuat key yang sudah tidak ada
+func (s *RedisStore) Extend(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.PExpire(ctx, s.prefix+key, ttl).Err()
+}
+
+// Menyimpan response akhir untuk key
+func (s *RedisStore) Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+key, data, ttl).Err()
+}
+
+// Menghapus key
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
@@ -0,0 +1,16 @@
+package idgen
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Pembuat ID berupa ObjectID MongoDB dalam bentuk hex, format ID produk sebelum strategi ID bisa dipilih
+type ObjectIDGenerator struct{}
+
+// Membuat instance baru dari ObjectIDGenerator
+func NewObjectIDGenerator() *ObjectIDGenerator {
+	return &ObjectIDGenerator{}
+}
+
+// Membuat ObjectID baru
+func (g *ObjectIDGenerator) NewID() string {
+	return primitive.NewObjectID().Hex()
+}
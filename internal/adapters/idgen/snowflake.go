@@ -0,0 +1,67 @@
+package idgen
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Awal hitungan timestamp Snowflake
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+
+	// Node ID maksimum (1023)
+	MaxSnowflakeNodeID = 1<<snowflakeNodeBits - 1
+
+	maxSnowflakeSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// Pembuat ID Snowflake: 41 bit milidetik sejak 2024-01-01, 10 bit node ID dan
+// 12 bit urutan per milidetik, ditulis sebagai angka desimal.
+// Setiap instance aplikasi wajib memakai node ID yang berbeda.
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+}
+
+// Membuat instance baru dari SnowflakeGenerator
+func NewSnowflakeGenerator(nodeID int64) (*SnowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > MaxSnowflakeNodeID {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d (got %d)", MaxSnowflakeNodeID, nodeID)
+	}
+	return &SnowflakeGenerator{nodeID: nodeID}, nil
+}
+
+// Membuat ID Snowflake baru
+func (g *SnowflakeGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := time.Since(snowflakeEpoch).Milliseconds()
+	if ms < g.lastMs {
+		// Jam mundur, tetap memakai milidetik terakhir agar ID tidak berulang
+		ms = g.lastMs
+	}
+	if ms == g.lastMs {
+		g.sequence = (g.sequence + 1) & maxSnowflakeSequence
+		if g.sequence == 0 {
+			// Urutan habis, tunggu milidetik berikutnya
+			for ms <= g.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				ms = time.Since(snowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = ms
+
+	id := ms<<(snowflakeNodeBits+snowflakeSequenceBits) | g.nodeID<<snowflakeSequenceBits | g.sequence
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,70 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// Alfabet Crockford base32 yang dipakai ULID
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Pembuat ID ULID: 48 bit timestamp milidetik dan 80 bit acak dalam 26 karakter base32.
+// ID yang dibuat pada milidetik yang sama tetap terurut karena bagian acaknya dinaikkan.
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+// Membuat instance baru dari ULIDGenerator
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{}
+}
+
+// Membuat ULID baru
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	if ms > g.lastMs || !g.increment() {
+		if ms < g.lastMs {
+			ms = g.lastMs
+		}
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			panic(err)
+		}
+	}
+	g.lastMs = ms
+
+	// 128 bit ULID: timestamp di 48 bit teratas, entropy di 80 bit terbawah
+	hi := ms<<16 | uint64(binary.BigEndian.Uint16(g.entropy[:2]))
+	lo := binary.BigEndian.Uint64(g.entropy[2:])
+
+	var id [26]byte
+	for i := range id {
+		// Setiap karakter mewakili 5 bit, dimulai dari bit ke-125
+		shift := uint(125 - 5*i)
+		var value uint64
+		if shift >= 64 {
+			value = hi >> (shift - 64)
+		} else {
+			value = lo>>shift | hi<<(64-shift)
+		}
+		id[i] = crockfordAlphabet[value&31]
+	}
+	return string(id[:])
+}
+
+// Menaikkan entropy sebesar satu, false jika entropy sudah maksimum
+func (g *ULIDGenerator) increment() bool {
+	for i := len(g.entropy) - 1; i >= 0; i-- {
+		g.entropy[i]++
+		if g.entropy[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
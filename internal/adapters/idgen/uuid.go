@@ -0,0 +1,16 @@
+package idgen
+
+import "github.com/google/uuid"
+
+// Pembuat ID UUIDv7 (RFC 9562): diawali timestamp milidetik sehingga terurut berdasarkan waktu
+type UUIDv7Generator struct{}
+
+// Membuat instance baru dari UUIDv7Generator
+func NewUUIDv7Generator() *UUIDv7Generator {
+	return &UUIDv7Generator{}
+}
+
+// Membuat UUIDv7 baru
+func (g *UUIDv7Generator) NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
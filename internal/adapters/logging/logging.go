@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Nilai pengganti untuk field sensitif
+const Redacted = "[REDACTED]"
+
+// Bagian nama field yang dianggap sensitif (tidak peka huruf besar/kecil)
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie", "api_key", "apikey", "dsn"}
+
+// Konfigurasi logger
+type Options struct {
+	// Level minimum: debug, info, warn atau error
+	Level string
+
+	// Format output: json atau text
+	Format string
+
+	// Level yang bisa diubah saat berjalan (hot reload), nil berarti level tetap
+	LevelVar *slog.LevelVar
+}
+
+// Membuat logger slog dengan field request dari context dan redaksi field sensitif
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	handlerOpts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}
+	if opts.LevelVar != nil {
+		opts.LevelVar.Set(level)
+		handlerOpts.Level = opts.LevelVar
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	case "text":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", opts.Format)
+	}
+	return slog.New(NewContextHandler(handler)), nil
+}
+
+// Membaca level log dari teks
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("unknown log level %q", value)
+	}
+	return level, nil
+}
+
+// Handler yang menambahkan request_id, tenant, actor serta trace_id/span_id dari context
+// ke setiap log yang ditulis dengan method *Context selama request berjalan
+type ContextHandler struct {
+	slog.Handler
+}
+
+// Membuat instance baru dari ContextHandler
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+// Menambahkan field request lalu meneruskan record ke handler di bawahnya
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if info := domain.RequestInfoFromContext(ctx); info.RequestID != "" {
+		record.AddAttrs(
+			slog.String("request_id", info.RequestID),
+			slog.String("tenant", domain.TenantFromContext(ctx)),
+			slog.String("actor", domain.ActorFromContext(ctx)),
+		)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Mengganti nilai field sensitif dengan Redacted
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+// Memeriksa apakah nama field termasuk sensitif
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
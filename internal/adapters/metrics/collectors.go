@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Mendaftarkan statistik connection pool sql.DB (go_sql_*) dengan label db_name
+func (m *Metrics) RegisterDBStats(name string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Collector cache hit/miss yang membaca statistik saat scrape
+type cacheStatsCollector struct {
+	stats func() domain.CacheStats
+	desc  *prometheus.Desc
+}
+
+// Mendaftarkan jumlah cache hit, miss dan error pembacaan produk
+func (m *Metrics) RegisterCacheStats(stats func() domain.CacheStats) error {
+	return m.registry.Register(&cacheStatsCollector{
+		stats: stats,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "requests_total"),
+			"Product cache lookups by result (hit, miss or error).",
+			[]string{"result"}, nil,
+		),
+	})
+}
+
+func (c *cacheStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *cacheStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.Hits), "hit")
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.Misses), "miss")
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.Errors), "error")
+}
+
+// Collector metric bisnis produk per tenant yang menghitung ulang saat scrape
+type productStatsCollector struct {
+	repo              ports.ProductStatsRepository
+	lowStockThreshold int
+	timeout           time.Duration
+	metrics           *Metrics
+	total             *prometheus.Desc
+	lowStock          *prometheus.Desc
+}
+
+// Mendaftarkan gauge jumlah produk dan produk dengan stok di bawah lowStockThreshold per tenant.
+// Query dijalankan setiap scrape dan dibatasi timeout, jika gagal gauge tidak dikirim pada scrape tersebut.
+func (m *Metrics) RegisterProductStats(repo ports.ProductStatsRepository, lowStockThreshold int, timeout time.Duration) error {
+	return m.registry.Register(&productStatsCollector{
+		repo:              repo,
+		lowStockThreshold: lowStockThreshold,
+		timeout:           timeout,
+		metrics:           m,
+		total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "products", "total"),
+			"Number of products per tenant.",
+			[]string{"tenant"}, nil,
+		),
+		lowStock: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "products", "low_stock"),
+			"Number of products per tenant with stock below the low-stock threshold.",
+			[]string{"tenant"}, prometheus.Labels{"threshold": strconv.Itoa(lowStockThreshold)},
+		),
+	})
+}
+
+func (c *productStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.total
+	ch <- c.lowStock
+}
+
+func (c *productStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(domain.ContextWithAllTenants(context.Background()), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	stats, err := c.repo.ProductStats(ctx, c.lowStockThreshold)
+	c.metrics.observeRepository(StoreMongo, "ProductStats", start, err)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to collect product stats", "error", err)
+		return
+	}
+	for _, tenant := range stats {
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(tenant.Total), tenant.TenantID)
+		ch <- prometheus.MustNewConstMetric(c.lowStock, prometheus.GaugeValue, float64(tenant.LowStock), tenant.TenantID)
+	}
+}
+
+// Status circuit breaker yang dilaporkan sebagai label
+var circuitStates = []domain.CircuitState{domain.CircuitClosed, domain.CircuitOpen, domain.CircuitHalfOpen}
+
+// Collector circuit breaker, retry dan bulkhead per store yang membaca statistik saat scrape
+type resilienceCollector struct {
+	policies   []ports.ResiliencePolicy
+	state      *prometheus.Desc
+	inFlight   *prometheus.Desc
+	retries    *prometheus.Desc
+	rejections *prometheus.Desc
+}
+
+// Mendaftarkan status circuit breaker, panggilan berjalan, retry dan penolakan setiap store
+func (m *Metrics) RegisterResilienceStats(policies ...ports.ResiliencePolicy) error {
+	return m.registry.Register(&resilienceCollector{
+		policies: policies,
+		state: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "circuit_breaker", "state"),
+			"Circuit breaker state per store, 1 for the current state.",
+			[]string{"store", "state"}, nil,
+		),
+		inFlight: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "repository", "in_flight_calls"),
+			"Repository calls currently holding a bulkhead slot.",
+			[]string{"store"}, nil,
+		),
+		retries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "repository", "retries_total"),
+			"Repository calls retried after a transient error.",
+			[]string{"store"}, nil,
+		),
+		rejections: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "repository", "rejected_calls_total"),
+			"Repository calls rejected without reaching the store, by reason (circuit_open or bulkhead_full).",
+			[]string{"store", "reason"}, nil,
+		),
+	})
+}
+
+func (c *resilienceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.inFlight
+	ch <- c.retries
+	ch <- c.rejections
+}
+
+func (c *resilienceCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, policy := range c.policies {
+		stats := policy.Stats()
+		for _, state := range circuitStates {
+			value := 0.0
+			if stats.State == state {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, value, stats.Store, string(state))
+		}
+		ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(stats.InFlight), stats.Store)
+		ch <- prometheus.MustNewConstMetric(c.retries, prometheus.CounterValue, float64(stats.Retries), stats.Store)
+		ch <- prometheus.MustNewConstMetric(c.rejections, prometheus.CounterValue, float64(stats.ShortCircuits), stats.Store, "circuit_open")
+		ch <- prometheus.MustNewConstMetric(c.rejections, prometheus.CounterValue, float64(stats.BulkheadRejections), stats.Store, "bulkhead_full")
+	}
+}
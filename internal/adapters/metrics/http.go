@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Label route untuk request yang tidak cocok dengan route mana pun
+const unmatchedRoute = "unmatched"
+
+// Middleware untuk mencatat jumlah dan latensi request per route dan status.
+// Route memakai pola yang terdaftar (misalnya /api/products/:id) agar jumlah label tetap terbatas.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		route := c.Route().Path
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+			// Fiber mengembalikan fiber.ErrNotFound jika tidak ada route yang cocok,
+			// route saat itu masih milik middleware terakhir
+			if status == fiber.StatusNotFound {
+				route = unmatchedRoute
+			}
+		}
+		labels := []string{c.Method(), route, strconv.Itoa(status)}
+		m.httpRequests.WithLabelValues(labels...).Inc()
+		m.httpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
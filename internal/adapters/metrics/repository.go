@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Label store untuk metric repository
+const (
+	StoreMongo = "mongo"
+	StoreMySQL = "mysql"
+)
+
+// Mencatat latensi satu operasi repository, error not found tidak dihitung sebagai error
+func (m *Metrics) observeRepository(store, operation string, start time.Time, err error) {
+	m.repositoryDuration.WithLabelValues(store, operation).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) && !errors.Is(err, sql.ErrNoRows) &&
+		!errors.Is(err, domain.ErrProductNotFound) {
+		m.repositoryErrors.WithLabelValues(store, operation).Inc()
+	}
+}
+
+// Decorator MongoProductRepository yang mencatat latensi dan error setiap operasi
+type mongoProductRepository struct {
+	next    ports.MongoProductRepository
+	metrics *Metrics
+}
+
+// Membungkus repository produk MongoDB dengan metric operasi
+func (m *Metrics) InstrumentMongoProductRepository(next ports.MongoProductRepository) ports.MongoProductRepository {
+	return &mongoProductRepository{next: next, metrics: m}
+}
+
+func (r *mongoProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	start := time.Now()
+	product, err := r.next.GetProduct(ctx, id)
+	r.metrics.observeRepository(StoreMongo, "GetProduct", start, err)
+	return product, err
+}
+
+func (r *mongoProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	start := time.Now()
+	product, err := r.next.FindProduct(ctx, lookup, value)
+	r.metrics.observeRepository(StoreMongo, "FindProduct", start, err)
+	return product, err
+}
+
+func (r *mongoProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (string, error) {
+	start := time.Now()
+	id, err := r.next.CreateProduct(ctx, product)
+	r.metrics.observeRepository(StoreMongo, "CreateProduct", start, err)
+	return id, err
+}
+
+func (r *mongoProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	start := time.Now()
+	err := r.next.UpdateProduct(ctx, product)
+	r.metrics.observeRepository(StoreMongo, "UpdateProduct", start, err)
+	return err
+}
+
+func (r *mongoProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.DeleteProduct(ctx, id)
+	r.metrics.observeRepository(StoreMongo, "DeleteProduct", start, err)
+	return err
+}
+
+func (r *mongoProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	start := time.Now()
+	products, err := r.next.ListProducts(ctx, opts)
+	r.metrics.observeRepository(StoreMongo, "ListProducts", start, err)
+	return products, err
+}
+
+// Decorator MySQLProductRepository yang mencatat latensi dan error setiap operasi
+type mysqlProductRepository struct {
+	next    ports.MySQLProductRepository
+	metrics *Metrics
+}
+
+// Membungkus repository produk MySQL dengan metric operasi
+func (m *Metrics) InstrumentMySQLProductRepository(next ports.MySQLProductRepository) ports.MySQLProductRepository {
+	return &mysqlProductRepository{next: next, metrics: m}
+}
+
+func (r *mysqlProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	start := time.Now()
+	product, err := r.next.GetProduct(ctx, id)
+	r.metrics.observeRepository(StoreMySQL, "GetProduct", start, err)
+	return product, err
+}
+
+func (r *mysqlProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	start := time.Now()
+	product, err := r.next.FindProduct(ctx, lookup, value)
+	r.metrics.observeRepository(StoreMySQL, "FindProduct", start, err)
+	return product, err
+}
+
+func (r *mysqlProductRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	start := time.Now()
+	err := r.next.CreateProduct(ctx, product)
+	r.metrics.observeRepository(StoreMySQL, "CreateProduct", start, err)
+	return err
+}
+
+func (r *mysqlProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	start := time.Now()
+	err := r.next.UpdateProduct(ctx, product)
+	r.metrics.observeRepository(StoreMySQL, "UpdateProduct", start, err)
+	return err
+}
+
+func (r *mysqlProductRepository) SaveProduct(ctx context.Context, product *domain.Product) error {
+	start := time.Now()
+	err := r.next.SaveProduct(ctx, product)
+	r.metrics.observeRepository(StoreMySQL, "SaveProduct", start, err)
+	return err
+}
+
+func (r *mysqlProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.DeleteProduct(ctx, id)
+	r.metrics.observeRepository(StoreMySQL, "DeleteProduct", start, err)
+	return err
+}
+
+func (r *mysqlProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	start := time.Now()
+	products, err := r.next.ListProducts(ctx, opts)
+	r.metrics.observeRepository(StoreMySQL, "ListProducts", start, err)
+	return products, err
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"math"
+	"time"
+)
+
+// Mengisi ulang token sesuai waktu yang berlalu sejak pembaruan terakhir
+func refill(tokens float64, elapsed time.Duration, limit domain.RateLimit) float64 {
+	if elapsed <= 0 {
+		return tokens
+	}
+	tokens += float64(elapsed) * float64(limit.Limit) / float64(limit.Period)
+	return math.Min(tokens, float64(limit.Limit))
+}
+
+// Menyusun hasil dari jumlah token setelah request
+func result(allowed bool, tokens float64, limit domain.RateLimit) domain.RateLimitResult {
+	perToken := float64(limit.Period) / float64(limit.Limit)
+	res := domain.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((float64(limit.Limit) - tokens) * perToken)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration(math.Ceil((1 - tokens) * perToken))
+	}
+	return res
+}
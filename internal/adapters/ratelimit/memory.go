@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"sync"
+	"time"
+)
+
+// Jumlah pemanggilan Allow di antara pembersihan bucket yang sudah penuh
+const memorySweepInterval = 1024
+
+// Rate limiter token bucket di memory proses, hanya berlaku per instance aplikasi
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// Membuat instance baru dari MemoryRateLimiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+// Mengambil satu token dari bucket key
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	results, err := l.AllowAll(ctx, []domain.RateLimitBucket{{Key: key, Limit: limit}})
+	if err != nil {
+		return domain.RateLimitResult{}, err
+	}
+	return results[0], nil
+}
+
+// Mengambil satu token dari setiap bucket jika semua bucket masih memiliki token
+func (l *MemoryRateLimiter) AllowAll(ctx context.Context, buckets []domain.RateLimitBucket) ([]domain.RateLimitResult, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%memorySweepInterval == 0 {
+		l.sweep(now)
+	}
+
+	// Semua bucket diisi ulang dan diperiksa sebelum token diambil dari salah satunya
+	states := make([]*memoryBucket, len(buckets))
+	allowed := true
+	for i, bucket := range buckets {
+		b, ok := l.buckets[bucket.Key]
+		if !ok {
+			b = &memoryBucket{tokens: float64(bucket.Limit.Limit), updated: now}
+			l.buckets[bucket.Key] = b
+		}
+		b.tokens = refill(b.tokens, now.Sub(b.updated), bucket.Limit)
+		b.updated = now
+		b.period = bucket.Limit.Period
+		states[i] = b
+		allowed = allowed && b.tokens >= 1
+	}
+
+	results := make([]domain.RateLimitResult, len(buckets))
+	for i, b := range states {
+		if allowed {
+			b.tokens--
+		}
+		results[i] = result(allowed || b.tokens >= 1, b.tokens, buckets[i].Limit)
+	}
+	return results, nil
+}
+
+// Menghapus bucket yang sudah terisi penuh, hasilnya sama dengan bucket baru
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Jumlah bucket yang sedang disimpan
+func (l *MemoryRateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
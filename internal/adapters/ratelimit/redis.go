@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Script token bucket yang dijalankan atomik di server untuk satu atau beberapa bucket.
+// KEYS = key bucket, ARGV = waktu sekarang (ms) lalu kapasitas dan periode (ms) setiap bucket.
+// Token hanya diambil jika semua bucket masih memiliki token.
+// Token disimpan sebagai string agar pecahan tidak dibulatkan oleh Redis.
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens = {}
+local updated = {}
+local allowed = 1
+for i, key in ipairs(KEYS) do
+  local capacity = tonumber(ARGV[i * 2])
+  local period = tonumber(ARGV[i * 2 + 1])
+  local state = redis.call('HMGET', key, 'tokens', 'updated')
+  local current = tonumber(state[1])
+  local last = tonumber(state[2])
+  if current == nil or last == nil then
+    current = capacity
+    last = now
+  end
+  if now > last then
+    current = math.min(capacity, current + (now - last) * capacity / period)
+  end
+  if current < 1 then
+    allowed = 0
+  end
+  tokens[i] = current
+  updated[i] = math.max(now, last)
+end
+local result = {allowed}
+for i, key in ipairs(KEYS) do
+  if allowed == 1 then
+    tokens[i] = tokens[i] - 1
+  end
+  redis.call('HSET', key, 'tokens', tostring(tokens[i]), 'updated', tostring(updated[i]))
+  redis.call('PEXPIRE', key, tonumber(ARGV[i * 2 + 1]))
+  result[i + 1] = tostring(tokens[i])
+end
+return result
+`)
+
+// Rate limiter token bucket di server dengan protokol Redis, berlaku untuk semua instance aplikasi
+type RedisRateLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// Membuat instance baru dari RedisRateLimiter, semua key diberi prefix agar tidak bentrok
+func NewRedisRateLimiter(client *redis.Client, prefix string) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Mengambil satu token dari bucket key
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	results, err := l.AllowAll(ctx, []domain.RateLimitBucket{{Key: key, Limit: limit}})
+	if err != nil {
+		return domain.RateLimitResult{}, err
+	}
+	return results[0], nil
+}
+
+// Mengambil satu token dari setiap bucket jika semua bucket masih memiliki token
+func (l *RedisRateLimiter) AllowAll(ctx context.Context, buckets []domain.RateLimitBucket) ([]domain.RateLimitResult, error) {
+	keys := make([]string, len(buckets))
+	args := []interface{}{time.Now().UnixMilli()}
+	for i, bucket := range buckets {
+		keys[i] = l.prefix + bucket.Key
+		args = append(args, bucket.Limit.Limit, bucket.Limit.Period.Milliseconds())
+	}
+	values, err := tokenBucketScript.Run(ctx, l.client, keys, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, _ := values[0].(int64)
+	results := make([]domain.RateLimitResult, len(buckets))
+	for i, bucket := range buckets {
+		tokensValue, _ := values[i+1].(string)
+		tokens, err := strconv.ParseFloat(tokensValue, 64)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result(allowed == 1 || tokens >= 1, tokens, bucket.Limit)
+	}
+	return results, nil
+}
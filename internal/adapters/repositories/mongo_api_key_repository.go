@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository API key di MongoDB
+type MongoAPIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// Membuat instance baru dari MongoAPIKeyRepository
+func NewMongoAPIKeyRepository(collection *mongo.Collection) *MongoAPIKeyRepository {
+	return &MongoAPIKeyRepository{
+		collection: collection,
+	}
+}
+
+// Membuat index unik untuk lookup prefix
+func (r *MongoAPIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "prefix", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Menyimpan API key baru
+func (r *MongoAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		return err
+	}
+	key.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+// Mendapatkan API key berdasarkan ID
+func (r *MongoAPIKeyRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// Mendapatkan API key berdasarkan prefix
+func (r *MongoAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return r.findOne(ctx, bson.M{"prefix": prefix})
+}
+
+// Mendapatkan semua API key, diurutkan dari yang terbaru
+func (r *MongoAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := make([]*domain.APIKey, 0)
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Mengganti prefix dan hash API key
+func (r *MongoAPIKeyRepository) RotateAPIKey(ctx context.Context, id, prefix, hash string, rotatedAt time.Time) error {
+	return r.update(ctx, id, bson.M{"$set": bson.M{"prefix": prefix, "hash": hash, "rotated_at": rotatedAt}})
+}
+
+// Mencabut API key
+func (r *MongoAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	return r.update(ctx, id, bson.M{"$set": bson.M{"revoked_at": revokedAt}})
+}
+
+// Mencatat waktu terakhir API key dipakai
+func (r *MongoAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	return r.update(ctx, id, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+}
+
+func (r *MongoAPIKeyRepository) findOne(ctx context.Context, filter bson.M) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.collection.FindOne(ctx, filter).Decode(&key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *MongoAPIKeyRepository) update(ctx context.Context, id string, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrAPIKeyNotFound
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
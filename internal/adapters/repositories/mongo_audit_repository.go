@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Batas jumlah catatan audit per query
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// Repository audit log produk di MongoDB.
+// Repository ini hanya melakukan insert dan find; untuk menjamin append-only di sisi
+// database, user aplikasi sebaiknya hanya diberi hak insert dan find pada koleksi audit.
+type MongoAuditRepository struct {
+	collection *mongo.Collection
+}
+
+// Membuat instance baru dari MongoAuditRepository
+func NewMongoAuditRepository(collection *mongo.Collection) *MongoAuditRepository {
+	return &MongoAuditRepository{
+		collection: collection,
+	}
+}
+
+// Membuat index untuk query audit per tenant: per produk, per actor dan per waktu
+func (r *MongoAuditRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "actor", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	return err
+}
+
+// Menambahkan catatan audit baru
+func (r *MongoAuditRepository) AppendAudit(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.TenantID = writeTenant(ctx, entry.TenantID)
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// Mencari catatan audit sesuai filter, diurutkan dari yang terbaru
+func (r *MongoAuditRepository) ListAudit(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	filter := withTenantFilter(ctx, bson.M{})
+	if query.ProductID != "" {
+		filter["product_id"] = query.ProductID
+	}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	timestamp := bson.M{}
+	if !query.From.IsZero() {
+		timestamp["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		timestamp["$lte"] = query.To
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := make([]*domain.AuditEntry, 0)
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
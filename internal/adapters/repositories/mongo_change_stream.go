@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jenis operasi change stream yang diteruskan ke MySQL
+const (
+	ChangeOperationInsert  = "insert"
+	ChangeOperationUpdate  = "update"
+	ChangeOperationReplace = "replace"
+	ChangeOperationDelete  = "delete"
+)
+
+// Jeda sebelum membuka ulang change stream setelah terjadi error, berlipat dua
+// setiap kali stream gagal dibuka sampai batas maksimum
+const (
+	changeStreamMinRetryDelay = time.Second
+	changeStreamMaxRetryDelay = 2 * time.Minute
+)
+
+// Kode error server MongoDB jika resume token sudah tidak bisa dipakai
+const (
+	mongoErrInvalidResumeToken      = 260
+	mongoErrChangeStreamFatal       = 280
+	mongoErrChangeStreamHistoryLost = 286
+)
+
+// Perubahan produk yang dibaca dari change stream MongoDB
+type ProductChange struct {
+	// Jenis operasi (insert, update, replace, delete)
+	Operation string
+
+	// ID produk dari documentKey
+	ProductID string
+
+	// Dokumen produk lengkap, nil untuk delete
+	Product *domain.Product
+}
+
+// Event change stream MongoDB yang relevan untuk sinkronisasi
+type productChangeEvent struct {
+	OperationType string          `bson:"operationType"`
+	FullDocument  *domain.Product `bson:"fullDocument"`
+	DocumentKey   struct {
+		ID string `bson:"_id"`
+	} `bson:"documentKey"`
+}
+
+// Consumer change stream koleksi produk MongoDB yang menerapkan
+// setiap perubahan ke repository MySQL. Perubahan yang ditulis langsung
+// ke MongoDB (tanpa melalui ProductService) ikut tersinkron ke MySQL.
+//
+// Change stream membutuhkan MongoDB dalam mode replica set.
+type MongoChangeStreamSyncer struct {
+	collection *mongo.Collection
+	target     ports.MySQLProductRepository
+	tokens     ports.ResumeTokenStore
+	name       string
+
+	// Sinkronisasi ulang semua produk tertunda karena resume token hilang
+	resyncPending bool
+}
+
+// Membuat instance baru dari MongoChangeStreamSyncer
+func NewMongoChangeStreamSyncer(collection *mongo.Collection, target ports.MySQLProductRepository, tokens ports.ResumeTokenStore, name string) *MongoChangeStreamSyncer {
+	return &MongoChangeStreamSyncer{
+		collection: collection,
+		target:     target,
+		tokens:     tokens,
+		name:       name,
+	}
+}
+
+// Menjalankan consumer sampai context dibatalkan.
+// Jika change stream terputus, consumer membuka ulang stream dari resume token terakhir
+// dengan jeda yang bertambah secara eksponensial. Jika resume token sudah keluar dari oplog,
+// token dihapus dan semua produk disinkronkan ulang ke MySQL.
+func (s *MongoChangeStreamSyncer) Run(ctx context.Context) error {
+	delay := changeStreamMinRetryDelay
+	for {
+		opened, err := s.watch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if IsChangeStreamHistoryLost(err) {
+			slog.ErrorContext(ctx, "change stream resume token lost, resyncing all products to mysql", "name", s.name, "error", err)
+			if err := s.tokens.DeleteToken(s.name); err != nil {
+				slog.ErrorContext(ctx, "delete change stream resume token failed", "name", s.name, "error", err)
+			} else {
+				// Stream dibuka ulang tanpa token secepatnya
+				s.resyncPending = true
+				opened = true
+			}
+		}
+		if opened {
+			delay = changeStreamMinRetryDelay
+		}
+		slog.WarnContext(ctx, "change stream disconnected", "name", s.name, "error", err, "retry_in", delay)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, changeStreamMaxRetryDelay)
+	}
+}
+
+// Membuka change stream dan memproses event sampai terjadi error.
+// opened bernilai true jika stream sempat terbuka dan siap memproses event.
+func (s *MongoChangeStreamSyncer) watch(ctx context.Context) (opened bool, err error) {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	// Lanjutkan dari resume token terakhir jika ada
+	token, err := s.tokens.LoadToken(s.name)
+	if err != nil {
+		return false, err
+	}
+	if token != nil {
+		opts.SetResumeAfter(bson.Raw(token))
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{
+			ChangeOperationInsert, ChangeOperationUpdate, ChangeOperationReplace, ChangeOperationDelete,
+		}}}}},
+	}
+	stream, err := s.collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close(context.Background())
+
+	// Stream dibuka sebelum sinkronisasi ulang, sehingga perubahan selama sinkronisasi tetap diterapkan
+	if s.resyncPending {
+		if err := s.Resync(ctx); err != nil {
+			return false, err
+		}
+		s.resyncPending = false
+		if token := stream.ResumeToken(); token != nil {
+			if err := s.tokens.SaveToken(s.name, token); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "change stream started", "name", s.name)
+	for stream.Next(ctx) {
+		var event productChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			return true, err
+		}
+
+		change := ProductChange{
+			Operation: event.OperationType,
+			ProductID: event.DocumentKey.ID,
+			Product:   event.FullDocument,
+		}
+		if err := s.ApplyChange(ctx, change); err != nil {
+			// Token tidak disimpan agar event ini diproses ulang saat stream dibuka kembali
+			return true, err
+		}
+
+		// Simpan posisi terakhir setelah perubahan berhasil diterapkan
+		if err := s.tokens.SaveToken(s.name, stream.ResumeToken()); err != nil {
+			return true, err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return true, err
+	}
+	return true, errors.New("change stream closed")
+}
+
+// Menyinkronkan ulang semua produk MongoDB ke MySQL
+func (s *MongoChangeStreamSyncer) Resync(ctx context.Context) error {
+	ctx = domain.ContextWithAllTenants(ctx)
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var products []*domain.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return err
+	}
+	return s.ReconcileProducts(ctx, products)
+}
+
+// Menyamakan MySQL dengan daftar produk MongoDB: setiap produk disimpan,
+// dan produk MySQL yang tidak ada di daftar dihapus
+func (s *MongoChangeStreamSyncer) ReconcileProducts(ctx context.Context, products []*domain.Product) error {
+	ctx = domain.ContextWithAllTenants(ctx)
+	ids := make(map[string]struct{}, len(products))
+	for _, product := range products {
+		ids[product.ID] = struct{}{}
+		if err := s.target.SaveProduct(ctx, product); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.target.ListProducts(ctx, domain.ProductListOptions{})
+	if err != nil {
+		return err
+	}
+	deleted := 0
+	for _, product := range existing {
+		if _, ok := ids[product.ID]; ok {
+			continue
+		}
+		if err := s.target.DeleteProduct(ctx, product.ID); err != nil {
+			return err
+		}
+		deleted++
+	}
+	slog.InfoContext(ctx, "products resynced to mysql", "name", s.name, "saved", len(products), "deleted", deleted)
+	return nil
+}
+
+// Memeriksa apakah error change stream disebabkan resume token yang sudah tidak ada di oplog
+func IsChangeStreamHistoryLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(mongoErrChangeStreamHistoryLost) ||
+		serverErr.HasErrorCode(mongoErrInvalidResumeToken) ||
+		serverErr.HasErrorCode(mongoErrChangeStreamFatal)
+}
+
+// Menerapkan satu perubahan produk ke repository MySQL.
+// Consumer bekerja lintas tenant: tenant diambil dari dokumen produk,
+// dan delete cukup berdasarkan ID karena ID produk unik di semua tenant.
+func (s *MongoChangeStreamSyncer) ApplyChange(ctx context.Context, change ProductChange) error {
+	ctx = domain.ContextWithAllTenants(ctx)
+	switch change.Operation {
+	case ChangeOperationInsert, ChangeOperationUpdate, ChangeOperationReplace:
+		if change.Product == nil {
+			// Dokumen sudah dihapus sebelum update lookup, event delete akan menyusul
+			return nil
+		}
+		if change.Product.ID == "" {
+			change.Product.ID = change.ProductID
+		}
+		return s.target.SaveProduct(ctx, change.Product)
+	case ChangeOperationDelete:
+		return s.target.DeleteProduct(ctx, change.ProductID)
+	default:
+		return nil
+	}
+}
@@ -2,13 +2,16 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-fiber-hexagonal-product/internal/core/domain"
-	"log"
-	"time" // Package ini digunakan untuk mengukur durasi/kecepatan koneksi ke MongoDB
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // Repository produk MongoDB
@@ -23,101 +26,171 @@ func NewMongoProductRepository(collection *mongo.Collection) *MongoProductReposi
 	}
 }
 
+// Membuat index untuk query produk per tenant
+func (r *MongoProductRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+		uniqueIdentifierIndex("sku"),
+		uniqueIdentifierIndex("barcode"),
+		uniqueIdentifierIndex("slug"),
+	})
+	return err
+}
+
+// Index unik per tenant untuk SKU, barcode atau slug. Produk tanpa identifier tersebut
+// tidak menyimpan field-nya sehingga tidak ikut diindex.
+func uniqueIdentifierIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: field, Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{field: bson.M{"$exists": true}}),
+	}
+}
+
+// Field dokumen untuk setiap jenis pencarian produk
+var mongoLookupFields = map[domain.ProductLookup]string{
+	domain.LookupSKU:     "sku",
+	domain.LookupBarcode: "barcode",
+	domain.LookupSlug:    "slug",
+}
+
+// Memeriksa koneksi ke primary MongoDB untuk readiness
+func (r *MongoProductRepository) Ping(ctx context.Context) error {
+	return r.collection.Database().Client().Ping(ctx, readpref.Primary())
+}
+
 // Mendapatkan produk berdasarkan ID
-func (r *MongoProductRepository) GetProduct(id string) (*domain.Product, error) {
-	start := time.Now() // Mulai pengukuran waktu
+func (r *MongoProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
 	var product domain.Product
-	objectID, err := primitive.ObjectIDFromHex(id)
+	// Mengambil produk dari MongoDB berdasarkan ID
+	err := r.collection.FindOne(ctx, withTenantFilter(ctx, bson.M{"_id": productIDFilter(id)})).Decode(&product)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, id)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// Mengambil produk dari MongoDB berdasarkan ID
-	err = r.collection.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&product)
+	return &product, nil
+}
+
+// Mendapatkan produk berdasarkan SKU, barcode atau slug
+func (r *MongoProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	field, ok := mongoLookupFields[lookup]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product lookup %q", lookup)
+	}
+	var product domain.Product
+	err := r.collection.FindOne(ctx, withTenantFilter(ctx, bson.M{field: value})).Decode(&product)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: %s %s", domain.ErrProductNotFound, lookup, value)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// Menghitung durasi waktu yang dihabiskan untuk query
-	log.Printf("GetProduct duration: %v", time.Since(start)) // Waktu yang diambil dari awal hingga selesai query
 	return &product, nil
 }
 
-// Membuat produk baru
-func (r *MongoProductRepository) CreateProduct(product *domain.Product) (string, error) {
-	start := time.Now() // Mulai pengukuran waktu
+// Membuat produk baru. ID disimpan sebagai string, ID kosong diisi dengan ObjectID hex.
+func (r *MongoProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (string, error) {
+	product.TenantID = writeTenant(ctx, product.TenantID)
+	if product.ID == "" {
+		product.ID = primitive.NewObjectID().Hex()
+	} else if objectID, err := primitive.ObjectIDFromHex(product.ID); err == nil {
+		// Produk lama menyimpan _id sebagai ObjectID sehingga tidak dicegah oleh index _id
+		count, err := r.collection.CountDocuments(ctx, bson.M{"_id": objectID}, options.Count().SetLimit(1))
+		if err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return "", fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+		}
+	}
 	// Menyisipkan produk baru ke dalam MongoDB
-	result, err := r.collection.InsertOne(context.TODO(), product)
-	if err != nil {
+	if _, err := r.collection.InsertOne(ctx, product); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+		}
 		return "", err
 	}
-	// Mendapatkan ID produk yang disimpan
-	objectID := result.InsertedID.(primitive.ObjectID)
-	// Menghitung durasi waktu yang dihabiskan untuk query
-	log.Printf("CreateProduct duration: %v", time.Since(start)) // Mencatat waktu yang diperlukan untuk menyimpan data
-	return objectID.Hex(), nil
+	return product.ID, nil
 }
 
 // Mengupdate produk yang sudah ada
-func (r *MongoProductRepository) UpdateProduct(product *domain.Product) error {
-	start := time.Now() // Mulai pengukuran waktu
-	objID, err := primitive.ObjectIDFromHex(product.ID)
-	if err != nil {
-		return err
-	}
+func (r *MongoProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	// Filter untuk menemukan produk yang akan di-update, hanya milik tenant ini
+	filter := withTenantFilter(ctx, bson.M{"_id": productIDFilter(product.ID)})
 	// Mengecek apakah produk dengan ID tersebut ada di MongoDB
-	if err := r.collection.FindOne(context.TODO(), bson.M{"_id": objID}).Err(); err != nil {
+	if err := r.collection.FindOne(ctx, filter).Err(); errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %s", domain.ErrProductNotFound, product.ID)
+	} else if err != nil {
 		return err
 	}
-	// Filter untuk menemukan produk yang akan di-update
-	filter := bson.M{"_id": objID}
 	// Data yang akan di-update
-	update := bson.M{
-		"$set": bson.M{
-			"name":  product.Name,
-			"price": product.Price,
-			"stock": product.Stock,
-		},
+	set := bson.M{
+		"name":       product.Name,
+		"price":      product.Price,
+		"stock":      product.Stock,
+		"updated_at": product.UpdatedAt,
+		"updated_by": product.UpdatedBy,
+	}
+	// Identifier kosong dihapus dari dokumen agar tidak bentrok pada index unik
+	unset := bson.M{}
+	for field, value := range map[string]string{"sku": product.SKU, "barcode": product.Barcode, "slug": product.Slug} {
+		if value == "" {
+			unset[field] = ""
+		} else {
+			set[field] = value
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 	// Melakukan update pada produk
-	_, err = r.collection.UpdateOne(context.Background(), filter, update)
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+	}
 	if err != nil {
-		log.Printf("Failed to update product in MongoDB: %v", err)
+		slog.ErrorContext(ctx, "mongo update product failed", "product_id", product.ID, "error", err)
 		return err
 	}
-	// Menghitung durasi waktu yang dihabiskan untuk update query
-	log.Printf("UpdateProduct duration: %v", time.Since(start)) // Mencatat waktu yang dihabiskan untuk proses update
 	return nil
 }
 
 // Menghapus produk berdasarkan ID
-func (r *MongoProductRepository) DeleteProduct(id string) error {
-	start := time.Now() // Mulai pengukuran waktu
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
+func (r *MongoProductRepository) DeleteProduct(ctx context.Context, id string) error {
 	// Menghapus produk dari MongoDB berdasarkan ID
-	_, err = r.collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+	_, err := r.collection.DeleteOne(ctx, withTenantFilter(ctx, bson.M{"_id": productIDFilter(id)}))
 	if err != nil {
 		return err
 	}
-	// Menghitung durasi waktu yang dihabiskan untuk query penghapusan
-	log.Printf("DeleteProduct duration: %v", time.Since(start)) // Mencatat durasi untuk operasi penghapusan
 	return nil
 }
 
 // Mendapatkan daftar produk
-func (r *MongoProductRepository) ListProducts() ([]*domain.Product, error) {
-	start := time.Now() // Mulai pengukuran waktu
-	// Mengambil semua produk dari MongoDB
-	cursor, err := r.collection.Find(context.TODO(), bson.M{})
+func (r *MongoProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	// Nama field pengurutan sama dengan nama field dokumen
+	findOptions := options.Find()
+	if opts.SortBy != "" {
+		direction := 1
+		if opts.Descending {
+			direction = -1
+		}
+		findOptions.SetSort(bson.D{{Key: opts.SortBy, Value: direction}})
+	}
+	// Mengambil semua produk tenant dari MongoDB
+	cursor, err := r.collection.Find(ctx, withTenantFilter(ctx, bson.M{}), findOptions)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	products := make([]*domain.Product, 0)
 	// Iterasi hasil query untuk memasukkan produk ke dalam slice
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var product domain.Product
 		err := cursor.Decode(&product)
 		if err != nil {
@@ -125,7 +198,46 @@ func (r *MongoProductRepository) ListProducts() ([]*domain.Product, error) {
 		}
 		products = append(products, &product)
 	}
-	// Menghitung durasi waktu yang dihabiskan untuk mengambil semua produk
-	log.Printf("ListProducts duration: %v", time.Since(start)) // Mencatat waktu yang dibutuhkan untuk mengambil daftar produk
 	return products, nil
 }
+
+// Menghitung jumlah produk dan produk dengan stok rendah untuk setiap tenant.
+// Dokumen lama tanpa tenant_id dihitung sebagai tenant default.
+func (r *MongoProductRepository) ProductStats(ctx context.Context, lowStockThreshold int) ([]domain.ProductStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$tenant_id", domain.DefaultTenant}},
+			"total": bson.M{"$sum": 1},
+			"low_stock": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$lt": bson.A{"$stock", lowStockThreshold}}, 1, 0},
+			}},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		TenantID string `bson:"_id"`
+		Total    int64  `bson:"total"`
+		LowStock int64  `bson:"low_stock"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	stats := make([]domain.ProductStats, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, domain.ProductStats{TenantID: row.TenantID, Total: row.Total, LowStock: row.LowStock})
+	}
+	return stats, nil
+}
+
+// Filter _id untuk ID string, juga cocok dengan produk lama yang menyimpan _id sebagai ObjectID
+func productIDFilter(id string) interface{} {
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"$in": bson.A{id, objectID}}
+	}
+	return id
+}
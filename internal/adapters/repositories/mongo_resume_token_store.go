@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Penyimpanan resume token change stream di koleksi MongoDB
+type MongoResumeTokenStore struct {
+	collection *mongo.Collection
+}
+
+// Dokumen resume token, satu dokumen per nama consumer
+type resumeTokenDocument struct {
+	Name      string    `bson:"_id"`
+	Token     bson.Raw  `bson:"token"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Membuat instance baru dari MongoResumeTokenStore
+func NewMongoResumeTokenStore(collection *mongo.Collection) *MongoResumeTokenStore {
+	return &MongoResumeTokenStore{
+		collection: collection,
+	}
+}
+
+// Mengambil resume token terakhir untuk consumer dengan nama tertentu
+func (s *MongoResumeTokenStore) LoadToken(name string) ([]byte, error) {
+	var doc resumeTokenDocument
+	err := s.collection.FindOne(context.TODO(), bson.M{"_id": name}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Belum ada token, consumer mulai dari posisi terbaru
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc.Token, nil
+}
+
+// Menyimpan resume token terbaru (upsert)
+func (s *MongoResumeTokenStore) SaveToken(name string, token []byte) error {
+	update := bson.M{
+		"$set": bson.M{
+			"token":      bson.Raw(token),
+			"updated_at": time.Now().UTC(),
+		},
+	}
+	_, err := s.collection.UpdateOne(context.TODO(), bson.M{"_id": name}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Menghapus resume token sehingga consumer mulai dari posisi terbaru
+func (s *MongoResumeTokenStore) DeleteToken(name string) error {
+	_, err := s.collection.DeleteOne(context.TODO(), bson.M{"_id": name})
+	return err
+}
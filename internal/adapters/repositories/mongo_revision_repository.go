@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jumlah percobaan jika nomor revisi bentrok dengan penulisan lain
+const revisionSaveAttempts = 5
+
+// Repository revisi produk di MongoDB
+type MongoRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+// Membuat instance baru dari MongoRevisionRepository
+func NewMongoRevisionRepository(collection *mongo.Collection) *MongoRevisionRepository {
+	return &MongoRevisionRepository{
+		collection: collection,
+	}
+}
+
+// Membuat index unik (product_id, revision) dan index untuk query berdasarkan waktu
+func (r *MongoRevisionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "revision", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	return err
+}
+
+// Menyimpan revisi baru dengan nomor revisi terakhir + 1
+func (r *MongoRevisionRepository) SaveRevision(ctx context.Context, revision *domain.ProductRevision) error {
+	revision.TenantID = writeTenant(ctx, revision.TenantID)
+	for attempt := 0; attempt < revisionSaveAttempts; attempt++ {
+		latest, err := r.latestRevision(ctx, revision.ProductID)
+		if err != nil {
+			return err
+		}
+		revision.Revision = latest + 1
+
+		_, err = r.collection.InsertOne(ctx, revision)
+		if err == nil {
+			return nil
+		}
+		// Penulisan lain sudah memakai nomor ini, ambil ulang nomor terakhir
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return errors.New("failed to allocate revision number")
+}
+
+// Mendapatkan semua revisi produk, diurutkan dari yang terbaru
+func (r *MongoRevisionRepository) ListRevisions(ctx context.Context, productID string) ([]*domain.ProductRevision, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+	cursor, err := r.collection.Find(ctx, withTenantFilter(ctx, bson.M{"product_id": productID}), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := make([]*domain.ProductRevision, 0)
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Mendapatkan revisi tertentu
+func (r *MongoRevisionRepository) GetRevision(ctx context.Context, productID string, revision int) (*domain.ProductRevision, error) {
+	return r.findOne(ctx, bson.M{"product_id": productID, "revision": revision}, nil)
+}
+
+// Mendapatkan revisi terakhir pada atau sebelum waktu asOf
+func (r *MongoRevisionRepository) GetRevisionAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.ProductRevision, error) {
+	filter := bson.M{"product_id": productID, "timestamp": bson.M{"$lte": asOf}}
+	return r.findOne(ctx, filter, bson.D{{Key: "timestamp", Value: -1}, {Key: "revision", Value: -1}})
+}
+
+// Mencari satu revisi milik tenant dari context
+func (r *MongoRevisionRepository) findOne(ctx context.Context, filter bson.M, sort bson.D) (*domain.ProductRevision, error) {
+	findOptions := options.FindOne()
+	if sort != nil {
+		findOptions.SetSort(sort)
+	}
+	var revision domain.ProductRevision
+	err := r.collection.FindOne(ctx, withTenantFilter(ctx, filter), findOptions).Decode(&revision)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// Nomor revisi terakhir produk, 0 jika belum ada
+func (r *MongoRevisionRepository) latestRevision(ctx context.Context, productID string) (int, error) {
+	latest, err := r.findOne(ctx, bson.M{"product_id": productID}, bson.D{{Key: "revision", Value: -1}})
+	if err != nil {
+		if errors.Is(err, domain.ErrRevisionNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return latest.Revision, nil
+}
@@ -1,11 +1,43 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"go-fiber-hexagonal-product/internal/core/domain"
-	"log"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Nomor error MySQL untuk pelanggaran unique key dan deadlock antar transaksi
+const (
+	mysqlErrDuplicateEntry = 1062
+	mysqlErrDeadlock       = 1213
 )
 
+// Kolom yang dibaca untuk setiap produk
+const mysqlProductColumns = "product_id, tenant_id, product_name, sku, barcode, slug, price, stock, created_at, updated_at, created_by, updated_by"
+
+// Kolom MySQL untuk setiap field pengurutan
+var mysqlSortColumns = map[string]string{
+	domain.SortByName:      "product_name",
+	domain.SortByPrice:     "price",
+	domain.SortByStock:     "stock",
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByUpdatedAt: "updated_at",
+}
+
+// Kolom MySQL untuk setiap jenis pencarian produk
+var mysqlLookupColumns = map[domain.ProductLookup]string{
+	domain.LookupSKU:     "sku",
+	domain.LookupBarcode: "barcode",
+	domain.LookupSlug:    "slug",
+}
+
 // Repository produk MySQL
 type MysqlProductRepository struct {
 	db *sql.DB
@@ -16,79 +48,252 @@ func NewMySQLProductRepository(db *sql.DB) *MysqlProductRepository {
 	return &MysqlProductRepository{db: db}
 }
 
-// Mendapatkan produk berdasarkan ID
-func (r *MysqlProductRepository) GetProduct(id string) (*domain.Product, error) {
-	var product domain.Product
-	err := r.db.QueryRow("SELECT product_id, product_name, price, stock FROM product WHERE product_id = ?", id).Scan(&product.ID, &product.Name, &product.Price, &product.Stock)
+// Memeriksa koneksi ke MySQL untuk readiness
+func (r *MysqlProductRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// Mendapatkan produk berdasarkan ID, domain.ErrProductNotFound jika tidak ada
+func (r *MysqlProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	tenantClause, tenantArgs := mysqlTenantClause(ctx)
+	args := append([]any{id}, tenantArgs...)
+	product, err := scanProduct(r.db.QueryRowContext(ctx, "SELECT "+mysqlProductColumns+" FROM product WHERE product_id = ?"+tenantClause, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, id)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			// Jika produk tidak ditemukan, return nil dan error nil
-			return nil, nil
-		}
 		return nil, err
 	}
-	return &product, nil
+	return product, nil
+}
+
+// Mendapatkan produk berdasarkan SKU, barcode atau slug
+func (r *MysqlProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	// Nama kolom diambil dari whitelist, bukan dari input
+	column, ok := mysqlLookupColumns[lookup]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product lookup %q", lookup)
+	}
+	tenantClause, tenantArgs := mysqlTenantClause(ctx)
+	args := append([]any{value}, tenantArgs...)
+	product, err := scanProduct(r.db.QueryRowContext(ctx, "SELECT "+mysqlProductColumns+" FROM product WHERE "+column+" = ?"+tenantClause, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s %s", domain.ErrProductNotFound, lookup, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
 // Membuat produk baru
-func (r *MysqlProductRepository) CreateProduct(product *domain.Product) error {
-	_, err := r.db.Exec("INSERT INTO product (product_id, product_name, price, stock) VALUES (?, ?, ?, ?)", product.ID, product.Name, product.Price, product.Stock)
+func (r *MysqlProductRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	product.TenantID = writeTenant(ctx, product.TenantID)
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO product (product_id, tenant_id, product_name, sku, barcode, slug, price, stock, created_at, updated_at, created_by, updated_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		product.ID, product.TenantID, product.Name, nullString(product.SKU), nullString(product.Barcode), nullString(product.Slug),
+		product.Price, product.Stock, nullTime(product.CreatedAt), nullTime(product.UpdatedAt), product.CreatedBy, product.UpdatedBy,
+	)
 	if err != nil {
-		log.Printf("Gagal membuat produk di MySQL: %v", err)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+		}
+		slog.ErrorContext(ctx, "mysql create product failed", "product_id", product.ID, "error", err)
 		return err
 	}
 	return nil
 }
 
 // Mengupdate produk yang sudah ada
-func (r *MysqlProductRepository) UpdateProduct(product *domain.Product) error {
-	// Cek apakah produk ada di MySQL
-	_, err := r.GetProduct(product.ID)
+func (r *MysqlProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	tenantClause, tenantArgs := mysqlTenantClause(ctx)
+	args := append([]any{
+		product.Name, nullString(product.SKU), nullString(product.Barcode), nullString(product.Slug),
+		product.Price, product.Stock, nullTime(product.UpdatedAt), product.UpdatedBy, product.ID,
+	}, tenantArgs...)
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE product SET product_name = ?, sku = ?, barcode = ?, slug = ?, price = ?, stock = ?, updated_at = ?, updated_by = ? WHERE product_id = ?"+tenantClause,
+		args...,
+	)
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "mysql update product failed", "product_id", product.ID, "error", err)
+		return err
+	}
+	// Koneksi memakai clientFoundRows, sehingga 0 berarti produk tidak ada atau milik tenant lain
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrProductNotFound, product.ID)
+	}
+	return nil
+}
 
-	_, err = r.db.Exec("UPDATE product SET product_name = ?, price = ?, stock = ? WHERE product_id = ?", product.Name, product.Price, product.Stock, product.ID)
+// Menyimpan produk, update jika product_id sudah ada dan insert jika belum.
+// Tenant produk yang sudah ada tidak pernah diubah. Upsert hanya berdasarkan product_id:
+// SKU, barcode atau slug yang sudah dipakai produk lain menghasilkan domain.ErrProductExists,
+// bukan menimpa produk tersebut.
+func (r *MysqlProductRepository) SaveProduct(ctx context.Context, product *domain.Product) error {
+	product.TenantID = writeTenant(ctx, product.TenantID)
+	err := r.saveProduct(ctx, product)
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("%w: %s", domain.ErrProductExists, product.ID)
+	}
 	if err != nil {
-		log.Printf("Gagal mengupdate produk di MySQL: %v", err)
+		slog.ErrorContext(ctx, "mysql save product failed", "product_id", product.ID, "error", err)
 		return err
 	}
 	return nil
 }
 
+// Menyimpan produk dan mencoba sekali lagi jika kalah balapan dengan penyimpanan produk
+// yang sama (duplicate entry atau deadlock pada gap lock), yang kemudian menjadi update
+func (r *MysqlProductRepository) saveProduct(ctx context.Context, product *domain.Product) error {
+	err := r.saveProductTx(ctx, product)
+	if isDuplicateEntry(err) || isDeadlock(err) {
+		err = r.saveProductTx(ctx, product)
+	}
+	return err
+}
+
+// Update jika product_id sudah ada dan insert jika belum, dalam satu transaksi.
+// Baris dikunci dengan SELECT ... FOR UPDATE agar change stream dan API tidak saling menimpa.
+func (r *MysqlProductRepository) saveProductTx(ctx context.Context, product *domain.Product) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, "SELECT 1 FROM product WHERE product_id = ? FOR UPDATE", product.ID).Scan(&exists)
+	switch {
+	case err == nil:
+		_, err = tx.ExecContext(ctx,
+			"UPDATE product SET product_name = ?, sku = ?, barcode = ?, slug = ?, price = ?, stock = ?, "+
+				"created_at = ?, updated_at = ?, created_by = ?, updated_by = ? WHERE product_id = ?",
+			product.Name, nullString(product.SKU), nullString(product.Barcode), nullString(product.Slug), product.Price, product.Stock,
+			nullTime(product.CreatedAt), nullTime(product.UpdatedAt), product.CreatedBy, product.UpdatedBy, product.ID,
+		)
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO product (product_id, tenant_id, product_name, sku, barcode, slug, price, stock, created_at, updated_at, created_by, updated_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			product.ID, product.TenantID, product.Name, nullString(product.SKU), nullString(product.Barcode), nullString(product.Slug),
+			product.Price, product.Stock, nullTime(product.CreatedAt), nullTime(product.UpdatedAt), product.CreatedBy, product.UpdatedBy,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // Menghapus produk berdasarkan ID
-func (r *MysqlProductRepository) DeleteProduct(id string) error {
-	_, err := r.db.Exec("DELETE FROM product WHERE product_id = ?", id)
+func (r *MysqlProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	tenantClause, tenantArgs := mysqlTenantClause(ctx)
+	_, err := r.db.ExecContext(ctx, "DELETE FROM product WHERE product_id = ?"+tenantClause, append([]any{id}, tenantArgs...)...)
 	if err != nil {
-		log.Printf("Gagal menghapus produk: %v", err)
+		slog.ErrorContext(ctx, "mysql delete product failed", "product_id", id, "error", err)
 		return err
 	}
 	return nil
 }
 
 // Mendapatkan daftar produk
-func (r *MysqlProductRepository) ListProducts() ([]*domain.Product, error) {
-	rows, err := r.db.Query("SELECT product_id, product_name, price, stock FROM product")
+func (r *MysqlProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	query := "SELECT " + mysqlProductColumns + " FROM product"
+	tenantClause, tenantArgs := mysqlTenantClause(ctx)
+	if tenantClause != "" {
+		query += " WHERE" + strings.TrimPrefix(tenantClause, " AND")
+	}
+	// Nama kolom diambil dari whitelist, bukan dari input
+	if column, ok := mysqlSortColumns[opts.SortBy]; ok {
+		direction := "ASC"
+		if opts.Descending {
+			direction = "DESC"
+		}
+		query += " ORDER BY " + column + " " + direction
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, tenantArgs...)
 	if err != nil {
-		log.Printf("Gagal mendapatkan daftar produk: %v", err)
+		slog.ErrorContext(ctx, "mysql list products failed", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
 
 	var products []*domain.Product
 	for rows.Next() {
-		var product domain.Product
-		if err := rows.Scan(&product.ID, &product.Name, &product.Price, &product.Stock); err != nil {
-			log.Printf("Gagal scan produk: %v", err)
+		product, err := scanProduct(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "mysql scan product failed", "error", err)
 			return nil, err
 		}
-		products = append(products, &product)
+		products = append(products, product)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Error iterating over rows: %v", err)
+		slog.ErrorContext(ctx, "mysql iterate products failed", "error", err)
 		return nil, err
 	}
 
 	return products, nil
-}
\ No newline at end of file
+}
+
+// Baris hasil query yang bisa di-scan (*sql.Row atau *sql.Rows)
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Membaca satu baris produk sesuai urutan mysqlProductColumns
+func scanProduct(row rowScanner) (*domain.Product, error) {
+	var product domain.Product
+	var createdAt, updatedAt sql.NullTime
+	var sku, barcode, slug, createdBy, updatedBy sql.NullString
+	if err := row.Scan(&product.ID, &product.TenantID, &product.Name, &sku, &barcode, &slug, &product.Price, &product.Stock, &createdAt, &updatedAt, &createdBy, &updatedBy); err != nil {
+		return nil, err
+	}
+	// Produk lama mungkin belum memiliki kolom audit
+	if createdAt.Valid {
+		product.CreatedAt = createdAt.Time.UTC()
+	}
+	if updatedAt.Valid {
+		product.UpdatedAt = updatedAt.Time.UTC()
+	}
+	product.SKU = sku.String
+	product.Barcode = barcode.String
+	product.Slug = slug.String
+	product.CreatedBy = createdBy.String
+	product.UpdatedBy = updatedBy.String
+	return &product, nil
+}
+
+// Waktu kosong disimpan sebagai NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// String kosong disimpan sebagai NULL agar tidak bentrok pada unique key
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Memeriksa apakah error berasal dari pelanggaran unique key
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// Memeriksa apakah transaksi dibatalkan MySQL karena deadlock
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDeadlock
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Menambahkan filter tenant dari context ke filter MongoDB.
+// Dokumen lama tanpa field tenant_id dianggap milik DefaultTenant.
+// Context lintas tenant (domain.ContextWithAllTenants) tidak difilter.
+func withTenantFilter(ctx context.Context, filter bson.M) bson.M {
+	if domain.AllTenantsFromContext(ctx) {
+		return filter
+	}
+	tenant := domain.TenantFromContext(ctx)
+	if tenant == domain.DefaultTenant {
+		filter["tenant_id"] = bson.M{"$in": bson.A{tenant, nil}}
+	} else {
+		filter["tenant_id"] = tenant
+	}
+	return filter
+}
+
+// Kondisi tenant untuk query MySQL, kosong untuk context lintas tenant
+func mysqlTenantClause(ctx context.Context) (string, []any) {
+	if domain.AllTenantsFromContext(ctx) {
+		return "", nil
+	}
+	return " AND tenant_id = ?", []any{domain.TenantFromContext(ctx)}
+}
+
+// Tenant untuk data yang ditulis: dari data itu sendiri untuk context lintas tenant,
+// selain itu dari context
+func writeTenant(ctx context.Context, tenant string) string {
+	if domain.AllTenantsFromContext(ctx) {
+		if tenant == "" {
+			return domain.DefaultTenant
+		}
+		return tenant
+	}
+	return domain.TenantFromContext(ctx)
+}
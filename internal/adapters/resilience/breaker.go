@@ -0,0 +1,148 @@
+package resilience
+
+import (
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Pengaturan circuit breaker
+type BreakerOptions struct {
+	// Jumlah kegagalan berturut-turut yang membuka circuit, 0 menonaktifkan circuit breaker
+	FailureThreshold int
+	// Lama circuit terbuka sebelum panggilan percobaan (half-open) diizinkan
+	OpenTimeout time.Duration
+	// Jumlah panggilan percobaan sukses berturut-turut yang menutup kembali circuit
+	SuccessThreshold int
+}
+
+// Hasil panggilan yang dicatat circuit breaker
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// Panggilan dibatalkan pemanggil sebelum store menjawab, tidak dihitung sukses maupun gagal
+	callCanceled
+)
+
+// Circuit breaker berbasis kegagalan berturut-turut. Saat half-open hanya satu
+// panggilan percobaan yang berjalan pada satu waktu.
+type CircuitBreaker struct {
+	name string
+	opts BreakerOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	state     domain.CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+	probing   bool
+	// Naik setiap kali status berubah agar hasil panggilan dari status lama diabaikan
+	generation uint64
+}
+
+// Membuat instance baru dari CircuitBreaker dalam status closed
+func NewCircuitBreaker(name string, opts BreakerOptions) *CircuitBreaker {
+	if opts.SuccessThreshold < 1 {
+		opts.SuccessThreshold = 1
+	}
+	return &CircuitBreaker{
+		name:  name,
+		opts:  opts,
+		now:   time.Now,
+		state: domain.CircuitClosed,
+	}
+}
+
+// Status circuit saat ini. Circuit terbuka yang sudah melewati OpenTimeout dilaporkan half-open.
+func (b *CircuitBreaker) State() domain.CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+	return b.state
+}
+
+// Meminta izin menjalankan panggilan, error domain.ErrStoreUnavailable jika circuit terbuka
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.opts.FailureThreshold <= 0 {
+		return b.generation, nil
+	}
+	b.refresh()
+	switch b.state {
+	case domain.CircuitOpen:
+		return 0, fmt.Errorf("%w: %s circuit breaker is open", domain.ErrStoreUnavailable, b.name)
+	case domain.CircuitHalfOpen:
+		if b.probing {
+			return 0, fmt.Errorf("%w: %s circuit breaker is half-open", domain.ErrStoreUnavailable, b.name)
+		}
+		b.probing = true
+	}
+	return b.generation, nil
+}
+
+// Mencatat hasil panggilan yang diizinkan oleh allow. Panggilan yang dibatalkan
+// pemanggil hanya melepas slot percobaan, sehingga circuit half-open tetap half-open.
+func (b *CircuitBreaker) record(generation uint64, outcome callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.opts.FailureThreshold <= 0 || generation != b.generation {
+		return
+	}
+	switch b.state {
+	case domain.CircuitClosed:
+		switch outcome {
+		case callSucceeded:
+			b.failures = 0
+		case callFailed:
+			b.failures++
+			if b.failures >= b.opts.FailureThreshold {
+				b.transition(domain.CircuitOpen)
+			}
+		}
+	case domain.CircuitHalfOpen:
+		b.probing = false
+		switch outcome {
+		case callCanceled:
+			return
+		case callFailed:
+			b.transition(domain.CircuitOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.opts.SuccessThreshold {
+			b.transition(domain.CircuitClosed)
+		}
+	}
+}
+
+// Memindahkan circuit terbuka ke half-open setelah OpenTimeout, mu harus sudah dikunci
+func (b *CircuitBreaker) refresh() {
+	if b.state == domain.CircuitOpen && b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		b.transition(domain.CircuitHalfOpen)
+	}
+}
+
+// Mengganti status circuit dan mereset penghitung, mu harus sudah dikunci
+func (b *CircuitBreaker) transition(state domain.CircuitState) {
+	from := b.state
+	b.state = state
+	b.failures = 0
+	b.successes = 0
+	b.probing = false
+	b.generation++
+	if state == domain.CircuitOpen {
+		b.openedAt = b.now()
+	}
+
+	if state == domain.CircuitOpen {
+		slog.Warn("circuit breaker opened", "store", b.name, "from", from, "open_timeout", b.opts.OpenTimeout)
+	} else {
+		slog.Info("circuit breaker state changed", "store", b.name, "from", from, "to", state)
+	}
+}
@@ -0,0 +1,63 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+)
+
+// Membatasi jumlah panggilan bersamaan ke satu store agar store yang lambat
+// tidak menghabiskan seluruh goroutine dan koneksi
+type Bulkhead struct {
+	name    string
+	slots   chan struct{}
+	maxWait time.Duration
+}
+
+// Membuat instance baru dari Bulkhead, maxConcurrent 0 berarti tanpa batas.
+// Panggilan menunggu slot paling lama maxWait sebelum ditolak.
+func NewBulkhead(name string, maxConcurrent int, maxWait time.Duration) *Bulkhead {
+	b := &Bulkhead{name: name, maxWait: maxWait}
+	if maxConcurrent > 0 {
+		b.slots = make(chan struct{}, maxConcurrent)
+	}
+	return b
+}
+
+// Mengambil satu slot, error domain.ErrStoreUnavailable jika bulkhead penuh
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	if b.slots == nil {
+		return nil
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if b.maxWait <= 0 {
+		return b.fullError()
+	}
+
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return b.fullError()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Mengembalikan slot yang diambil oleh acquire
+func (b *Bulkhead) release() {
+	if b.slots != nil {
+		<-b.slots
+	}
+}
+
+func (b *Bulkhead) fullError() error {
+	return fmt.Errorf("%w: %s bulkhead is full (%d concurrent calls)", domain.ErrStoreUnavailable, b.name, cap(b.slots))
+}
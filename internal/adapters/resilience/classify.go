@@ -0,0 +1,93 @@
+package resilience
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Menentukan apakah error bersifat sementara (jaringan, timeout, failover, deadlock)
+// sehingga layak dicoba ulang dan dihitung sebagai kegagalan oleh circuit breaker.
+// Error bisnis seperti not found atau duplicate key bukan error sementara.
+type Classifier func(error) bool
+
+// Kode error server MongoDB yang terjadi saat failover, shutdown atau jaringan bermasalah
+var mongoTransientCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	262,   // ExceededTimeLimit
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// Nomor error server MySQL yang aman dicoba ulang
+var mysqlTransientNumbers = map[uint16]bool{
+	1040: true, // ER_CON_COUNT_ERROR (too many connections)
+	1205: true, // ER_LOCK_WAIT_TIMEOUT
+	1213: true, // ER_LOCK_DEADLOCK
+	1290: true, // ER_OPTION_PREVENTS_STATEMENT (read-only saat failover)
+	1792: true, // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+	3024: true, // ER_QUERY_TIMEOUT
+}
+
+// Klasifikasi error sementara driver MongoDB
+func IsMongoTransient(err error) bool {
+	if err == nil || errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) && (labeled.HasErrorLabel("RetryableWriteError") || labeled.HasErrorLabel("TransientTransactionError")) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		for _, code := range mongoTransientCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return isNetworkError(err)
+}
+
+// Klasifikasi error sementara driver MySQL
+func IsMySQLTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlTransientNumbers[mysqlErr.Number]
+	}
+	return isNetworkError(err)
+}
+
+// Error jaringan umum: timeout, koneksi ditolak atau terputus
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,169 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Pengaturan percobaan ulang dengan exponential backoff dan full jitter
+type RetryOptions struct {
+	// Jumlah percobaan maksimum termasuk panggilan pertama, 1 berarti tanpa percobaan ulang
+	MaxAttempts int
+	// Batas jeda percobaan ulang pertama, naik dua kali lipat sampai MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Jeda acak sebelum percobaan ulang ke-n (mulai dari 1)
+func (o RetryOptions) backoff(retry int) time.Duration {
+	ceiling := o.BaseDelay << (retry - 1)
+	if ceiling <= 0 || (o.MaxDelay > 0 && ceiling > o.MaxDelay) {
+		ceiling = o.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// Pengaturan resilience satu store
+type Options struct {
+	Breaker BreakerOptions
+	Retry   RetryOptions
+	// Jumlah panggilan bersamaan maksimum, 0 berarti tanpa batas
+	MaxConcurrent int
+	// Lama menunggu slot bulkhead sebelum panggilan ditolak
+	MaxWait time.Duration
+	// Batas waktu satu percobaan, 0 berarti hanya mengikuti ctx pemanggil
+	Timeout time.Duration
+}
+
+// Kebijakan resilience satu store: bulkhead, circuit breaker lalu retry untuk setiap panggilan
+type Policy struct {
+	store     string
+	breaker   *CircuitBreaker
+	bulkhead  *Bulkhead
+	retry     RetryOptions
+	timeout   time.Duration
+	transient Classifier
+
+	inFlight      atomic.Int64
+	retries       atomic.Uint64
+	shortCircuits atomic.Uint64
+	rejections    atomic.Uint64
+}
+
+// Membuat instance baru dari Policy, transient menentukan error yang dicoba ulang
+// dan dihitung sebagai kegagalan oleh circuit breaker
+func NewPolicy(store string, opts Options, transient Classifier) *Policy {
+	if opts.Retry.MaxAttempts < 1 {
+		opts.Retry.MaxAttempts = 1
+	}
+	return &Policy{
+		store:     store,
+		breaker:   NewCircuitBreaker(store, opts.Breaker),
+		bulkhead:  NewBulkhead(store, opts.MaxConcurrent, opts.MaxWait),
+		retry:     opts.Retry,
+		timeout:   opts.Timeout,
+		transient: transient,
+	}
+}
+
+// Menjalankan fn dengan perlindungan bulkhead dan circuit breaker. Jika retryable,
+// error sementara dicoba ulang; hanya operasi idempoten yang boleh retryable.
+func (p *Policy) Execute(ctx context.Context, operation string, retryable bool, fn func(context.Context) error) error {
+	if err := p.bulkhead.acquire(ctx); err != nil {
+		if errors.Is(err, domain.ErrStoreUnavailable) {
+			p.rejections.Add(1)
+		}
+		return err
+	}
+	defer p.bulkhead.release()
+	p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	generation, err := p.breaker.allow()
+	if err != nil {
+		p.shortCircuits.Add(1)
+		return err
+	}
+	err = p.run(ctx, operation, retryable, fn)
+	p.breaker.record(generation, p.outcome(ctx, err))
+	return err
+}
+
+// Menjalankan fn dan mencoba ulang error sementara sampai percobaan habis
+func (p *Policy) run(ctx context.Context, operation string, retryable bool, fn func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := p.attempt(ctx, fn)
+		if err == nil || !retryable || attempt >= p.retry.MaxAttempts || !p.isFailure(ctx, err) {
+			return err
+		}
+
+		delay := p.retry.backoff(attempt)
+		p.retries.Add(1)
+		slog.DebugContext(ctx, "retrying repository call", "store", p.store, "operation", operation, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Satu percobaan dengan batas waktu per percobaan
+func (p *Policy) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if p.timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// Error yang disebabkan store: error sementara atau batas waktu percobaan habis,
+// termasuk batas waktu dari domain.ContextWithStoreTimeout. Pembatalan oleh pemanggil
+// tidak dihitung sebagai kegagalan store.
+func (p *Policy) isFailure(ctx context.Context, err error) bool {
+	if err == nil || (ctx.Err() != nil && !domain.StoreTimedOut(ctx)) {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || p.transient(err)
+}
+
+// Hasil panggilan untuk circuit breaker: error karena pemanggil membatalkan request
+// (atau batas waktu request habis) bukan jawaban dari store
+func (p *Policy) outcome(ctx context.Context, err error) callOutcome {
+	switch {
+	case p.isFailure(ctx, err):
+		return callFailed
+	case err != nil && ctx.Err() != nil:
+		return callCanceled
+	default:
+		return callSucceeded
+	}
+}
+
+// Status circuit breaker saat ini
+func (p *Policy) CircuitState() domain.CircuitState {
+	return p.breaker.State()
+}
+
+// Statistik untuk metrics
+func (p *Policy) Stats() domain.ResilienceStats {
+	return domain.ResilienceStats{
+		Store:              p.store,
+		State:              p.breaker.State(),
+		InFlight:           int(p.inFlight.Load()),
+		Retries:            p.retries.Load(),
+		ShortCircuits:      p.shortCircuits.Load(),
+		BulkheadRejections: p.rejections.Load(),
+	}
+}
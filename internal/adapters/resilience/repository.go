@@ -0,0 +1,123 @@
+package resilience
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+)
+
+// Decorator MongoProductRepository dengan circuit breaker, bulkhead dan retry.
+// Hanya pembacaan yang dicoba ulang di sini, penulisan memakai retryable writes driver.
+type mongoProductRepository struct {
+	next   ports.MongoProductRepository
+	policy *Policy
+}
+
+// Membungkus repository produk MongoDB dengan kebijakan resilience
+func ProtectMongoProductRepository(next ports.MongoProductRepository, policy *Policy) ports.MongoProductRepository {
+	return &mongoProductRepository{next: next, policy: policy}
+}
+
+func (r *mongoProductRepository) GetProduct(ctx context.Context, id string) (product *domain.Product, err error) {
+	err = r.policy.Execute(ctx, "GetProduct", true, func(ctx context.Context) error {
+		product, err = r.next.GetProduct(ctx, id)
+		return err
+	})
+	return product, err
+}
+
+func (r *mongoProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (product *domain.Product, err error) {
+	err = r.policy.Execute(ctx, "FindProduct", true, func(ctx context.Context) error {
+		product, err = r.next.FindProduct(ctx, lookup, value)
+		return err
+	})
+	return product, err
+}
+
+func (r *mongoProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (id string, err error) {
+	err = r.policy.Execute(ctx, "CreateProduct", false, func(ctx context.Context) error {
+		id, err = r.next.CreateProduct(ctx, product)
+		return err
+	})
+	return id, err
+}
+
+func (r *mongoProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	return r.policy.Execute(ctx, "UpdateProduct", false, func(ctx context.Context) error {
+		return r.next.UpdateProduct(ctx, product)
+	})
+}
+
+func (r *mongoProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	return r.policy.Execute(ctx, "DeleteProduct", false, func(ctx context.Context) error {
+		return r.next.DeleteProduct(ctx, id)
+	})
+}
+
+func (r *mongoProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) (products []*domain.Product, err error) {
+	err = r.policy.Execute(ctx, "ListProducts", true, func(ctx context.Context) error {
+		products, err = r.next.ListProducts(ctx, opts)
+		return err
+	})
+	return products, err
+}
+
+// Decorator MySQLProductRepository dengan circuit breaker, bulkhead dan retry.
+// Update, save dan delete idempoten sehingga ikut dicoba ulang, insert tidak.
+type mysqlProductRepository struct {
+	next   ports.MySQLProductRepository
+	policy *Policy
+}
+
+// Membungkus repository produk MySQL dengan kebijakan resilience
+func ProtectMySQLProductRepository(next ports.MySQLProductRepository, policy *Policy) ports.MySQLProductRepository {
+	return &mysqlProductRepository{next: next, policy: policy}
+}
+
+func (r *mysqlProductRepository) GetProduct(ctx context.Context, id string) (product *domain.Product, err error) {
+	err = r.policy.Execute(ctx, "GetProduct", true, func(ctx context.Context) error {
+		product, err = r.next.GetProduct(ctx, id)
+		return err
+	})
+	return product, err
+}
+
+func (r *mysqlProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (product *domain.Product, err error) {
+	err = r.policy.Execute(ctx, "FindProduct", true, func(ctx context.Context) error {
+		product, err = r.next.FindProduct(ctx, lookup, value)
+		return err
+	})
+	return product, err
+}
+
+func (r *mysqlProductRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	return r.policy.Execute(ctx, "CreateProduct", false, func(ctx context.Context) error {
+		return r.next.CreateProduct(ctx, product)
+	})
+}
+
+func (r *mysqlProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	return r.policy.Execute(ctx, "UpdateProduct", true, func(ctx context.Context) error {
+		return r.next.UpdateProduct(ctx, product)
+	})
+}
+
+func (r *mysqlProductRepository) SaveProduct(ctx context.Context, product *domain.Product) error {
+	return r.policy.Execute(ctx, "SaveProduct", true, func(ctx context.Context) error {
+		return r.next.SaveProduct(ctx, product)
+	})
+}
+
+func (r *mysqlProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	return r.policy.Execute(ctx, "DeleteProduct", true, func(ctx context.Context) error {
+		return r.next.DeleteProduct(ctx, id)
+	})
+}
+
+func (r *mysqlProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) (products []*domain.Product, err error) {
+	err = r.policy.Execute(ctx, "ListProducts", true, func(ctx context.Context) error {
+		products, err = r.next.ListProducts(ctx, opts)
+		return err
+	})
+	return products, err
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Memulai span client untuk satu operasi database
+func startDBSpan(ctx context.Context, system attribute.KeyValue, collection, operation string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, operation+" "+collection,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			system,
+			semconv.DBCollectionName(collection),
+			semconv.DBOperationName(operation),
+			attribute.String("tenant", domain.TenantFromContext(ctx)),
+		),
+	)
+}
+
+// Decorator MongoProductRepository yang membuat span untuk setiap operasi
+type mongoProductRepository struct {
+	next ports.MongoProductRepository
+}
+
+// Membungkus repository produk MongoDB dengan span per operasi
+func InstrumentMongoProductRepository(next ports.MongoProductRepository) ports.MongoProductRepository {
+	return &mongoProductRepository{next: next}
+}
+
+func (r *mongoProductRepository) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return startDBSpan(ctx, semconv.DBSystemMongoDB, "products", operation)
+}
+
+func (r *mongoProductRepository) GetProduct(ctx context.Context, id string) (product *domain.Product, err error) {
+	ctx, span := r.start(ctx, "GetProduct")
+	defer func() { end(span, err) }()
+	return r.next.GetProduct(ctx, id)
+}
+
+func (r *mongoProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (product *domain.Product, err error) {
+	ctx, span := r.start(ctx, "FindProduct")
+	defer func() { end(span, err) }()
+	return r.next.FindProduct(ctx, lookup, value)
+}
+
+func (r *mongoProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (id string, err error) {
+	ctx, span := r.start(ctx, "CreateProduct")
+	defer func() { end(span, err) }()
+	return r.next.CreateProduct(ctx, product)
+}
+
+func (r *mongoProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := r.start(ctx, "UpdateProduct")
+	defer func() { end(span, err) }()
+	return r.next.UpdateProduct(ctx, product)
+}
+
+func (r *mongoProductRepository) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := r.start(ctx, "DeleteProduct")
+	defer func() { end(span, err) }()
+	return r.next.DeleteProduct(ctx, id)
+}
+
+func (r *mongoProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) (products []*domain.Product, err error) {
+	ctx, span := r.start(ctx, "ListProducts")
+	defer func() { end(span, err) }()
+	return r.next.ListProducts(ctx, opts)
+}
+
+// Decorator MySQLProductRepository yang membuat span untuk setiap operasi
+type mysqlProductRepository struct {
+	next ports.MySQLProductRepository
+}
+
+// Membungkus repository produk MySQL dengan span per operasi
+func InstrumentMySQLProductRepository(next ports.MySQLProductRepository) ports.MySQLProductRepository {
+	return &mysqlProductRepository{next: next}
+}
+
+func (r *mysqlProductRepository) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return startDBSpan(ctx, semconv.DBSystemMySQL, "product", operation)
+}
+
+func (r *mysqlProductRepository) GetProduct(ctx context.Context, id string) (product *domain.Product, err error) {
+	ctx, span := r.start(ctx, "GetProduct")
+	defer func() { end(span, err) }()
+	return r.next.GetProduct(ctx, id)
+}
+
+func (r *mysqlProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (product *domain.Product, err error) {
+	ctx, span := r.start(ctx, "FindProduct")
+	defer func() { end(span, err) }()
+	return r.next.FindProduct(ctx, lookup, value)
+}
+
+func (r *mysqlProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := r.start(ctx, "CreateProduct")
+	defer func() { end(span, err) }()
+	return r.next.CreateProduct(ctx, product)
+}
+
+func (r *mysqlProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := r.start(ctx, "UpdateProduct")
+	defer func() { end(span, err) }()
+	return r.next.UpdateProduct(ctx, product)
+}
+
+func (r *mysqlProductRepository) SaveProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := r.start(ctx, "SaveProduct")
+	defer func() { end(span, err) }()
+	return r.next.SaveProduct(ctx, product)
+}
+
+func (r *mysqlProductRepository) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := r.start(ctx, "DeleteProduct")
+	defer func() { end(span, err) }()
+	return r.next.DeleteProduct(ctx, id)
+}
+
+func (r *mysqlProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) (products []*domain.Product, err error) {
+	ctx, span := r.start(ctx, "ListProducts")
+	defer func() { end(span, err) }()
+	return r.next.ListProducts(ctx, opts)
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Decorator ProductService yang membuat span untuk setiap method.
+// Span repository MongoDB dan MySQL dari penulisan ganda menjadi child span ini.
+type productService struct {
+	next ports.ProductService
+}
+
+// Membungkus ProductService dengan span per method
+func InstrumentProductService(next ports.ProductService) ports.ProductService {
+	return &productService{next: next}
+}
+
+func (s *productService) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("tenant", domain.TenantFromContext(ctx)),
+		attribute.String("actor", domain.ActorFromContext(ctx)),
+	)
+	return tracer().Start(ctx, "ProductService."+method, trace.WithAttributes(attrs...))
+}
+
+func (s *productService) GetProduct(ctx context.Context, id string) (product *domain.Product, err error) {
+	ctx, span := s.start(ctx, "GetProduct", attribute.String("product.id", id))
+	defer func() { end(span, err) }()
+	return s.next.GetProduct(ctx, id)
+}
+
+func (s *productService) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (product *domain.Product, err error) {
+	ctx, span := s.start(ctx, "FindProduct", attribute.String("product.lookup", string(lookup)))
+	defer func() { end(span, err) }()
+	return s.next.FindProduct(ctx, lookup, value)
+}
+
+func (s *productService) CreateProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := s.start(ctx, "CreateProduct")
+	defer func() {
+		span.SetAttributes(attribute.String("product.id", product.ID))
+		end(span, err)
+	}()
+	return s.next.CreateProduct(ctx, product)
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := s.start(ctx, "UpdateProduct", attribute.String("product.id", product.ID))
+	defer func() { end(span, err) }()
+	return s.next.UpdateProduct(ctx, product)
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteProduct", attribute.String("product.id", id))
+	defer func() { end(span, err) }()
+	return s.next.DeleteProduct(ctx, id)
+}
+
+func (s *productService) RestoreProduct(ctx context.Context, product *domain.Product) (err error) {
+	ctx, span := s.start(ctx, "RestoreProduct", attribute.String("product.id", product.ID))
+	defer func() { end(span, err) }()
+	return s.next.RestoreProduct(ctx, product)
+}
+
+func (s *productService) ListProducts(ctx context.Context, opts domain.ProductListOptions) (products []*domain.Product, err error) {
+	ctx, span := s.start(ctx, "ListProducts", attribute.String("product.sort", opts.String()))
+	defer func() {
+		span.SetAttributes(attribute.Int("product.count", len(products)))
+		end(span, err)
+	}()
+	return s.next.ListProducts(ctx, opts)
+}
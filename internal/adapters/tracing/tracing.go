@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"io"
+	"os"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Nama instrumentation scope untuk semua span aplikasi
+const instrumentationName = "go-fiber-hexagonal-product"
+
+// Jenis exporter span
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Konfigurasi tracing
+type Options struct {
+	// Jenis exporter: "otlp" atau "stdout"
+	Exporter string
+	// Nama service pada resource span
+	ServiceName string
+	// Alamat collector OTLP/HTTP (host:port), kosong memakai default SDK (localhost:4318)
+	Endpoint string
+	// Mengirim ke collector tanpa TLS
+	Insecure bool
+	// File tujuan exporter stdout, kosong berarti stdout
+	File string
+	// Rasio sampling trace baru (0-1), trace dengan parent mengikuti keputusan parent
+	SampleRatio float64
+}
+
+// Memasang tracer provider dan propagator W3C traceparent/baggage secara global.
+// Fungsi shutdown mengirim span yang tersisa lalu menutup exporter.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+	var file io.Closer
+	switch opts.Exporter {
+	case ExporterOTLP:
+		clientOpts := []otlptracehttp.Option{}
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+	case ExporterStdout:
+		writer := io.Writer(os.Stdout)
+		if opts.File != "" {
+			f, openErr := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if openErr != nil {
+				return nil, openErr
+			}
+			writer, file = f, f
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(writer))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Tracer aplikasi dari provider global, dibaca setiap kali agar mengikuti Setup
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Menutup span dan menandai error, not found tidak dianggap error
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) && !errors.Is(err, domain.ErrProductNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
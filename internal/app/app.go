@@ -1,47 +1,520 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/metrics"
+	"go-fiber-hexagonal-product/internal/adapters/resilience"
+	"go-fiber-hexagonal-product/internal/adapters/tracing"
+	"go-fiber-hexagonal-product/internal/core/domain"
 	"go-fiber-hexagonal-product/internal/core/ports"
 	"go-fiber-hexagonal-product/internal/core/services"
 	"go-fiber-hexagonal-product/pkg/config"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 type App struct {
-	config    *config.Config
-	fiberApp  *fiber.App
-	mongoRepo ports.MongoProductRepository
-	mysqlRepo ports.MySQLProductRepository
+	config       *config.Config
+	fiberApp     *fiber.App
+	mongoRepo    ports.MongoProductRepository
+	mysqlRepo    ports.MySQLProductRepository
+	productCache ports.ProductCache
+	auditRepo    ports.AuditRepository
+	revisionRepo ports.RevisionRepository
+	verifier     ports.TokenVerifier
+	apiKeyRepo   ports.APIKeyRepository
+	authorizer   ports.Authorizer
+	rateLimiter  ports.RateLimiter
+	idempotency  ports.IdempotencyStore
+	idGenerator  ports.IDGenerator
+	metrics      *metrics.Metrics
+	tracing      bool
+	healthDeps   []services.HealthDependency
+	resilience   map[string]ports.ResiliencePolicy
+	health       *services.HealthService
+	products     *services.ProductService
+	workers      []backgroundWorker
+	closers      []resourceCloser
+
+	configManager *config.Manager
+}
+
+// Pekerjaan latar belakang yang berjalan selama server hidup dan berhenti saat ctx dibatalkan
+type backgroundWorker struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Resource yang ditutup setelah request dan worker selesai
+type resourceCloser struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// Opsi tambahan untuk App
+type Option func(*App)
+
+// Mengaktifkan read-through cache untuk pembacaan produk
+func WithProductCache(cache ports.ProductCache) Option {
+	return func(a *App) {
+		a.productCache = cache
+	}
+}
+
+// Mengaktifkan audit log untuk setiap penulisan produk
+func WithAuditRepository(auditRepo ports.AuditRepository) Option {
+	return func(a *App) {
+		a.auditRepo = auditRepo
+	}
+}
+
+// Mengaktifkan riwayat revisi dan restore produk
+func WithRevisionRepository(revisionRepo ports.RevisionRepository) Option {
+	return func(a *App) {
+		a.revisionRepo = revisionRepo
+	}
+}
+
+// Mewajibkan bearer token untuk semua endpoint /api
+func WithTokenVerifier(verifier ports.TokenVerifier) Option {
+	return func(a *App) {
+		a.verifier = verifier
+	}
+}
+
+// Mengaktifkan autentikasi X-API-Key dan endpoint /admin/api-keys
+func WithAPIKeyRepository(apiKeyRepo ports.APIKeyRepository) Option {
+	return func(a *App) {
+		a.apiKeyRepo = apiKeyRepo
+	}
+}
+
+// Memeriksa permission role/scope di service layer, tanpa authorizer semua pemanggil diizinkan
+func WithAuthorizer(authorizer ports.Authorizer) Option {
+	return func(a *App) {
+		a.authorizer = authorizer
+	}
 }
 
-func NewApp(config *config.Config, mongoRepo ports.MongoProductRepository, mysqlRepo ports.MySQLProductRepository) *App {
-	return &App{
-		config:    config,
-		fiberApp:  fiber.New(),
+// Mengaktifkan rate limit untuk endpoint /api sesuai konfigurasi RateLimit*
+func WithRateLimiter(limiter ports.RateLimiter) Option {
+	return func(a *App) {
+		a.rateLimiter = limiter
+	}
+}
+
+// Mengaktifkan header Idempotency-Key pada endpoint pembuatan produk
+func WithIdempotencyStore(store ports.IdempotencyStore) Option {
+	return func(a *App) {
+		a.idempotency = store
+	}
+}
+
+// Membuat ID produk di service agar MongoDB dan MySQL ditulis bersamaan
+func WithIDGenerator(generator ports.IDGenerator) Option {
+	return func(a *App) {
+		a.idGenerator = generator
+	}
+}
+
+// Mengaktifkan endpoint metrics Prometheus dan metric request HTTP serta cache
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(a *App) {
+		a.metrics = m
+	}
+}
+
+// Membuat span untuk setiap request HTTP dan method ProductService.
+// Tracer provider dipasang terlebih dahulu dengan tracing.Setup.
+func WithTracing() Option {
+	return func(a *App) {
+		a.tracing = true
+	}
+}
+
+// Menambahkan dependency yang diperiksa oleh /readyz. Dependency kritis yang tidak
+// tersedia membuat service tidak siap, dependency lain hanya membuat status degraded.
+func WithHealthCheck(name string, checker ports.HealthChecker, critical bool) Option {
+	return func(a *App) {
+		a.healthDeps = append(a.healthDeps, services.HealthDependency{Name: name, Checker: checker, Critical: critical})
+	}
+}
+
+// Melaporkan circuit breaker store pada /readyz (dependency dengan nama yang sama)
+// dan metrics. Repository dibungkus dengan kebijakan resilience sebelum NewApp.
+func WithResiliencePolicy(name string, policy ports.ResiliencePolicy) Option {
+	return func(a *App) {
+		if a.resilience == nil {
+			a.resilience = make(map[string]ports.ResiliencePolicy)
+		}
+		a.resilience[name] = policy
+	}
+}
+
+// Mengaktifkan hot reload: batas rate limit, kebijakan penulisan dan read fallback dibaca
+// dari konfigurasi aktif setiap request. Endpoint GET /admin/config dan POST /admin/config/reload
+// hanya dipasang jika autentikasi JWT atau API key aktif.
+func WithConfigManager(manager *config.Manager) Option {
+	return func(a *App) {
+		a.configManager = manager
+	}
+}
+
+// Menjalankan worker latar belakang (misalnya sinkronisasi change stream) bersama server.
+// Saat shutdown ctx worker dibatalkan setelah request yang berjalan selesai.
+func WithBackgroundWorker(name string, run func(ctx context.Context) error) Option {
+	return func(a *App) {
+		a.workers = append(a.workers, backgroundWorker{name: name, run: run})
+	}
+}
+
+// Menutup resource (misalnya koneksi database) di akhir shutdown, sesuai urutan pendaftaran
+func WithCloser(name string, close func(ctx context.Context) error) Option {
+	return func(a *App) {
+		a.closers = append(a.closers, resourceCloser{name: name, close: close})
+	}
+}
+
+func NewApp(config *config.Config, mongoRepo ports.MongoProductRepository, mysqlRepo ports.MySQLProductRepository, opts ...Option) *App {
+	a := &App{
+		config: config,
+		fiberApp: fiber.New(fiber.Config{
+			ReadTimeout:  config.ServerReadTimeout,
+			WriteTimeout: config.ServerWriteTimeout,
+			IdleTimeout:  config.ServerIdleTimeout,
+			BodyLimit:    config.ServerBodyLimit,
+		}),
 		mongoRepo: mongoRepo,
 		mysqlRepo: mysqlRepo,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for i, dependency := range a.healthDeps {
+		a.healthDeps[i].Resilience = a.resilience[dependency.Name]
+	}
+	a.health = services.NewHealthService(config.HealthCheckTimeout, a.healthDeps...)
+	return a
 }
 
 func (a *App) SetupRoutes() {
-	productService := services.NewProductService(a.mongoRepo, a.mysqlRepo)
-	productHandler := handlers.NewProductHandler(productService)
+	if a.tracing {
+		a.fiberApp.Use(tracing.Middleware())
+	}
+	if a.metrics != nil {
+		a.fiberApp.Use(a.metrics.Middleware())
+		if len(a.resilience) > 0 {
+			policies := make([]ports.ResiliencePolicy, 0, len(a.resilience))
+			for _, policy := range a.resilience {
+				policies = append(policies, policy)
+			}
+			if err := a.metrics.RegisterResilienceStats(policies...); err != nil {
+				slog.Warn("failed to register resilience metrics", "error", err)
+			}
+		}
+		a.fiberApp.Get(a.config.MetricsPath, a.metrics.Handler())
+	}
+
+	// Liveness dan readiness untuk orchestrator, tanpa autentikasi
+	healthHandler := handlers.NewHealthHandler(a.health)
+	a.fiberApp.Get("/healthz", healthHandler.Liveness)
+	a.fiberApp.Get("/readyz", healthHandler.Readiness)
+
+	serviceOpts := []services.ProductServiceOption{services.WithAuthorizer(a.authorizer)}
+	if a.auditRepo != nil {
+		serviceOpts = append(serviceOpts, services.WithAuditLog(a.auditRepo))
+	}
+	if a.revisionRepo != nil {
+		serviceOpts = append(serviceOpts, services.WithRevisions(a.revisionRepo))
+	}
+	serviceOpts = append(serviceOpts,
+		services.WithDynamicReadFallback(a.readFallbackPolicy),
+		services.WithDynamicWritePolicy(a.writePolicy),
+	)
+	if a.idGenerator != nil {
+		serviceOpts = append(serviceOpts, services.WithIDGenerator(a.idGenerator))
+	}
+	if a.config.ClientProductIDs {
+		serviceOpts = append(serviceOpts, services.WithClientIDs())
+	}
+	a.products = services.NewProductService(a.mongoRepo, a.mysqlRepo, serviceOpts...)
+	var productService ports.ProductService = a.products
+	if a.productCache != nil {
+		cachedService := services.NewCachedProductService(productService, a.productCache, a.config.CacheTTL, a.authorizer,
+			services.WithTenantCacheTTL(a.config.TenantCacheTTL()),
+			services.WithCacheFetchTimeout(a.cacheFetchTimeout()))
+		if a.metrics != nil {
+			if err := a.metrics.RegisterCacheStats(cachedService.Stats); err != nil {
+				slog.Warn("failed to register cache metrics", "error", err)
+			}
+		}
+		productService = cachedService
+	}
+	if a.tracing {
+		productService = tracing.InstrumentProductService(productService)
+	}
+	handlerOpts := []handlers.ProductHandlerOption{
+		handlers.WithCacheControl(a.config.HTTPCacheControl),
+		handlers.WithTenantCacheControl(a.config.TenantHTTPCacheControl()),
+	}
+	var revisionService ports.RevisionService
+	if a.revisionRepo != nil {
+		revisionService = services.NewRevisionService(productService, a.revisionRepo, a.authorizer)
+		handlerOpts = append(handlerOpts, handlers.WithRevisionService(revisionService))
+	}
+	productHandler := handlers.NewProductHandler(productService, handlerOpts...)
+
+	var apiKeyService *services.APIKeyService
+	if a.apiKeyRepo != nil {
+		apiKeyService = services.NewAPIKeyService(a.apiKeyRepo)
+	}
 
 	api := a.fiberApp.Group("/api")
-	api.Use(logger.New())
+	a.useRequestMiddleware(api, apiKeyService)
+	api.Use(handlers.TenantMiddleware(handlers.TenantResolverConfig{
+		BaseDomain: a.config.TenantBaseDomain,
+		Tenants:    a.config.TenantIDs(),
+	}))
+	if a.rateLimiter != nil {
+		api.Use(handlers.DynamicRateLimitMiddleware(a.rateLimiter, a.rateLimitConfig))
+	}
 
 	products := api.Group("/products")
 	products.Get("/", productHandler.ListProducts)
-	products.Post("/", productHandler.CreateProduct)
+	products.Post("/", a.idempotent(productHandler.CreateProduct)...)
+	// Route pencarian didaftarkan sebelum /:id agar tidak dianggap sebagai ID
+	products.Get("/by-sku/:sku", productHandler.GetProductBySKU)
+	products.Get("/by-barcode/:code", productHandler.GetProductByBarcode)
+	products.Get("/by-slug/:slug", productHandler.GetProductBySlug)
 	products.Get("/:id", productHandler.GetProduct)
 	products.Put("/:id", productHandler.UpdateProduct)
 	products.Delete("/:id", productHandler.DeleteProduct)
+
+	if a.auditRepo != nil {
+		auditHandler := handlers.NewAuditHandler(services.NewAuditService(a.auditRepo, a.authorizer))
+		products.Get("/:id/audit", auditHandler.ListProductAudit)
+		api.Get("/audit", auditHandler.QueryAudit)
+	}
+	if revisionService != nil {
+		revisionHandler := handlers.NewRevisionHandler(revisionService)
+		products.Get("/:id/revisions", revisionHandler.ListRevisions)
+		products.Post("/:id/revisions/:rev/restore", revisionHandler.RestoreRevision)
+	}
+
+	// Endpoint admin selalu memerlukan scope admin, sehingga tidak dipasang tanpa autentikasi
+	if a.configManager != nil && a.verifier == nil && apiKeyService == nil {
+		slog.Warn("admin config endpoints disabled because authentication is not enabled")
+	}
+	if a.verifier != nil || apiKeyService != nil {
+		admin := a.fiberApp.Group("/admin")
+		a.useRequestMiddleware(admin, apiKeyService)
+		admin.Use(handlers.RequireScope(handlers.ScopeAdmin))
+
+		if apiKeyService != nil {
+			apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+			apiKeys := admin.Group("/api-keys")
+			apiKeys.Get("/", apiKeyHandler.ListAPIKeys)
+			apiKeys.Post("/", apiKeyHandler.IssueAPIKey)
+			apiKeys.Post("/:id/rotate", apiKeyHandler.RotateAPIKey)
+			apiKeys.Delete("/:id", apiKeyHandler.RevokeAPIKey)
+		}
+		if a.configManager != nil {
+			configHandler := handlers.NewConfigHandler(a.configManager)
+			admin.Get("/config", configHandler.GetConfig)
+			admin.Post("/config/reload", configHandler.ReloadConfig)
+		}
+	}
+}
+
+// Middleware request ID, logging, informasi request, rate limit per IP dan autentikasi untuk satu group
+func (a *App) useRequestMiddleware(router fiber.Router, apiKeyService *services.APIKeyService) {
+	router.Use(handlers.RequestIDMiddleware())
+	router.Use(handlers.RequestInfoMiddleware())
+	router.Use(handlers.RequestLogger())
+	if a.rateLimiter != nil {
+		// Dibatasi per IP sebelum kredensial diperiksa
+		router.Use(handlers.IPRateLimitMiddleware(a.rateLimiter, a.ipRateLimit))
+	}
+	if a.verifier != nil || apiKeyService != nil {
+		// Actor diambil dari principal, header X-Actor-ID diabaikan
+		var apiKeys ports.APIKeyAuthenticator
+		if apiKeyService != nil {
+			apiKeys = apiKeyService
+		}
+		router.Use(handlers.AuthMiddleware(a.verifier, apiKeys))
+	} else {
+		router.Use(handlers.ActorMiddleware(a.config.ActorTrustedProxyPrefixes()))
+	}
+}
+
+// Menambahkan middleware Idempotency-Key sebelum handler jika diaktifkan
+func (a *App) idempotent(handler fiber.Handler) []fiber.Handler {
+	if a.idempotency == nil {
+		return []fiber.Handler{handler}
+	}
+	return []fiber.Handler{handlers.IdempotencyMiddleware(a.idempotency, a.config.IdempotencyTTL), handler}
+}
+
+// Batas rate limit dari konfigurasi aktif
+func (a *App) rateLimitConfig() handlers.RateLimitConfig {
+	cfg := a.currentConfig()
+	period := cfg.RateLimitPeriod
+	return handlers.RateLimitConfig{
+		Read:        domain.RateLimit{Limit: cfg.RateLimitRead, Period: period},
+		Write:       domain.RateLimit{Limit: cfg.RateLimitWrite, Period: period},
+		TenantRead:  domain.RateLimit{Limit: cfg.RateLimitTenantRead, Period: period},
+		TenantWrite: domain.RateLimit{Limit: cfg.RateLimitTenantWrite, Period: period},
+	}
+}
+
+// Batas rate limit per IP dari konfigurasi aktif
+func (a *App) ipRateLimit() domain.RateLimit {
+	cfg := a.currentConfig()
+	return domain.RateLimit{Limit: cfg.RateLimitIP, Period: cfg.RateLimitPeriod}
+}
+
+// Kebijakan pembacaan dari MySQL per route dari konfigurasi aktif
+func (a *App) readFallbackPolicy() services.ReadFallbackPolicy {
+	policy := services.ReadFallbackPolicy{Unavailable: resilience.IsMongoTransient}
+	timeouts := a.currentConfig().ReadFallbackTimeouts()
+	if timeout, ok := timeouts[handlers.RouteGetProduct]; ok {
+		policy.Get = services.ReadFallback{Enabled: true, Timeout: timeout}
+	}
+	if timeout, ok := timeouts[handlers.RouteListProducts]; ok {
+		policy.List = services.ReadFallback{Enabled: true, Timeout: timeout}
+	}
+	return policy
 }
 
-func (a *App) Start() error {
+// Batas waktu pembacaan bersama saat cache miss: setiap percobaan repository dibatasi
+// repository_timeout, dan pembacaan bisa dicoba ulang lalu jatuh ke MySQL (fallback)
+func (a *App) cacheFetchTimeout() time.Duration {
+	if a.config.RepositoryTimeout <= 0 {
+		return 0
+	}
+	return 2 * time.Duration(max(a.config.RetryMaxAttempts, 1)) * a.config.RepositoryTimeout
+}
+
+// Kebijakan penulisan produk dari konfigurasi aktif
+func (a *App) writePolicy() services.WritePolicy {
+	cfg := a.currentConfig()
+	return services.WritePolicy{
+		Consistency: services.WriteConsistency(cfg.WriteConsistency),
+		Timeout:     cfg.WriteTimeout,
+	}
+}
+
+// Konfigurasi terbaru jika hot reload aktif, selain itu konfigurasi awal
+func (a *App) currentConfig() *config.Config {
+	if a.configManager != nil {
+		return a.configManager.Current()
+	}
+	return a.config
+}
+
+// Menjalankan server dan worker sampai ctx selesai (misalnya SIGINT/SIGTERM), lalu shutdown:
+// readiness menjadi tidak siap, server berhenti menerima koneksi dan menunggu request yang
+// berjalan, worker dihentikan, kemudian resource ditutup. Seluruh shutdown dibatasi ShutdownTimeout.
+func (a *App) Run(ctx context.Context) error {
 	a.SetupRoutes()
-	return a.fiberApp.Listen(a.config.ServerAddress)
+
+	listener, err := net.Listen("tcp", a.config.ServerAddress)
+	if err != nil {
+		return errors.Join(err, a.closeResources(context.Background()))
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	for _, worker := range a.workers {
+		workers.Add(1)
+		go func(worker backgroundWorker) {
+			defer workers.Done()
+			if err := worker.run(workerCtx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("background worker stopped", "worker", worker.name, "error", err)
+			}
+		}(worker)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- a.fiberApp.Listener(listener)
+	}()
+	slog.Info("server started", "address", listener.Addr().String())
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown started")
+	case runErr = <-serveErr:
+		slog.Error("server stopped unexpectedly", "error", runErr)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+	defer cancel()
+	return errors.Join(runErr, a.shutdown(shutdownCtx, listener, stopWorkers, &workers))
+}
+
+// Urutan shutdown setelah sinyal diterima
+func (a *App) shutdown(ctx context.Context, listener net.Listener, stopWorkers context.CancelFunc, workers *sync.WaitGroup) error {
+	var errs []error
+
+	// Beri waktu load balancer melihat /readyz gagal sebelum koneksi ditolak
+	a.health.MarkShuttingDown()
+	if a.config.ShutdownDelay > 0 {
+		select {
+		case <-time.After(a.config.ShutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	// Berhenti menerima koneksi dan tunggu request yang berjalan (termasuk penulisan ganda) selesai
+	if err := a.fiberApp.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server: %w", err))
+	}
+	listener.Close()
+
+	// Tunggu penulisan produk yang berlanjut di background setelah ack
+	if a.products != nil {
+		if err := a.products.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("product writes: %w", err))
+		}
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background workers: %w", ctx.Err()))
+	}
+
+	errs = append(errs, a.closeResources(ctx))
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("shutdown completed")
+	return nil
+}
+
+// Menutup resource sesuai urutan pendaftaran, error tidak menghentikan penutupan resource berikutnya
+func (a *App) closeResources(ctx context.Context) error {
+	var errs []error
+	for _, closer := range a.closers {
+		if err := closer.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", closer.name, err))
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+// Actor yang dipakai jika request tidak membawa identitas
+const AnonymousActor = "anonymous"
+
+type actorContextKey struct{}
+
+// Menyimpan identitas actor (user atau sistem yang melakukan request) ke context
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// Mengambil identitas actor dari context, AnonymousActor jika tidak ada
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Error untuk API key
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+)
+
+// API key yang tersimpan. Key asli tidak pernah disimpan, hanya hash-nya.
+type APIKey struct {
+	// ID API key
+	ID string `json:"id" bson:"_id,omitempty"`
+
+	// Nama/keterangan pemilik key, misalnya nama batch tool
+	Name string `json:"name" bson:"name"`
+
+	// Prefix publik key untuk lookup
+	Prefix string `json:"prefix" bson:"prefix"`
+
+	// Hash SHA-256 dari key lengkap
+	Hash string `json:"-" bson:"hash"`
+
+	// Scope yang diberikan ke pemegang key
+	Scopes []string `json:"scopes" bson:"scopes"`
+
+	// Tenant pemegang key, kosong berarti key tidak terikat ke satu tenant
+	TenantID string `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"`
+
+	// Waktu key dibuat dan oleh siapa
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	CreatedBy string    `json:"created_by" bson:"created_by"`
+
+	// Waktu kedaluwarsa, nil berarti tidak kedaluwarsa
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+
+	// Waktu terakhir key dipakai
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+
+	// Waktu terakhir key dirotasi
+	RotatedAt *time.Time `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`
+
+	// Waktu key dicabut, nil berarti masih aktif
+	RevokedAt *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// Memeriksa apakah key masih bisa dipakai pada waktu tertentu
+func (k *APIKey) Active(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || at.Before(*k.ExpiresAt)
+}
+
+// Tenant yang boleh diakses pemegang key, kosong berarti tenant mana pun
+func (k *APIKey) AllowedTenant() string {
+	crossTenant := false
+	for _, scope := range k.Scopes {
+		if scope == ScopeCrossTenant {
+			crossTenant = true
+		}
+	}
+	return allowedTenant(k.TenantID, crossTenant)
+}
+
+// API key yang baru dibuat atau dirotasi, Key hanya ditampilkan sekali
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Request pembuatan API key
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	TenantID  string     `json:"tenant_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "time"
+
+// Jenis perubahan yang dicatat di audit log
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Perubahan satu field produk
+type FieldChange struct {
+	// Nama field sesuai nama JSON
+	Field string `json:"field" bson:"field"`
+
+	// Nilai sebelum perubahan, nil untuk create
+	Before interface{} `json:"before" bson:"before"`
+
+	// Nilai setelah perubahan, nil untuk delete
+	After interface{} `json:"after" bson:"after"`
+}
+
+// Satu catatan audit untuk setiap penulisan produk
+type AuditEntry struct {
+	// ID catatan audit
+	ID string `json:"id" bson:"_id,omitempty"`
+
+	// Tenant pemilik produk, diisi oleh repository dari context
+	TenantID string `json:"-" bson:"tenant_id,omitempty"`
+
+	// ID produk yang diubah
+	ProductID string `json:"product_id" bson:"product_id"`
+
+	// Jenis perubahan (create, update, delete)
+	Action string `json:"action" bson:"action"`
+
+	// Actor yang melakukan perubahan
+	Actor string `json:"actor" bson:"actor"`
+
+	// ID request yang melakukan perubahan
+	RequestID string `json:"request_id" bson:"request_id"`
+
+	// Alamat IP asal request
+	SourceIP string `json:"source_ip" bson:"source_ip"`
+
+	// Waktu perubahan
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+
+	// Perubahan per field
+	Changes []FieldChange `json:"changes" bson:"changes"`
+}
+
+// Filter pencarian audit log, field kosong diabaikan
+type AuditQuery struct {
+	ProductID string
+	Actor     string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// Membandingkan dua versi produk dan mengembalikan field yang berubah.
+// before nil berarti produk baru dibuat, after nil berarti produk dihapus.
+// Field audit (created_*, updated_*) tidak ikut dibandingkan.
+func DiffProducts(before, after *Product) []FieldChange {
+	fields := func(p *Product) map[string]interface{} {
+		if p == nil {
+			return nil
+		}
+		values := map[string]interface{}{
+			"name":  p.Name,
+			"price": p.Price,
+			"stock": p.Stock,
+		}
+		// Identifier opsional hanya dicatat jika diisi
+		for field, value := range map[string]string{"sku": p.SKU, "barcode": p.Barcode, "slug": p.Slug} {
+			if value != "" {
+				values[field] = value
+			}
+		}
+		return values
+	}
+	oldValues, newValues := fields(before), fields(after)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range []string{"name", "sku", "barcode", "slug", "price", "stock"} {
+		oldValue, hadOld := oldValues[field]
+		newValue, hasNew := newValues[field]
+		if (hadOld && hasNew && oldValue == newValue) || (!hadOld && !hasNew) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Before: oldValue, After: newValue})
+	}
+	return changes
+}
@@ -0,0 +1,39 @@
+package domain
+
+// Status kesehatan satu dependency
+const (
+	DependencyUp   = "up"
+	DependencyDown = "down"
+)
+
+// Status kesiapan service secara keseluruhan
+const (
+	// Semua dependency tersedia
+	HealthOK = "ok"
+	// Dependency non-kritis tidak tersedia, pembacaan tetap dilayani
+	HealthDegraded = "degraded"
+	// Dependency kritis tidak tersedia atau service sedang berhenti
+	HealthUnavailable = "unavailable"
+)
+
+// Hasil pemeriksaan satu dependency
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// Status circuit breaker jika store dilindungi kebijakan resilience
+	Circuit CircuitState `json:"circuit,omitempty"`
+}
+
+// Hasil pemeriksaan kesiapan service
+type HealthReport struct {
+	Status       string                      `json:"status"`
+	ShuttingDown bool                        `json:"shutting_down,omitempty"`
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+}
+
+// Service siap menerima request (termasuk mode degraded)
+func (r HealthReport) Ready() bool {
+	return r.Status != HealthUnavailable
+}
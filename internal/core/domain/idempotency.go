@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Hasil request yang disimpan untuk sebuah Idempotency-Key
+type IdempotencyRecord struct {
+	// Hash request (method, path dan body) yang pertama memakai key ini
+	Fingerprint string `json:"fingerprint"`
+
+	// Request pertama masih diproses, response belum tersedia
+	Pending bool `json:"pending"`
+
+	// Response yang dikirim ulang untuk request berikutnya dengan key yang sama
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+
+	// Waktu key pertama kali dipakai
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Penanda bahwa request sudah mengubah data, diisi oleh service agar response error
+// setelah perubahan tersimpan tidak membuka Idempotency-Key untuk diulang
+type WriteRecord struct {
+	Committed bool
+}
+
+type writeRecordContextKey struct{}
+
+// Menyiapkan penanda penulisan baru di context, dibaca kembali setelah pemanggilan service
+func ContextWithWriteRecord(ctx context.Context) (context.Context, *WriteRecord) {
+	record := &WriteRecord{}
+	return context.WithValue(ctx, writeRecordContextKey{}, record), record
+}
+
+// Menandai bahwa perubahan sudah tersimpan jika context memiliki penanda
+func RecordWriteCommitted(ctx context.Context) {
+	if record, ok := ctx.Value(writeRecordContextKey{}).(*WriteRecord); ok {
+		record.Committed = true
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field yang bisa dipakai untuk mengurutkan daftar produk
+const (
+	SortByName      = "name"
+	SortByPrice     = "price"
+	SortByStock     = "stock"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
+// Semua field yang bisa dipakai untuk pengurutan
+var ProductSortFields = []string{SortByName, SortByPrice, SortByStock, SortByCreatedAt, SortByUpdatedAt}
+
+// Opsi untuk mendapatkan daftar produk
+type ProductListOptions struct {
+	// Field pengurutan, kosong berarti urutan bawaan penyimpanan
+	SortBy string
+
+	// Urutkan dari nilai terbesar
+	Descending bool
+}
+
+// Membaca parameter sort, misalnya "name" (naik) atau "-updated_at" (turun)
+func ParseProductSort(sort string) (ProductListOptions, error) {
+	var opts ProductListOptions
+	if sort == "" {
+		return opts, nil
+	}
+	if strings.HasPrefix(sort, "-") {
+		opts.Descending = true
+		sort = sort[1:]
+	}
+	for _, field := range ProductSortFields {
+		if field == sort {
+			opts.SortBy = field
+			return opts, nil
+		}
+	}
+	return ProductListOptions{}, fmt.Errorf("invalid sort field %q", sort)
+}
+
+// Representasi string dari opsi pengurutan, kebalikan dari ParseProductSort
+func (o ProductListOptions) String() string {
+	if o.SortBy == "" {
+		return ""
+	}
+	if o.Descending {
+		return "-" + o.SortBy
+	}
+	return o.SortBy
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "fmt"
+
+// Permission untuk operasi produk
+const (
+	PermissionProductsRead        = "products:read"
+	PermissionProductsCreate      = "products:create"
+	PermissionProductsUpdate      = "products:update"
+	PermissionProductsAdjustStock = "products:adjust_stock"
+	PermissionProductsDelete      = "products:delete"
+	PermissionProductsRestore     = "products:restore"
+	PermissionAuditRead           = "audit:read"
+)
+
+// Error jika pemanggil tidak memiliki permission yang dibutuhkan
+type PermissionError struct {
+	// Permission yang tidak dimiliki
+	Permission string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("missing permission %s", e.Permission)
+}
@@ -0,0 +1,89 @@
+package domain
+
+import "context"
+
+// Scope (atau role) khusus
+const (
+	// Akses endpoint /admin
+	ScopeAdmin = "admin"
+
+	// Principal tanpa claim tenant yang boleh memilih tenant
+	// melalui header X-Tenant-ID atau subdomain
+	ScopeCrossTenant = "cross_tenant"
+)
+
+// Metode autentikasi principal
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// Identitas pemanggil yang sudah diautentikasi
+type Principal struct {
+	// Subject (misalnya user ID) dari token
+	Subject string `json:"subject"`
+
+	// Scope yang diberikan kepada pemanggil
+	Scopes []string `json:"scopes"`
+
+	// Role pemanggil
+	Roles []string `json:"roles"`
+
+	// Metode autentikasi yang dipakai
+	Method string `json:"method"`
+
+	// Tenant dari claim token atau API key, kosong jika principal tidak terikat ke satu tenant
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// Memeriksa apakah principal memiliki scope tertentu
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Memeriksa apakah principal memiliki role tertentu
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Memeriksa apakah principal memiliki scope atau role tertentu
+func (p *Principal) Has(scopeOrRole string) bool {
+	return p.HasScope(scopeOrRole) || p.HasRole(scopeOrRole)
+}
+
+// Tenant yang boleh diakses principal, kosong berarti tenant mana pun
+func (p *Principal) AllowedTenant() string {
+	return allowedTenant(p.TenantID, p.Has(ScopeCrossTenant))
+}
+
+// Principal tanpa claim tenant hanya berlaku untuk DefaultTenant, kecuali lintas tenant
+func allowedTenant(tenantID string, crossTenant bool) string {
+	if tenantID == "" && !crossTenant {
+		return DefaultTenant
+	}
+	return tenantID
+}
+
+type principalContextKey struct{}
+
+// Menyimpan principal ke context, sekaligus menjadikan subject sebagai actor
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	ctx = context.WithValue(ctx, principalContextKey{}, principal)
+	return ContextWithActor(ctx, principal.Subject)
+}
+
+// Mengambil principal dari context, nil jika request tidak diautentikasi
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
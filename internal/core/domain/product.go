@@ -1,16 +1,42 @@
 package domain
 
+import "time"
+
 // Struktur data produk
 type Product struct {
-    // ID produk (unik)
-    ID    string `json:"id" bson:"_id,omitempty" db:"product_id"`
-    
-    // Nama produk
-    Name  string `json:"name" bson:"name" db:"product_name"`
-    
-    // Harga produk
-    Price int    `json:"price" bson:"price" db:"price"`
-    
-    // Stok produk
-    Stock int    `json:"stock" bson:"stock" db:"stock"`
-}
\ No newline at end of file
+	// ID produk (unik)
+	ID string `json:"id" bson:"_id,omitempty" db:"product_id"`
+
+	// Tenant pemilik produk, diisi oleh repository dari context
+	TenantID string `json:"-" bson:"tenant_id,omitempty" db:"tenant_id"`
+
+	// Nama produk
+	Name string `json:"name" bson:"name" db:"product_name"`
+
+	// Kode SKU, unik per tenant
+	SKU string `json:"sku,omitempty" bson:"sku,omitempty" db:"sku"`
+
+	// Barcode GTIN/EAN, disimpan sebagai GTIN-14 dan unik per tenant
+	Barcode string `json:"barcode,omitempty" bson:"barcode,omitempty" db:"barcode"`
+
+	// Slug URL, dibuat dari nama jika kosong dan unik per tenant
+	Slug string `json:"slug,omitempty" bson:"slug,omitempty" db:"slug"`
+
+	// Harga produk
+	Price int `json:"price" bson:"price" db:"price"`
+
+	// Stok produk
+	Stock int `json:"stock" bson:"stock" db:"stock"`
+
+	// Waktu produk dibuat, diisi oleh ProductService
+	CreatedAt time.Time `json:"created_at" bson:"created_at" db:"created_at"`
+
+	// Waktu terakhir produk diubah, diisi oleh ProductService
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at" db:"updated_at"`
+
+	// Actor yang membuat produk, diisi oleh ProductService
+	CreatedBy string `json:"created_by" bson:"created_by" db:"created_by"`
+
+	// Actor yang terakhir mengubah produk, diisi oleh ProductService
+	UpdatedBy string `json:"updated_by" bson:"updated_by" db:"updated_by"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Error jika ID produk dari client tidak valid
+var ErrInvalidProductID = errors.New("invalid product id")
+
+// Error jika ID produk sudah dipakai
+var ErrProductExists = errors.New("product already exists")
+
+// ID produk: huruf, angka, "-" dan "_", maksimal 64 karakter agar aman dipakai di URL
+var productIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Memeriksa format ID produk dari client
+func ValidProductID(id string) bool {
+	return productIDPattern.MatchString(id)
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Identifier produk selain ID yang bisa dipakai untuk mencari produk
+type ProductLookup string
+
+const (
+	LookupSKU     ProductLookup = "sku"
+	LookupBarcode ProductLookup = "barcode"
+	LookupSlug    ProductLookup = "slug"
+)
+
+// Error jika produk tidak ditemukan saat pencarian berdasarkan identifier
+var ErrProductNotFound = errors.New("product not found")
+
+// Error jika SKU, barcode atau slug tidak valid
+var ErrInvalidProduct = errors.New("invalid product")
+
+// Panjang maksimum slug
+const MaxSlugLength = 128
+
+// SKU: huruf, angka, ".", "-" dan "_", maksimal 64 karakter
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Slug: huruf kecil dan angka dipisahkan satu "-"
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Memeriksa format SKU
+func ValidSKU(sku string) bool {
+	return skuPattern.MatchString(sku)
+}
+
+// Memeriksa format slug
+func ValidSlug(slug string) bool {
+	return len(slug) <= MaxSlugLength && slugPattern.MatchString(slug)
+}
+
+// Memeriksa GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN-13) atau GTIN-14 beserta check digit GS1
+// dan mengembalikannya dalam bentuk GTIN-14, sehingga UPC-A dan EAN-13 yang sama dianggap sama
+func NormalizeGTIN(code string) (string, error) {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: barcode must have 8, 12, 13 or 14 digits", ErrInvalidProduct)
+	}
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		if code[i] < '0' || code[i] > '9' {
+			return "", fmt.Errorf("%w: barcode must contain only digits", ErrInvalidProduct)
+		}
+		digit := int(code[i] - '0')
+		if i == len(code)-1 {
+			continue
+		}
+		// Bobot 3 dan 1 bergantian dimulai dari digit tepat sebelum check digit
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if check := (10 - sum%10) % 10; check != int(code[len(code)-1]-'0') {
+		return "", fmt.Errorf("%w: barcode check digit must be %d", ErrInvalidProduct, check)
+	}
+	return strings.Repeat("0", 14-len(code)) + code, nil
+}
+
+// Membuat slug dari teks: huruf kecil, selain huruf dan angka ASCII menjadi "-"
+func Slugify(text string) string {
+	var b strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if separator && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			separator = false
+			b.WriteRune(r)
+			continue
+		}
+		separator = true
+	}
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	return slug
+}
+
+// Memeriksa dan menormalkan SKU dan barcode, serta memeriksa format slug.
+// Identifier kosong berarti produk tidak memilikinya.
+func (p *Product) NormalizeIdentifiers() error {
+	p.SKU = strings.TrimSpace(p.SKU)
+	if p.SKU != "" && !ValidSKU(p.SKU) {
+		return fmt.Errorf("%w: sku must be 1-64 letters, digits, '.', '-' or '_'", ErrInvalidProduct)
+	}
+	if p.Barcode = strings.TrimSpace(p.Barcode); p.Barcode != "" {
+		barcode, err := NormalizeGTIN(p.Barcode)
+		if err != nil {
+			return err
+		}
+		p.Barcode = barcode
+	}
+	if p.Slug = strings.TrimSpace(p.Slug); p.Slug != "" && !ValidSlug(p.Slug) {
+		return fmt.Errorf("%w: slug must be lowercase letters and digits separated by '-', at most %d characters", ErrInvalidProduct, MaxSlugLength)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Batas token bucket: Limit request per Period, dengan kapasitas burst sebesar Limit
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Memeriksa apakah batas aktif
+func (l RateLimit) Enabled() bool {
+	return l.Limit > 0 && l.Period > 0
+}
+
+// Satu bucket yang diperiksa untuk sebuah request
+type RateLimitBucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+// Hasil pemeriksaan rate limit untuk satu bucket
+type RateLimitResult struct {
+	// Request diizinkan
+	Allowed bool
+
+	// Kapasitas bucket
+	Limit int
+
+	// Sisa token setelah request ini
+	Remaining int
+
+	// Waktu sampai bucket penuh kembali
+	Reset time.Duration
+
+	// Waktu tunggu sampai request berikutnya diizinkan, 0 jika Allowed
+	RetryAfter time.Duration
+}
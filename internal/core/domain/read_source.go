@@ -0,0 +1,34 @@
+package domain
+
+import "context"
+
+// Sumber data pembacaan produk
+const (
+	DataSourceMongo = "mongodb"
+	DataSourceMySQL = "mysql"
+	DataSourceCache = "cache"
+)
+
+// Sumber data satu pembacaan, diisi oleh service untuk header response
+type ReadSource struct {
+	Source string
+	// Data dibaca dari store sekunder yang mungkin tertinggal dari store utama
+	Stale bool
+	// Penyebab beralih ke store sekunder
+	Reason string
+}
+
+type readSourceContextKey struct{}
+
+// Menyiapkan pencatat sumber data baru di context, dibaca kembali setelah pemanggilan service
+func ContextWithReadSource(ctx context.Context) (context.Context, *ReadSource) {
+	source := &ReadSource{}
+	return context.WithValue(ctx, readSourceContextKey{}, source), source
+}
+
+// Mencatat sumber data jika context memiliki pencatat
+func RecordReadSource(ctx context.Context, source ReadSource) {
+	if recorder, ok := ctx.Value(readSourceContextKey{}).(*ReadSource); ok {
+		*recorder = source
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "context"
+
+// Informasi request yang dicatat di audit log
+type RequestInfo struct {
+	// ID request dari header X-Request-ID atau yang dibuat server
+	RequestID string
+
+	// Alamat IP asal request
+	SourceIP string
+}
+
+type requestInfoContextKey struct{}
+
+// Menyimpan informasi request ke context
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+// Mengambil informasi request dari context, kosong jika tidak ada
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoContextKey{}).(RequestInfo)
+	return info
+}
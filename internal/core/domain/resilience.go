@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Status circuit breaker sebuah store
+type CircuitState string
+
+const (
+	// Panggilan diteruskan ke store
+	CircuitClosed CircuitState = "closed"
+	// Panggilan langsung ditolak tanpa menghubungi store
+	CircuitOpen CircuitState = "open"
+	// Panggilan percobaan diizinkan untuk memeriksa apakah store sudah pulih
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Store sementara tidak bisa dipakai karena circuit breaker terbuka atau bulkhead penuh
+var ErrStoreUnavailable = errors.New("store temporarily unavailable")
+
+type storeTimeoutContextKey struct{}
+
+// Membatasi waktu panggilan store. Berbeda dengan pembatalan oleh pemanggil, batas waktu
+// ini dianggap kegagalan store oleh circuit breaker saat terlampaui (lihat StoreTimedOut).
+func ContextWithStoreTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(timeoutCtx, storeTimeoutContextKey{}, ctx), cancel
+}
+
+// Memeriksa apakah ctx selesai karena batas waktu dari ContextWithStoreTimeout,
+// bukan karena context pemanggil dibatalkan atau melewati batas waktunya sendiri
+func StoreTimedOut(ctx context.Context) bool {
+	parent, ok := ctx.Value(storeTimeoutContextKey{}).(context.Context)
+	return ok && errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil
+}
+
+// Statistik resilience satu store untuk metrics
+type ResilienceStats struct {
+	Store string
+	State CircuitState
+	// Panggilan yang sedang berjalan
+	InFlight int
+	// Jumlah percobaan ulang setelah error sementara
+	Retries uint64
+	// Panggilan yang ditolak karena circuit terbuka
+	ShortCircuits uint64
+	// Panggilan yang ditolak karena bulkhead penuh
+	BulkheadRejections uint64
+}
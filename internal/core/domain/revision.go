@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Error jika revisi produk tidak ditemukan
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Snapshot lengkap produk setelah satu penulisan
+type ProductRevision struct {
+	// Tenant pemilik produk, diisi oleh repository dari context
+	TenantID string `json:"-" bson:"tenant_id,omitempty"`
+
+	// ID produk
+	ProductID string `json:"product_id" bson:"product_id"`
+
+	// Nomor revisi, dimulai dari 1 dan naik setiap penulisan
+	Revision int `json:"revision" bson:"revision"`
+
+	// Kondisi produk setelah penulisan
+	Product Product `json:"product" bson:"product"`
+
+	// Produk dihapus pada revisi ini
+	Deleted bool `json:"deleted" bson:"deleted"`
+
+	// Actor yang melakukan penulisan
+	Actor string `json:"actor" bson:"actor"`
+
+	// Waktu penulisan
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+// Statistik cache hit/miss pembacaan produk
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
+}
+
+// Jumlah produk satu tenant untuk metrics bisnis
+type ProductStats struct {
+	TenantID string
+	// Jumlah seluruh produk
+	Total int64
+	// Jumlah produk dengan stok di bawah threshold
+	LowStock int64
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Tenant untuk request tanpa tenant dan untuk data yang dibuat sebelum multi-tenant
+const DefaultTenant = "default"
+
+// Error jika tenant ID tidak valid
+var ErrInvalidTenant = errors.New("invalid tenant id")
+
+// Tenant ID: huruf kecil, angka, "-" dan "_", maksimal 64 karakter
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Memeriksa format tenant ID
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+type tenantContextKey struct{}
+
+type allTenantsContextKey struct{}
+
+// Menyimpan tenant ke context
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// Mengambil tenant dari context, DefaultTenant jika tidak ada
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// Menandai context untuk proses internal yang bekerja lintas tenant
+// (misalnya sinkronisasi change stream). Tidak boleh dipakai untuk request client.
+func ContextWithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsContextKey{}, true)
+}
+
+// Memeriksa apakah context bekerja lintas tenant
+func AllTenantsFromContext(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsContextKey{}).(bool)
+	return all
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+)
+
+// Interface untuk memverifikasi bearer token
+type TokenVerifier interface {
+	// Memverifikasi token dan mengembalikan principal pemiliknya
+	VerifyToken(ctx context.Context, token string) (*domain.Principal, error)
+}
+
+// Interface untuk memverifikasi API key
+type APIKeyAuthenticator interface {
+	// Memverifikasi API key dan mengembalikan principal pemiliknya
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+// Interface untuk memeriksa permission pemanggil
+type Authorizer interface {
+	// Mengembalikan *domain.PermissionError jika principal di context tidak memiliki permission
+	Authorize(ctx context.Context, permission string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import "time"
+
+// Interface untuk penyimpanan cache berbasis key-value
+type ProductCache interface {
+	// Mengambil nilai dari cache, found bernilai false jika tidak ada atau sudah kedaluwarsa
+	Get(key string) (value []byte, found bool, err error)
+
+	// Menyimpan nilai ke cache dengan masa berlaku ttl
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Menghapus satu atau beberapa key dari cache
+	Delete(keys ...string) error
+}
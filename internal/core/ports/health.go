@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+)
+
+// Interface untuk dependency yang bisa diperiksa ketersediaannya
+type HealthChecker interface {
+	// Memeriksa koneksi ke dependency, error berarti tidak tersedia
+	Ping(ctx context.Context) error
+}
+
+// Interface untuk layanan liveness dan readiness
+type HealthService interface {
+	// Memeriksa semua dependency dan menentukan status kesiapan
+	Readiness(ctx context.Context) domain.HealthReport
+}
+
+// Interface untuk membaca status circuit breaker, retry dan bulkhead satu store
+type ResiliencePolicy interface {
+	// Status circuit breaker saat ini
+	CircuitState() domain.CircuitState
+
+	// Statistik untuk metrics
+	Stats() domain.ResilienceStats
+}
@@ -0,0 +1,8 @@
+package ports
+
+// Interface untuk pembuat ID produk, dipanggil sebelum penulisan
+// agar semua store bisa ditulis bersamaan dengan ID yang sama
+type IDGenerator interface {
+	// Membuat ID baru yang unik
+	NewID() string
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+)
+
+// Interface untuk penyimpanan Idempotency-Key
+type IdempotencyStore interface {
+	// Menyimpan record pending jika key belum ada (atomik). Jika key sudah ada,
+	// record yang tersimpan dikembalikan dengan reserved false.
+	Reserve(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) (existing *domain.IdempotencyRecord, reserved bool, err error)
+
+	// Memperpanjang masa berlaku key yang masih ada, misalnya record pending
+	// selama request masih diproses. Key yang sudah tidak ada diabaikan.
+	Extend(ctx context.Context, key string, ttl time.Duration) error
+
+	// Menyimpan response akhir untuk key
+	Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error
+
+	// Menghapus key agar request bisa diulang (misalnya setelah error server)
+	Release(ctx context.Context, key string) error
+}
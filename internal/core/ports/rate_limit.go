@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+)
+
+// Interface untuk penyimpanan token bucket rate limit
+type RateLimiter interface {
+	// Mengambil satu token dari bucket key sesuai batas limit
+	Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error)
+
+	// Mengambil satu token dari setiap bucket secara atomik, hanya jika semua bucket masih
+	// memiliki token. Hasil sesuai urutan buckets; bucket yang masih memiliki token tetap
+	// Allowed walaupun request ditolak oleh bucket lain.
+	AllowAll(ctx context.Context, buckets []domain.RateLimitBucket) ([]domain.RateLimitResult, error)
+}
@@ -1,39 +1,136 @@
 package ports
 
-import "go-fiber-hexagonal-product/internal/core/domain"
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+)
+
+// Repository produk, audit dan revisi membaca tenant dari context
+// (domain.TenantFromContext) dan hanya mengakses data milik tenant tersebut.
 
 // Interface untuk repository produk MongoDB
 type MongoProductRepository interface {
-    // Mendapatkan produk berdasarkan ID
-    GetProduct(id string) (*domain.Product, error)
-    
-    // Membuat produk baru dan mengembalikan ID dari MongoDB
-    CreateProduct(product *domain.Product) (string, error)
-    
-    // Mengupdate produk yang sudah ada
-    UpdateProduct(product *domain.Product) error
-    
-    // Menghapus produk berdasarkan ID
-    DeleteProduct(id string) error
-    
-    // Mendapatkan daftar produk
-    ListProducts() ([]*domain.Product, error)
+	// Mendapatkan produk berdasarkan ID
+	GetProduct(ctx context.Context, id string) (*domain.Product, error)
+
+	// Mendapatkan produk berdasarkan SKU, barcode atau slug, domain.ErrProductNotFound jika tidak ada
+	FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error)
+
+	// Membuat produk baru dengan product.ID jika sudah diisi, selain itu dengan ObjectID hex
+	// baru, dan mengembalikan ID yang disimpan. ID yang sudah dipakai menghasilkan domain.ErrProductExists.
+	CreateProduct(ctx context.Context, product *domain.Product) (string, error)
+
+	// Mengupdate produk yang sudah ada
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+
+	// Menghapus produk berdasarkan ID
+	DeleteProduct(ctx context.Context, id string) error
+
+	// Mendapatkan daftar produk
+	ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error)
 }
 
 // Interface untuk repository produk MySQL
 type MySQLProductRepository interface {
-    // Mendapatkan produk berdasarkan ID
-    GetProduct(id string) (*domain.Product, error)
-    
-    // Membuat produk baru
-    CreateProduct(product *domain.Product) error
-    
-    // Mengupdate produk yang sudah ada
-    UpdateProduct(product *domain.Product) error
-    
-    // Menghapus produk berdasarkan ID
-    DeleteProduct(id string) error
-    
-    // Mendapatkan daftar produk
-    ListProducts() ([]*domain.Product, error)
-}
\ No newline at end of file
+	// Mendapatkan produk berdasarkan ID, domain.ErrProductNotFound jika tidak ada
+	GetProduct(ctx context.Context, id string) (*domain.Product, error)
+
+	// Mendapatkan produk berdasarkan SKU, barcode atau slug, domain.ErrProductNotFound jika tidak ada
+	FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error)
+
+	// Membuat produk baru, ID yang sudah dipakai menghasilkan domain.ErrProductExists
+	CreateProduct(ctx context.Context, product *domain.Product) error
+
+	// Mengupdate produk yang sudah ada, domain.ErrProductNotFound jika tidak ada
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+
+	// Menyimpan produk: insert jika belum ada, update jika sudah ada. SKU, barcode atau slug
+	// yang sudah dipakai produk lain menghasilkan domain.ErrProductExists.
+	SaveProduct(ctx context.Context, product *domain.Product) error
+
+	// Menghapus produk berdasarkan ID
+	DeleteProduct(ctx context.Context, id string) error
+
+	// Mendapatkan daftar produk
+	ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error)
+}
+
+// Interface untuk store tambahan yang menerima salinan setiap penulisan produk.
+// MySQLProductRepository juga memenuhi interface ini.
+type ProductReplicaRepository interface {
+	// Membuat produk baru dengan ID yang sudah diisi
+	CreateProduct(ctx context.Context, product *domain.Product) error
+
+	// Mengupdate produk yang sudah ada
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+
+	// Menghapus produk berdasarkan ID
+	DeleteProduct(ctx context.Context, id string) error
+}
+
+// Interface untuk statistik produk seluruh tenant, dipakai untuk metrics bisnis
+type ProductStatsRepository interface {
+	// Menghitung jumlah produk dan produk dengan stok di bawah lowStockThreshold per tenant
+	ProductStats(ctx context.Context, lowStockThreshold int) ([]domain.ProductStats, error)
+}
+
+// Interface untuk penyimpanan resume token change stream
+type ResumeTokenStore interface {
+	// Mengambil resume token terakhir, nil jika belum pernah disimpan
+	LoadToken(name string) ([]byte, error)
+
+	// Menyimpan resume token terbaru
+	SaveToken(name string, token []byte) error
+
+	// Menghapus resume token, misalnya karena posisinya sudah tidak ada di oplog
+	DeleteToken(name string) error
+}
+
+// Interface untuk penyimpanan audit log produk (append-only)
+type AuditRepository interface {
+	// Menambahkan catatan audit baru
+	AppendAudit(ctx context.Context, entry *domain.AuditEntry) error
+
+	// Mencari catatan audit, diurutkan dari yang terbaru
+	ListAudit(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error)
+}
+
+// Interface untuk penyimpanan revisi produk
+type RevisionRepository interface {
+	// Menyimpan revisi baru dan mengisi nomor revisinya
+	SaveRevision(ctx context.Context, revision *domain.ProductRevision) error
+
+	// Mendapatkan semua revisi produk, diurutkan dari yang terbaru
+	ListRevisions(ctx context.Context, productID string) ([]*domain.ProductRevision, error)
+
+	// Mendapatkan revisi tertentu, domain.ErrRevisionNotFound jika tidak ada
+	GetRevision(ctx context.Context, productID string, revision int) (*domain.ProductRevision, error)
+
+	// Mendapatkan revisi terakhir pada atau sebelum waktu tertentu
+	GetRevisionAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.ProductRevision, error)
+}
+
+// Interface untuk penyimpanan API key
+type APIKeyRepository interface {
+	// Menyimpan API key baru dan mengisi ID-nya
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) error
+
+	// Mendapatkan API key berdasarkan ID, domain.ErrAPIKeyNotFound jika tidak ada
+	GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error)
+
+	// Mendapatkan API key berdasarkan prefix, domain.ErrAPIKeyNotFound jika tidak ada
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+
+	// Mendapatkan semua API key
+	ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error)
+
+	// Mengganti prefix dan hash API key (rotasi)
+	RotateAPIKey(ctx context.Context, id, prefix, hash string, rotatedAt time.Time) error
+
+	// Mencabut API key
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+
+	// Mencatat waktu terakhir API key dipakai
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -1,21 +1,67 @@
 package ports
 
-import "go-fiber-hexagonal-product/internal/core/domain"
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+)
 
 // Interface untuk layanan produk
 type ProductService interface {
-    // Mendapatkan produk berdasarkan ID
-    GetProduct(id string) (*domain.Product, error)
-    
-    // Membuat produk baru
-    CreateProduct(product *domain.Product) error
-    
-    // Mengupdate produk yang sudah ada
-    UpdateProduct(product *domain.Product) error
-    
-    // Menghapus produk berdasarkan ID
-    DeleteProduct(id string) error
-    
-    // Mendapatkan daftar produk
-    ListProducts() ([]*domain.Product, error)
-}
\ No newline at end of file
+	// Mendapatkan produk berdasarkan ID
+	GetProduct(ctx context.Context, id string) (*domain.Product, error)
+
+	// Mendapatkan produk berdasarkan SKU, barcode atau slug, domain.ErrProductNotFound jika tidak ada
+	FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error)
+
+	// Membuat produk baru
+	CreateProduct(ctx context.Context, product *domain.Product) error
+
+	// Mengupdate produk yang sudah ada
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+
+	// Menghapus produk berdasarkan ID
+	DeleteProduct(ctx context.Context, id string) error
+
+	// Menulis ulang produk sesuai snapshot, membuat ulang produk jika sudah dihapus
+	RestoreProduct(ctx context.Context, product *domain.Product) error
+
+	// Mendapatkan daftar produk
+	ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error)
+}
+
+// Interface untuk layanan audit log
+type AuditService interface {
+	// Mendapatkan riwayat audit satu produk
+	ListProductAudit(ctx context.Context, productID string) ([]*domain.AuditEntry, error)
+
+	// Mencari audit log berdasarkan actor dan rentang waktu
+	QueryAudit(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error)
+}
+
+// Interface untuk layanan revisi produk
+type RevisionService interface {
+	// Mendapatkan semua revisi produk
+	ListRevisions(ctx context.Context, productID string) ([]*domain.ProductRevision, error)
+
+	// Mendapatkan kondisi produk pada waktu tertentu
+	GetProductAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.Product, error)
+
+	// Mengembalikan produk ke kondisi pada revisi tertentu
+	RestoreRevision(ctx context.Context, productID string, revision int) (*domain.Product, error)
+}
+
+// Interface untuk layanan pengelolaan API key
+type APIKeyService interface {
+	// Membuat API key baru, dengan tenant dan scope yang tidak melebihi milik pemanggil
+	IssueAPIKey(ctx context.Context, request domain.APIKeyRequest) (*domain.IssuedAPIKey, error)
+
+	// Mendapatkan semua API key di tenant pemanggil (tanpa key asli)
+	ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error)
+
+	// Mengganti key rahasia, key lama langsung tidak berlaku
+	RotateAPIKey(ctx context.Context, id string) (*domain.IssuedAPIKey, error)
+
+	// Mencabut API key
+	RevokeAPIKey(ctx context.Context, id string) error
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Format API key: gfp_<prefix>_<secret>
+const (
+	apiKeyPrefix      = "gfp_"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+// Waktu terakhir dipakai hanya diperbarui paling sering sekali per interval ini
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyService struct {
+	apiKeyRepo ports.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo ports.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+func (s *APIKeyService) IssueAPIKey(ctx context.Context, request domain.APIKeyRequest) (*domain.IssuedAPIKey, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+	if request.TenantID != "" && !domain.ValidTenantID(request.TenantID) {
+		return nil, domain.ErrInvalidTenant
+	}
+	if err := authorizeAPIKeyRequest(domain.PrincipalFromContext(ctx), &request); err != nil {
+		return nil, err
+	}
+
+	plaintext, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := &domain.APIKey{
+		Name:      request.Name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    request.Scopes,
+		TenantID:  request.TenantID,
+		CreatedAt: now(),
+		CreatedBy: domain.ActorFromContext(ctx),
+		ExpiresAt: request.ExpiresAt,
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+	return &domain.IssuedAPIKey{APIKey: *key, Key: plaintext}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	principal := domain.PrincipalFromContext(ctx)
+	visible := make([]*domain.APIKey, 0, len(keys))
+	for _, key := range keys {
+		if canManageAPIKey(principal, key) {
+			visible = append(visible, key)
+		}
+	}
+	return visible, nil
+}
+
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id string) (*domain.IssuedAPIKey, error) {
+	key, err := s.getManagedAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Active(time.Now()) {
+		return nil, errors.New("api key is revoked or expired")
+	}
+
+	plaintext, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	rotatedAt := now()
+	if err := s.apiKeyRepo.RotateAPIKey(ctx, id, prefix, hash, rotatedAt); err != nil {
+		return nil, err
+	}
+	key.Prefix = prefix
+	key.Hash = hash
+	key.RotatedAt = &rotatedAt
+	return &domain.IssuedAPIKey{APIKey: *key, Key: plaintext}, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if _, err := s.getManagedAPIKey(ctx, id); err != nil {
+		return err
+	}
+	return s.apiKeyRepo.RevokeAPIKey(ctx, id, now())
+}
+
+// Mendapatkan API key yang boleh dikelola pemanggil. Key milik tenant lain
+// dilaporkan sebagai domain.ErrAPIKeyNotFound agar keberadaannya tidak terlihat.
+func (s *APIKeyService) getManagedAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := s.apiKeyRepo.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageAPIKey(domain.PrincipalFromContext(ctx), key) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAPIKeyNotFound, id)
+	}
+	return key, nil
+}
+
+// Memastikan key baru tidak memberi akses melebihi pembuatnya: tenant key mengikuti
+// tenant pembuat, dan setiap scope harus dimiliki pembuat. Admin boleh memberikan scope
+// apa pun kecuali cross_tenant. Tanpa principal (misalnya dari CLI) tidak ada batasan.
+func authorizeAPIKeyRequest(issuer *domain.Principal, request *domain.APIKeyRequest) error {
+	if issuer == nil {
+		return nil
+	}
+	if allowed := issuer.AllowedTenant(); allowed != "" {
+		if request.TenantID == "" {
+			request.TenantID = allowed
+		}
+		if request.TenantID != allowed {
+			return &domain.PermissionError{Permission: domain.ScopeCrossTenant}
+		}
+	}
+	for _, scope := range request.Scopes {
+		if issuer.Has(scope) || (issuer.Has(domain.ScopeAdmin) && scope != domain.ScopeCrossTenant) {
+			continue
+		}
+		return &domain.PermissionError{Permission: scope}
+	}
+	return nil
+}
+
+// Principal hanya boleh mengelola key di tenant yang boleh diaksesnya.
+// Principal lintas tenant dan pemanggil tanpa principal boleh mengelola semua key.
+func canManageAPIKey(principal *domain.Principal, key *domain.APIKey) bool {
+	if principal == nil {
+		return true
+	}
+	allowed := principal.AllowedTenant()
+	return allowed == "" || key.AllowedTenant() == allowed
+}
+
+// Memverifikasi API key dengan lookup prefix lalu membandingkan hash
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	hash := hashAPIKey(plaintext)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) != 1 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	usedAt := time.Now()
+	if !key.Active(usedAt) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// Catat pemakaian tanpa menulis ke database di setiap request
+	if key.LastUsedAt == nil || usedAt.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchAPIKey(ctx, key.ID, usedAt.UTC().Truncate(time.Millisecond)); err != nil {
+			slog.WarnContext(ctx, "failed to record api key usage", "api_key_id", key.ID, "error", err)
+		}
+	}
+
+	return &domain.Principal{
+		Subject:  "apikey:" + key.ID,
+		Scopes:   key.Scopes,
+		Method:   domain.AuthMethodAPIKey,
+		TenantID: key.TenantID,
+	}, nil
+}
+
+// Membuat key baru, mengembalikan key lengkap, prefix dan hash-nya
+func generateAPIKey() (plaintext, prefix, hash string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(prefixBytes)
+	plaintext = apiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+	return plaintext, prefix, hashAPIKey(plaintext), nil
+}
+
+// Mengambil prefix dari key berformat gfp_<prefix>_<secret>
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != apiKeyPrefixBytes*2 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// Key memiliki entropi tinggi sehingga SHA-256 cukup (tidak perlu bcrypt)
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
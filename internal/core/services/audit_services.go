@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+)
+
+type AuditService struct {
+	auditRepo  ports.AuditRepository
+	authorizer ports.Authorizer
+}
+
+// Membuat instance baru dari AuditService, authorizer nil berarti semua pemanggil diizinkan
+func NewAuditService(auditRepo ports.AuditRepository, authorizer ports.Authorizer) *AuditService {
+	return &AuditService{
+		auditRepo:  auditRepo,
+		authorizer: authorizer,
+	}
+}
+
+func (s *AuditService) ListProductAudit(ctx context.Context, productID string) ([]*domain.AuditEntry, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionAuditRead); err != nil {
+		return nil, err
+	}
+	return s.auditRepo.ListAudit(ctx, domain.AuditQuery{ProductID: productID})
+}
+
+func (s *AuditService) QueryAudit(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionAuditRead); err != nil {
+		return nil, err
+	}
+	return s.auditRepo.ListAudit(ctx, query)
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Decorator ProductService dengan read-through cache.
+// GetProduct dan ListProducts dibaca dari cache terlebih dahulu, setiap penulisan
+// menghapus key yang terdampak. Miss yang terjadi bersamaan untuk key yang sama
+// digabung menjadi satu pembacaan ke service di bawahnya (singleflight).
+type CachedProductService struct {
+	next       ports.ProductService
+	cache      ports.ProductCache
+	ttl        time.Duration
+	authorizer ports.Authorizer
+	group      singleflight.Group
+
+	// Masa berlaku cache per tenant, tenant lain memakai ttl
+	tenantTTL map[string]time.Duration
+
+	// Batas waktu pembacaan bersama saat miss, 0 berarti tanpa batas
+	fetchTimeout time.Duration
+
+	// Naik setiap kali terjadi invalidasi agar hasil load yang sedang berjalan
+	// tidak menimpa cache dengan data lama
+	generation atomic.Uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// Opsi tambahan untuk CachedProductService
+type CachedProductServiceOption func(*CachedProductService)
+
+// Mengatur masa berlaku cache untuk tenant tertentu
+func WithTenantCacheTTL(ttl map[string]time.Duration) CachedProductServiceOption {
+	return func(s *CachedProductService) {
+		for tenant, value := range ttl {
+			s.tenantTTL[tenant] = value
+		}
+	}
+}
+
+// Mengatur batas waktu pembacaan ke service di bawahnya saat miss. Pembacaan dipakai
+// bersama oleh semua pemanggil dengan key yang sama, sehingga tidak ikut dibatalkan
+// bersama request pemanggil pertama dan hanya dibatasi oleh timeout ini.
+func WithCacheFetchTimeout(timeout time.Duration) CachedProductServiceOption {
+	return func(s *CachedProductService) {
+		s.fetchTimeout = timeout
+	}
+}
+
+// Membuat instance baru dari CachedProductService.
+// Cache hit tidak melewati next, sehingga permission baca diperiksa di sini dengan authorizer
+// yang sama. Penulisan tetap diperiksa oleh next. Key cache dipisah per tenant.
+func NewCachedProductService(next ports.ProductService, cache ports.ProductCache, ttl time.Duration, authorizer ports.Authorizer, opts ...CachedProductServiceOption) *CachedProductService {
+	s := &CachedProductService{
+		next:       next,
+		cache:      cache,
+		ttl:        ttl,
+		authorizer: authorizer,
+		tenantTTL:  make(map[string]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Mendapatkan produk berdasarkan ID melalui cache
+func (s *CachedProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	value, err := s.load(ctx, productCacheKey(domain.TenantFromContext(ctx), id), func(ctx context.Context) (interface{}, error) {
+		return s.next.GetProduct(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if product, ok := value.(*domain.Product); ok {
+		return product, nil
+	}
+
+	var product domain.Product
+	if err := json.Unmarshal(value.([]byte), &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// Mendapatkan produk berdasarkan SKU, barcode atau slug tanpa cache karena
+// identifier bisa berpindah ke produk lain saat update
+func (s *CachedProductService) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	return s.next.FindProduct(ctx, lookup, value)
+}
+
+// Mendapatkan daftar produk melalui cache
+func (s *CachedProductService) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	value, err := s.load(ctx, productListCacheKey(domain.TenantFromContext(ctx), opts), func(ctx context.Context) (interface{}, error) {
+		return s.next.ListProducts(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if products, ok := value.([]*domain.Product); ok {
+		return products, nil
+	}
+
+	var products []*domain.Product
+	if err := json.Unmarshal(value.([]byte), &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Membuat produk baru lalu menghapus cache daftar produk
+func (s *CachedProductService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	err := s.next.CreateProduct(ctx, product)
+	s.invalidate(ctx, productListCacheKeys(domain.TenantFromContext(ctx))...)
+	return err
+}
+
+// Mengupdate produk lalu menghapus cache produk tersebut dan daftar produk
+func (s *CachedProductService) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	err := s.next.UpdateProduct(ctx, product)
+	tenant := domain.TenantFromContext(ctx)
+	s.invalidate(ctx, append(productListCacheKeys(tenant), productCacheKey(tenant, product.ID))...)
+	return err
+}
+
+// Menghapus produk lalu menghapus cache produk tersebut dan daftar produk
+func (s *CachedProductService) DeleteProduct(ctx context.Context, id string) error {
+	err := s.next.DeleteProduct(ctx, id)
+	tenant := domain.TenantFromContext(ctx)
+	s.invalidate(ctx, append(productListCacheKeys(tenant), productCacheKey(tenant, id))...)
+	return err
+}
+
+// Memulihkan produk lalu menghapus cache produk tersebut dan daftar produk
+func (s *CachedProductService) RestoreProduct(ctx context.Context, product *domain.Product) error {
+	err := s.next.RestoreProduct(ctx, product)
+	tenant := domain.TenantFromContext(ctx)
+	s.invalidate(ctx, append(productListCacheKeys(tenant), productCacheKey(tenant, product.ID))...)
+	return err
+}
+
+// Statistik cache sejak service dibuat
+func (s *CachedProductService) Stats() domain.CacheStats {
+	return domain.CacheStats{
+		Hits:   s.hits.Load(),
+		Misses: s.misses.Load(),
+		Errors: s.errors.Load(),
+	}
+}
+
+// Hasil fetch beserta sumber datanya, dibagikan ke semua pemanggil singleflight
+type loadResult struct {
+	value  interface{}
+	source domain.ReadSource
+}
+
+// Membaca key dari cache, atau memanggil fetch jika tidak ada.
+// Mengembalikan []byte jika hit, atau hasil fetch jika miss. Hasil yang stale
+// (dibaca dari store sekunder) tidak disimpan ke cache. Pemanggil yang request-nya
+// dibatalkan berhenti menunggu tanpa membatalkan fetch untuk pemanggil lain.
+func (s *CachedProductService) load(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	cached, found, err := s.cache.Get(key)
+	if err != nil {
+		// Cache bermasalah tidak boleh menggagalkan pembacaan
+		s.errors.Add(1)
+		slog.WarnContext(ctx, "failed to read cache", "key", key, "error", err)
+	}
+	if found {
+		s.hits.Add(1)
+		domain.RecordReadSource(ctx, domain.ReadSource{Source: domain.DataSourceCache})
+		return cached, nil
+	}
+	s.misses.Add(1)
+
+	results := s.group.DoChan(key, func() (interface{}, error) {
+		generation := s.generation.Load()
+		fetchCtx, cancel := s.fetchContext(ctx)
+		defer cancel()
+		fetchCtx, source := domain.ContextWithReadSource(fetchCtx)
+		value, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+		result := loadResult{value: value, source: *source}
+		if isNilResult(value) || source.Stale {
+			return result, nil
+		}
+
+		// Jangan simpan hasil jika sudah ada penulisan selama fetch berjalan
+		if s.generation.Load() != generation {
+			return result, nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cache.Set(key, data, s.ttlFor(domain.TenantFromContext(ctx))); err != nil {
+			s.errors.Add(1)
+			slog.WarnContext(ctx, "failed to write cache", "key", key, "error", err)
+		}
+		return result, nil
+	})
+	var shared singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case shared = <-results:
+	}
+	if shared.Err != nil {
+		return nil, shared.Err
+	}
+	result := shared.Val.(loadResult)
+	domain.RecordReadSource(ctx, result.source)
+	return result.value, nil
+}
+
+// Context pembacaan bersama yang membawa nilai request (tenant, principal, trace)
+// tanpa ikut dibatalkan bersama request tersebut
+func (s *CachedProductService) fetchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if s.fetchTimeout > 0 {
+		return context.WithTimeout(detached, s.fetchTimeout)
+	}
+	return context.WithCancel(detached)
+}
+
+// Masa berlaku cache untuk tenant
+func (s *CachedProductService) ttlFor(tenant string) time.Duration {
+	if ttl, ok := s.tenantTTL[tenant]; ok && ttl > 0 {
+		return ttl
+	}
+	return s.ttl
+}
+
+// Menghapus key dari cache setelah penulisan
+func (s *CachedProductService) invalidate(ctx context.Context, keys ...string) {
+	s.generation.Add(1)
+	if err := s.cache.Delete(keys...); err != nil {
+		s.errors.Add(1)
+		slog.WarnContext(ctx, "failed to invalidate cache", "keys", keys, "error", err)
+	}
+}
+
+func productCacheKey(tenant, id string) string {
+	return "products:" + tenant + ":" + id
+}
+
+// Setiap variasi pengurutan daftar produk disimpan di key terpisah
+func productListCacheKey(tenant string, opts domain.ProductListOptions) string {
+	return "products:" + tenant + ":list:" + opts.String()
+}
+
+// Semua key daftar produk tenant yang perlu dihapus saat ada penulisan
+func productListCacheKeys(tenant string) []string {
+	keys := []string{productListCacheKey(tenant, domain.ProductListOptions{})}
+	for _, field := range domain.ProductSortFields {
+		keys = append(keys,
+			productListCacheKey(tenant, domain.ProductListOptions{SortBy: field}),
+			productListCacheKey(tenant, domain.ProductListOptions{SortBy: field, Descending: true}),
+		)
+	}
+	return keys
+}
+
+// Hasil nil (misalnya produk tidak ditemukan) tidak disimpan ke cache
+func isNilResult(value interface{}) bool {
+	switch v := value.(type) {
+	case *domain.Product:
+		return v == nil
+	case []*domain.Product:
+		return v == nil
+	default:
+		return value == nil
+	}
+}
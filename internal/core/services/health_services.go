@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Dependency yang diperiksa oleh HealthService
+type HealthDependency struct {
+	Name    string
+	Checker ports.HealthChecker
+	// Dependency kritis yang tidak tersedia membuat service tidak siap,
+	// dependency non-kritis hanya membuat status degraded
+	Critical bool
+	// Kebijakan resilience store, circuit yang terbuka membuat dependency dianggap tidak tersedia
+	Resilience ports.ResiliencePolicy
+}
+
+// Layanan readiness yang memeriksa semua dependency secara paralel
+type HealthService struct {
+	dependencies []HealthDependency
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// Membuat instance baru dari HealthService, timeout berlaku untuk setiap dependency
+func NewHealthService(timeout time.Duration, dependencies ...HealthDependency) *HealthService {
+	return &HealthService{
+		dependencies: dependencies,
+		timeout:      timeout,
+	}
+}
+
+// Menandai service sedang berhenti sehingga readiness selalu gagal
+func (s *HealthService) MarkShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *HealthService) Readiness(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{
+		Status:       domain.HealthOK,
+		Dependencies: make(map[string]domain.DependencyHealth, len(s.dependencies)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dependency := range s.dependencies {
+		wg.Add(1)
+		go func(dependency HealthDependency) {
+			defer wg.Done()
+			health := s.check(ctx, dependency)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[dependency.Name] = health
+			if health.Status == domain.DependencyUp {
+				return
+			}
+			if dependency.Critical {
+				report.Status = domain.HealthUnavailable
+			} else if report.Status == domain.HealthOK {
+				report.Status = domain.HealthDegraded
+			}
+		}(dependency)
+	}
+	wg.Wait()
+
+	if s.shuttingDown.Load() {
+		report.Status = domain.HealthUnavailable
+		report.ShuttingDown = true
+	}
+	return report
+}
+
+// Memeriksa satu dependency dengan batas waktu
+func (s *HealthService) check(ctx context.Context, dependency HealthDependency) domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dependency.Checker.Ping(ctx)
+	health := domain.DependencyHealth{
+		Status:    domain.DependencyUp,
+		Critical:  dependency.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		health.Status = domain.DependencyDown
+		health.Error = err.Error()
+	}
+	if dependency.Resilience != nil {
+		health.Circuit = dependency.Resilience.CircuitState()
+		if health.Circuit == domain.CircuitOpen && err == nil {
+			health.Status = domain.DependencyDown
+			health.Error = "circuit breaker is open"
+		}
+	}
+	return health
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"strings"
+)
+
+// Role bawaan
+const (
+	RoleViewer     = "viewer"
+	RoleEditor     = "editor"
+	RoleStockClerk = "stock_clerk"
+	RoleAdmin      = "admin"
+)
+
+// Policy bawaan jika tidak ada file policy
+func DefaultPolicy() map[string][]string {
+	return map[string][]string{
+		RoleViewer:     {domain.PermissionProductsRead},
+		RoleEditor:     {domain.PermissionProductsRead, domain.PermissionProductsCreate, domain.PermissionProductsUpdate},
+		RoleStockClerk: {domain.PermissionProductsRead, domain.PermissionProductsAdjustStock},
+		RoleAdmin:      {"*"},
+	}
+}
+
+// Authorizer berbasis role dan scope.
+// Permission principal adalah gabungan permission dari setiap role-nya, ditambah
+// scope yang berupa permission langsung atau nama role. Pola "*" dan "products:*" didukung.
+type PolicyAuthorizer struct {
+	roles map[string][]string
+}
+
+// Membuat instance baru dari PolicyAuthorizer dengan pemetaan role -> permission
+func NewPolicyAuthorizer(roles map[string][]string) *PolicyAuthorizer {
+	return &PolicyAuthorizer{roles: roles}
+}
+
+// Memeriksa apakah principal di context memiliki permission
+func (a *PolicyAuthorizer) Authorize(ctx context.Context, permission string) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return &domain.PermissionError{Permission: permission}
+	}
+
+	for _, role := range principal.Roles {
+		if matchesAny(a.roles[role], permission) {
+			return nil
+		}
+	}
+	for _, scope := range principal.Scopes {
+		if matchesPermission(scope, permission) || matchesAny(a.roles[scope], permission) {
+			return nil
+		}
+	}
+	return &domain.PermissionError{Permission: permission}
+}
+
+func matchesAny(patterns []string, permission string) bool {
+	for _, pattern := range patterns {
+		if matchesPermission(pattern, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mencocokkan pola permission: "*", "products:*" atau nama permission persis
+func matchesPermission(pattern, permission string) bool {
+	if pattern == "*" || pattern == permission {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(permission, prefix)
+	}
+	return false
+}
+
+// Memeriksa permission jika authorizer dikonfigurasi, nil berarti semua diizinkan
+func authorize(ctx context.Context, authorizer ports.Authorizer, permission string) error {
+	if authorizer == nil {
+		return nil
+	}
+	return authorizer.Authorize(ctx, permission)
+}
@@ -1,65 +1,519 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go-fiber-hexagonal-product/internal/core/domain"
 	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Batas akhiran angka saat mencari slug yang belum dipakai
+const maxSlugSuffix = 100
+
 type ProductService struct {
-	mongoRepo ports.MongoProductRepository
-	mysqlRepo  ports.MySQLProductRepository
+	mongoRepo    ports.MongoProductRepository
+	mysqlRepo    ports.MySQLProductRepository
+	auditRepo    ports.AuditRepository
+	revisionRepo ports.RevisionRepository
+	authorizer   ports.Authorizer
+	readFallback func() ReadFallbackPolicy
+	writePolicy  func() WritePolicy
+	idGenerator  ports.IDGenerator
+	clientIDs    bool
+	// Replica tambahan di luar MongoDB dan MySQL
+	stores []productStore
+	// Penulisan yang sedang berjalan, termasuk yang berlanjut di background setelah ack
+	pending pendingWrites
+}
+
+// Opsi tambahan untuk ProductService
+type ProductServiceOption func(*ProductService)
+
+// Mencatat setiap penulisan produk ke audit log
+func WithAuditLog(auditRepo ports.AuditRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.auditRepo = auditRepo
+	}
+}
+
+// Menyimpan snapshot revisi produk pada setiap penulisan
+func WithRevisions(revisionRepo ports.RevisionRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.revisionRepo = revisionRepo
+	}
+}
+
+// Memeriksa permission pemanggil pada setiap operasi produk
+func WithAuthorizer(authorizer ports.Authorizer) ProductServiceOption {
+	return func(s *ProductService) {
+		s.authorizer = authorizer
+	}
+}
+
+// Pembacaan dari MySQL untuk satu endpoint saat MongoDB tidak tersedia
+type ReadFallback struct {
+	Enabled bool
+	// Batas waktu pembacaan MongoDB sebelum beralih ke MySQL, 0 berarti hanya saat error
+	Timeout time.Duration
+}
+
+// Kebijakan pembacaan degraded dari MySQL, yang memiliki salinan lengkap produk
+type ReadFallbackPolicy struct {
+	Get  ReadFallback
+	List ReadFallback
+	// Error MongoDB yang berarti store tidak tersedia, selain domain.ErrStoreUnavailable dan timeout
+	Unavailable func(error) bool
+}
+
+// Membaca dari MySQL jika MongoDB tidak tersedia atau melewati batas waktu.
+// Response ditandai stale melalui domain.RecordReadSource.
+func WithReadFallback(policy ReadFallbackPolicy) ProductServiceOption {
+	return WithDynamicReadFallback(func() ReadFallbackPolicy { return policy })
 }
 
-func NewProductService(mongoRepo ports.MongoProductRepository, mysqlRepo ports.MySQLProductRepository) *ProductService {
-	return &ProductService{
+// Sama seperti WithReadFallback, tetapi kebijakan dibaca setiap pembacaan
+// sehingga perubahan konfigurasi (hot reload) langsung berlaku
+func WithDynamicReadFallback(current func() ReadFallbackPolicy) ProductServiceOption {
+	return func(s *ProductService) {
+		s.readFallback = current
+	}
+}
+
+func NewProductService(mongoRepo ports.MongoProductRepository, mysqlRepo ports.MySQLProductRepository, opts ...ProductServiceOption) *ProductService {
+	s := &ProductService{
 		mongoRepo: mongoRepo,
 		mysqlRepo: mysqlRepo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *ProductService) GetProduct(id string) (*domain.Product, error) {
-	// Mengambil produk dari MongoDB
-	return s.mongoRepo.GetProduct(id)
+func (s *ProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	// Mengambil produk dari MongoDB, atau dari MySQL jika MongoDB tidak tersedia
+	policy := s.readFallbackPolicy()
+	return readWithFallback(ctx, policy, policy.Get, "GetProduct",
+		func(ctx context.Context) (*domain.Product, error) { return s.mongoRepo.GetProduct(ctx, id) },
+		func(ctx context.Context) (*domain.Product, error) { return s.mysqlRepo.GetProduct(ctx, id) },
+	)
 }
 
-func (s *ProductService) CreateProduct(product *domain.Product) error {
-	// Simpan ke MongoDB dan ambil ID yang dihasilkan
-	productID, err := s.mongoRepo.CreateProduct(product)
-	if err != nil {
+// Mendapatkan produk berdasarkan SKU, barcode atau slug
+func (s *ProductService) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	// Barcode disimpan sebagai GTIN-14, sehingga UPC-A dan EAN-13 juga bisa dipakai untuk mencari
+	if lookup == domain.LookupBarcode {
+		barcode, err := domain.NormalizeGTIN(value)
+		if err != nil {
+			return nil, err
+		}
+		value = barcode
+	}
+	policy := s.readFallbackPolicy()
+	return readWithFallback(ctx, policy, policy.Get, "FindProduct",
+		func(ctx context.Context) (*domain.Product, error) { return s.mongoRepo.FindProduct(ctx, lookup, value) },
+		func(ctx context.Context) (*domain.Product, error) { return s.mysqlRepo.FindProduct(ctx, lookup, value) },
+	)
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsCreate); err != nil {
+		return err
+	}
+
+	if err := product.NormalizeIdentifiers(); err != nil {
 		return err
 	}
 
-	// Update ID produk untuk MySQL
-	product.ID = productID
+	// Field audit selalu diisi oleh service, bukan dari input client
+	actor := domain.ActorFromContext(ctx)
+	product.CreatedAt = now()
+	product.UpdatedAt = product.CreatedAt
+	product.CreatedBy = actor
+	product.UpdatedBy = actor
+
+	apply := func(ctx context.Context, store productStore) error {
+		// Setiap store menerima salinan sendiri karena repository mengisi field seperti tenant
+		stored := *product
+		return store.create(ctx, &stored)
+	}
+	undo := func(ctx context.Context, store productStore) error {
+		return store.delete(ctx, product.ID)
+	}
 
-	// Simpan ke MySQL
-	if err := s.mysqlRepo.CreateProduct(product); err != nil {
+	// ID dibuat sebelum penulisan agar semua store bisa ditulis bersamaan
+	switch {
+	case s.clientIDs && product.ID != "":
+		if !domain.ValidProductID(product.ID) {
+			return fmt.Errorf("%w: must be 1-64 letters, digits, '-' or '_'", domain.ErrInvalidProductID)
+		}
+	case s.idGenerator != nil:
+		product.ID = s.idGenerator.NewID()
+	default:
+		product.ID = ""
+	}
+
+	if product.Slug == "" {
+		slug, err := s.generateSlug(ctx, product.Name)
+		if err != nil {
+			return err
+		}
+		product.Slug = slug
+	}
+
+	release, err := s.beginWrite()
+	if err != nil {
 		return err
 	}
+	defer release()
+
+	stores, acked := s.productStores(), 0
+	if product.ID == "" {
+		// Simpan ke MongoDB dan ambil ID yang dihasilkan, store lain ditulis setelahnya
+		var productID string
+		err := s.applyWithTimeout(ctx, stores[0], func(ctx context.Context, _ productStore) (err error) {
+			productID, err = s.mongoRepo.CreateProduct(ctx, product)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		product.ID = productID
+		stores, acked = stores[1:], 1
+	}
 
+	// Perubahan tetap dicatat jika MongoDB sudah berubah walaupun store lain gagal
+	changed, writeErr := s.write(ctx, "CreateProduct", product.ID, stores, acked, apply, undo)
+	if !changed {
+		return writeErr
+	}
+	s.recordCreated(ctx, "CreateProduct", product, writeErr)
 	return nil
 }
-func (s *ProductService) UpdateProduct(product *domain.Product) error {
-	// Mengupdate produk di MongoDB
-	if err := s.mongoRepo.UpdateProduct(product); err != nil {
+
+func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	// Permission diperiksa sebelum membaca store agar pemanggil tanpa akses tidak bisa
+	// mengetahui apakah produk ada
+	updateErr, err := s.authorizeUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ambil produk yang tersimpan agar data pembuatan tidak bisa diubah client
+	existing, err := s.mongoRepo.GetProduct(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	// Identifier yang tidak dikirim client tetap memakai nilai yang tersimpan
+	if product.SKU == "" {
+		product.SKU = existing.SKU
+	}
+	if product.Barcode == "" {
+		product.Barcode = existing.Barcode
+	}
+	if product.Slug == "" {
+		product.Slug = existing.Slug
+	}
+	if err := product.NormalizeIdentifiers(); err != nil {
+		return err
+	}
+	if updateErr != nil && !onlyStockChanged(existing, product) {
+		return updateErr
+	}
+	product.CreatedAt = existing.CreatedAt
+	product.CreatedBy = existing.CreatedBy
+	product.UpdatedAt = now()
+	product.UpdatedBy = domain.ActorFromContext(ctx)
+
+	release, err := s.beginWrite()
+	if err != nil {
 		return err
 	}
-	
-	// Mengupdate produk di MySQL
-	return s.mysqlRepo.UpdateProduct(product)
+	defer release()
+
+	// Mengupdate produk di semua store. Update yang gagal tidak dibatalkan pada store
+	// yang sudah berhasil, lihat WriteConsistencyAll.
+	changed, writeErr := s.write(ctx, "UpdateProduct", product.ID, s.productStores(), 0, func(ctx context.Context, store productStore) error {
+		stored := *product
+		return store.update(ctx, &stored)
+	}, nil)
+	if !changed {
+		return writeErr
+	}
+	s.recordChange(ctx, domain.AuditActionUpdate, product.ID, existing, product)
+	return writeErr
 }
 
-func (s *ProductService) DeleteProduct(id string) error {
-	// Hapus produk dari MongoDB
-	if err := s.mongoRepo.DeleteProduct(id); err != nil {
+func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsDelete); err != nil {
+		return err
+	}
+
+	// Simpan kondisi terakhir produk untuk audit log
+	var existing *domain.Product
+	if s.auditRepo != nil || s.revisionRepo != nil {
+		existing, _ = s.mongoRepo.GetProduct(ctx, id)
+	}
+
+	release, err := s.beginWrite()
+	if err != nil {
 		return err
 	}
+	defer release()
 
-	// Hapus produk dari MySQL
-	return s.mysqlRepo.DeleteProduct(id)
+	// Hapus produk dari semua store. Delete yang gagal tidak dibatalkan pada store
+	// yang sudah berhasil, lihat WriteConsistencyAll.
+	changed, writeErr := s.write(ctx, "DeleteProduct", id, s.productStores(), 0, func(ctx context.Context, store productStore) error {
+		return store.delete(ctx, id)
+	}, nil)
+	if !changed {
+		return writeErr
+	}
+	s.recordChange(ctx, domain.AuditActionDelete, id, existing, nil)
+	return writeErr
+}
+
+// Menulis ulang produk persis seperti snapshot. Identifier yang kosong pada snapshot
+// ikut dikosongkan, dan produk yang sudah dihapus dibuat kembali dengan ID yang sama.
+// Membutuhkan permission restore dan update, siapa pun pemanggilnya.
+func (s *ProductService) RestoreProduct(ctx context.Context, product *domain.Product) error {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRestore); err != nil {
+		return err
+	}
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsUpdate); err != nil {
+		return err
+	}
+	if err := product.NormalizeIdentifiers(); err != nil {
+		return err
+	}
+
+	existing, err := s.mongoRepo.GetProduct(ctx, product.ID)
+	if err != nil && !errors.Is(err, domain.ErrProductNotFound) {
+		return err
+	}
+	actor := domain.ActorFromContext(ctx)
+	product.UpdatedAt = now()
+	product.UpdatedBy = actor
+
+	var apply, undo storeAction = func(ctx context.Context, store productStore) error {
+		stored := *product
+		return store.update(ctx, &stored)
+	}, nil
+	if existing != nil {
+		product.CreatedAt = existing.CreatedAt
+		product.CreatedBy = existing.CreatedBy
+	} else {
+		// Produk sudah dihapus, sehingga restore juga membutuhkan permission create
+		if err := authorize(ctx, s.authorizer, domain.PermissionProductsCreate); err != nil {
+			return err
+		}
+		if product.CreatedAt.IsZero() {
+			product.CreatedAt = product.UpdatedAt
+			product.CreatedBy = actor
+		}
+		apply = func(ctx context.Context, store productStore) error {
+			stored := *product
+			return store.create(ctx, &stored)
+		}
+		undo = func(ctx context.Context, store productStore) error {
+			return store.delete(ctx, product.ID)
+		}
+	}
+
+	release, err := s.beginWrite()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	changed, writeErr := s.write(ctx, "RestoreProduct", product.ID, s.productStores(), 0, apply, undo)
+	if !changed {
+		return writeErr
+	}
+	if existing == nil {
+		s.recordCreated(ctx, "RestoreProduct", product, writeErr)
+		return nil
+	}
+	s.recordChange(ctx, domain.AuditActionUpdate, product.ID, existing, product)
+	return writeErr
+}
+
+func (s *ProductService) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	// Mendapatkan daftar produk dari MongoDB, atau dari MySQL jika MongoDB tidak tersedia
+	policy := s.readFallbackPolicy()
+	return readWithFallback(ctx, policy, policy.List, "ListProducts",
+		func(ctx context.Context) ([]*domain.Product, error) { return s.mongoRepo.ListProducts(ctx, opts) },
+		func(ctx context.Context) ([]*domain.Product, error) { return s.mysqlRepo.ListProducts(ctx, opts) },
+	)
+}
+
+// Kebijakan pembacaan degraded yang aktif, kosong berarti tanpa fallback
+func (s *ProductService) readFallbackPolicy() ReadFallbackPolicy {
+	if s.readFallback == nil {
+		return ReadFallbackPolicy{}
+	}
+	return s.readFallback()
+}
+
+// Membaca dari store utama, lalu dari store sekunder jika store utama tidak tersedia.
+// Jika store sekunder juga gagal, error store utama yang dikembalikan.
+func readWithFallback[T any](ctx context.Context, policy ReadFallbackPolicy, rule ReadFallback, operation string, primary, secondary func(context.Context) (T, error)) (T, error) {
+	primaryCtx, cancel := ctx, context.CancelFunc(func() {})
+	if rule.Enabled && rule.Timeout > 0 {
+		// Store yang lambat dihitung sebagai kegagalan oleh circuit breaker
+		primaryCtx, cancel = domain.ContextWithStoreTimeout(ctx, rule.Timeout)
+	}
+	value, err := primary(primaryCtx)
+	cancel()
+	if err == nil {
+		domain.RecordReadSource(ctx, domain.ReadSource{Source: domain.DataSourceMongo})
+		return value, nil
+	}
+	if !rule.Enabled || !policy.unavailable(ctx, err) {
+		return value, err
+	}
+
+	fallback, fallbackErr := secondary(ctx)
+	if fallbackErr != nil {
+		slog.ErrorContext(ctx, "read fallback to secondary store failed", "operation", operation, "error", err, "fallback_error", fallbackErr)
+		return value, err
+	}
+	slog.WarnContext(ctx, "serving read from secondary store", "operation", operation, "error", err)
+	domain.RecordReadSource(ctx, domain.ReadSource{Source: domain.DataSourceMySQL, Stale: true, Reason: err.Error()})
+	return fallback, nil
+}
+
+// Error store utama yang layak dialihkan ke store sekunder. Pembatalan oleh pemanggil,
+// error permission dan error bisnis seperti not found tidak dialihkan.
+func (p ReadFallbackPolicy) unavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, domain.ErrStoreUnavailable) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return p.Unavailable != nil && p.Unavailable(err)
+}
+
+// Update membutuhkan products:update. Pemanggil yang hanya memiliki
+// products:adjust_stock boleh mengupdate selama yang berubah hanya stok, sehingga
+// updateErr dikembalikan untuk diperiksa setelah produk tersimpan dibaca.
+// err berisi penolakan jika pemanggil tidak memiliki keduanya.
+func (s *ProductService) authorizeUpdate(ctx context.Context) (updateErr, err error) {
+	updateErr = authorize(ctx, s.authorizer, domain.PermissionProductsUpdate)
+	if updateErr == nil {
+		return nil, nil
+	}
+	if authorize(ctx, s.authorizer, domain.PermissionProductsAdjustStock) != nil {
+		return updateErr, updateErr
+	}
+	return updateErr, nil
+}
+
+// Memeriksa apakah update hanya mengubah stok produk
+func onlyStockChanged(existing, product *domain.Product) bool {
+	return existing.Name == product.Name && existing.Price == product.Price &&
+		existing.SKU == product.SKU && existing.Barcode == product.Barcode && existing.Slug == product.Slug
+}
+
+// Membuat slug dari nama produk. Jika sudah dipakai produk lain milik tenant,
+// slug diberi akhiran angka. Kemungkinan bentrok antar request yang bersamaan
+// tetap dicegah oleh index unik di setiap store.
+func (s *ProductService) generateSlug(ctx context.Context, name string) (string, error) {
+	base := domain.Slugify(name)
+	if base == "" {
+		return "", nil
+	}
+	slug := base
+	for suffix := 2; suffix <= maxSlugSuffix; suffix++ {
+		_, err := s.mongoRepo.FindProduct(ctx, domain.LookupSlug, slug)
+		if errors.Is(err, domain.ErrProductNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		tail := "-" + strconv.Itoa(suffix)
+		slug = strings.TrimRight(base[:min(len(base), domain.MaxSlugLength-len(tail))], "-") + tail
+	}
+	return "", fmt.Errorf("%w: slug %s", domain.ErrProductExists, base)
+}
+
+// Mencatat produk yang sudah tersimpan di MongoDB. Create tidak dilaporkan gagal walaupun
+// store lain tertinggal atau undo gagal, karena produk sudah ada dengan ID tersebut dan
+// mengulang request justru membuat duplikat.
+func (s *ProductService) recordCreated(ctx context.Context, operation string, product *domain.Product, writeErr error) {
+	if writeErr != nil {
+		slog.WarnContext(ctx, "product created with incomplete writes", "operation", operation, "product_id", product.ID, "error", writeErr)
+	}
+	s.recordChange(ctx, domain.AuditActionCreate, product.ID, nil, product)
+}
+
+// Mencatat perubahan yang sudah tersimpan. Kegagalan audit log dan revisi hanya ditulis
+// ke log karena perubahan produk tidak dibatalkan lagi.
+func (s *ProductService) recordChange(ctx context.Context, action, productID string, before, after *domain.Product) {
+	domain.RecordWriteCommitted(ctx)
+	s.recordAudit(ctx, action, productID, before, after)
+	s.recordRevision(ctx, productID, after)
+}
+
+// Mencatat perubahan produk ke audit log jika diaktifkan
+func (s *ProductService) recordAudit(ctx context.Context, action, productID string, before, after *domain.Product) {
+	if s.auditRepo == nil {
+		return
+	}
+	info := domain.RequestInfoFromContext(ctx)
+	entry := &domain.AuditEntry{
+		ProductID: productID,
+		Action:    action,
+		Actor:     domain.ActorFromContext(ctx),
+		RequestID: info.RequestID,
+		SourceIP:  info.SourceIP,
+		Timestamp: now(),
+		Changes:   domain.DiffProducts(before, after),
+	}
+	if err := s.auditRepo.AppendAudit(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit entry", "action", action, "product_id", productID, "error", err)
+	}
+}
+
+// Menyimpan snapshot produk setelah penulisan, product nil berarti produk dihapus
+func (s *ProductService) recordRevision(ctx context.Context, productID string, product *domain.Product) {
+	if s.revisionRepo == nil {
+		return
+	}
+	revision := &domain.ProductRevision{
+		ProductID: productID,
+		Actor:     domain.ActorFromContext(ctx),
+		Timestamp: now(),
+	}
+	if product != nil {
+		revision.Product = *product
+		revision.Timestamp = product.UpdatedAt
+	} else {
+		revision.Product.ID = productID
+		revision.Deleted = true
+	}
+	if err := s.revisionRepo.SaveRevision(ctx, revision); err != nil {
+		slog.ErrorContext(ctx, "failed to save product revision", "product_id", productID, "error", err)
+	}
 }
 
-func (s *ProductService) ListProducts() ([]*domain.Product, error) {
-	// Mendapatkan daftar produk dari MongoDB
-	return s.mongoRepo.ListProducts()
+// Waktu saat ini dalam UTC, dipotong ke milidetik sesuai presisi MongoDB dan MySQL
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
 }
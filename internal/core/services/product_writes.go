@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Tingkat konsistensi penulisan produk ke semua store
+type WriteConsistency string
+
+const (
+	// Ack setelah MongoDB berhasil, store lain ditulis di background
+	WriteConsistencyPrimary WriteConsistency = "primary"
+	// Semua store ditulis bersamaan, gagal jika salah satu store gagal. Create yang gagal
+	// dibatalkan pada store yang sudah berhasil. Update dan delete tidak bisa dibatalkan
+	// dengan aman (penulisan lain mungkin sudah menyusul), sehingga store yang berhasil tetap
+	// berubah: perubahan di MongoDB dicatat ke audit log dan revisi, write record ditandai
+	// tersimpan, lalu error dikembalikan. Store yang tertinggal disamakan dengan mengulang
+	// request yang sama (update dan delete idempoten) atau oleh sinkronisasi change stream.
+	WriteConsistencyAll WriteConsistency = "all"
+	// Semua store ditulis bersamaan, ack setelah mayoritas store berhasil
+	WriteConsistencyQuorum WriteConsistency = "quorum"
+)
+
+// Kebijakan penulisan produk ke MongoDB, MySQL dan replica tambahan
+type WritePolicy struct {
+	// Kosong berarti WriteConsistencyAll
+	Consistency WriteConsistency
+	// Batas waktu penulisan ke setiap store, 0 berarti tanpa batas. Penulisan tidak ikut
+	// dibatalkan bersama request agar store tidak berbeda karena client terputus.
+	Timeout time.Duration
+}
+
+// Menulis produk ke semua store sesuai tingkat konsistensi
+func WithWritePolicy(policy WritePolicy) ProductServiceOption {
+	return WithDynamicWritePolicy(func() WritePolicy { return policy })
+}
+
+// Sama seperti WithWritePolicy, tetapi kebijakan dibaca setiap penulisan
+// sehingga perubahan konfigurasi (hot reload) langsung berlaku
+func WithDynamicWritePolicy(current func() WritePolicy) ProductServiceOption {
+	return func(s *ProductService) {
+		s.writePolicy = current
+	}
+}
+
+// Kebijakan penulisan yang aktif, kosong berarti WriteConsistencyAll tanpa batas waktu
+func (s *ProductService) currentWritePolicy() WritePolicy {
+	if s.writePolicy == nil {
+		return WritePolicy{}
+	}
+	return s.writePolicy()
+}
+
+// Membuat ID produk sebelum penulisan agar semua store bisa ditulis bersamaan.
+// Tanpa pembuat ID, ID diambil dari MongoDB dan store lain ditulis setelahnya.
+func WithIDGenerator(generator ports.IDGenerator) ProductServiceOption {
+	return func(s *ProductService) {
+		s.idGenerator = generator
+	}
+}
+
+// Menerima ID produk dari client saat pembuatan. ID harus sesuai domain.ValidProductID,
+// keunikan dijamin oleh unique key di setiap store (domain.ErrProductExists).
+// Tanpa opsi ini ID dari client diabaikan.
+func WithClientIDs() ProductServiceOption {
+	return func(s *ProductService) {
+		s.clientIDs = true
+	}
+}
+
+// Menambahkan store yang menerima salinan setiap penulisan produk dan ikut dihitung pada quorum
+func WithReplica(name string, replica ports.ProductReplicaRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.stores = append(s.stores, productStore{
+			name:   name,
+			create: replica.CreateProduct,
+			update: replica.UpdateProduct,
+			delete: replica.DeleteProduct,
+		})
+	}
+}
+
+// Satu store tujuan penulisan produk
+type productStore struct {
+	name   string
+	create func(ctx context.Context, product *domain.Product) error
+	update func(ctx context.Context, product *domain.Product) error
+	delete func(ctx context.Context, id string) error
+}
+
+// Operasi penulisan pada satu store
+type storeAction func(ctx context.Context, store productStore) error
+
+// Hasil penulisan pada satu store
+type storeResult struct {
+	store productStore
+	err   error
+}
+
+// Store produk dengan MongoDB sebagai store utama di urutan pertama
+func (s *ProductService) productStores() []productStore {
+	primary := []productStore{
+		{
+			name: domain.DataSourceMongo,
+			create: func(ctx context.Context, product *domain.Product) error {
+				_, err := s.mongoRepo.CreateProduct(ctx, product)
+				return err
+			},
+			update: s.mongoRepo.UpdateProduct,
+			delete: s.mongoRepo.DeleteProduct,
+		},
+		{
+			name:   domain.DataSourceMySQL,
+			create: s.mysqlRepo.CreateProduct,
+			update: s.mysqlRepo.UpdateProduct,
+			delete: s.mysqlRepo.DeleteProduct,
+		},
+	}
+	return append(primary, s.stores...)
+}
+
+// Menulis ke store sesuai tingkat konsistensi. acked adalah jumlah store yang sudah ditulis
+// sebelumnya (MongoDB saat ID diambil dari MongoDB), stores tidak memuat store tersebut.
+// undo membatalkan penulisan yang berhasil jika penulisan gagal secara keseluruhan, nil
+// berarti penulisan yang berhasil dibiarkan (update dan delete).
+// changed bernilai true jika MongoDB sudah berubah sehingga perubahan perlu dicatat.
+// Harus dipanggil di antara beginWrite dan release-nya.
+func (s *ProductService) write(ctx context.Context, operation, productID string, stores []productStore, acked int, apply, undo storeAction) (changed bool, err error) {
+	total := len(stores) + acked
+	var need int
+	switch s.currentWritePolicy().Consistency {
+	case WriteConsistencyPrimary:
+		need = 1
+		if acked == 0 {
+			// MongoDB ditulis lebih dulu, store lain menyusul di background
+			if err := s.applyWithTimeout(ctx, stores[0], apply); err != nil {
+				return false, fmt.Errorf("%s: %w", stores[0].name, err)
+			}
+			acked, stores = 1, stores[1:]
+		}
+	case WriteConsistencyQuorum:
+		need = total/2 + 1
+	default:
+		need = total
+	}
+	need -= acked
+	if need <= 0 {
+		s.writeInBackground(ctx, operation, productID, stores, apply)
+		return true, nil
+	}
+
+	writeCtx, cancel := s.writeContext(ctx)
+	results := make(chan storeResult, len(stores))
+	for _, store := range stores {
+		s.pending.add()
+		go func(store productStore) {
+			defer s.pending.done()
+			results <- storeResult{store: store, err: apply(writeCtx, store)}
+		}(store)
+	}
+
+	var succeeded []productStore
+	var errs []error
+	for received := 1; received <= len(stores); received++ {
+		result := <-results
+		if result.err != nil {
+			slog.ErrorContext(ctx, "product write failed", "operation", operation, "store", result.store.name, "product_id", productID, "error", result.err)
+			errs = append(errs, fmt.Errorf("%s: %w", result.store.name, result.err))
+			continue
+		}
+		succeeded = append(succeeded, result.store)
+		if len(succeeded) == need {
+			// Ack diberikan, sisa store diselesaikan di background
+			s.collectInBackground(ctx, operation, productID, results, len(stores)-received, cancel)
+			return true, nil
+		}
+	}
+	cancel()
+
+	changed = acked > 0
+	for _, store := range succeeded {
+		if undo == nil {
+			changed = changed || store.name == domain.DataSourceMongo
+			continue
+		}
+		undoCtx, cancelUndo := s.writeContext(ctx)
+		undoErr := undo(undoCtx, store)
+		cancelUndo()
+		if undoErr != nil {
+			slog.ErrorContext(ctx, "failed to undo product write", "operation", operation, "store", store.name, "product_id", productID, "error", undoErr)
+			changed = changed || store.name == domain.DataSourceMongo
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+// Menulis ke store di background setelah ack, kegagalan hanya dicatat ke log
+func (s *ProductService) writeInBackground(ctx context.Context, operation, productID string, stores []productStore, apply storeAction) {
+	if len(stores) == 0 {
+		return
+	}
+	writeCtx, cancel := s.writeContext(ctx)
+	results := make(chan storeResult, len(stores))
+	for _, store := range stores {
+		s.pending.add()
+		go func(store productStore) {
+			defer s.pending.done()
+			results <- storeResult{store: store, err: apply(writeCtx, store)}
+		}(store)
+	}
+	s.collectInBackground(ctx, operation, productID, results, len(stores), cancel)
+}
+
+// Menunggu sisa hasil penulisan di background lalu melepas context penulisan
+func (s *ProductService) collectInBackground(ctx context.Context, operation, productID string, results <-chan storeResult, remaining int, cancel context.CancelFunc) {
+	if remaining == 0 {
+		cancel()
+		return
+	}
+	s.pending.add()
+	go func() {
+		defer s.pending.done()
+		defer cancel()
+		for i := 0; i < remaining; i++ {
+			if result := <-results; result.err != nil {
+				slog.ErrorContext(ctx, "background product write failed", "operation", operation, "store", result.store.name, "product_id", productID, "error", result.err)
+			}
+		}
+	}()
+}
+
+// Menjalankan penulisan pada satu store dengan batas waktu kebijakan penulisan
+func (s *ProductService) applyWithTimeout(ctx context.Context, store productStore, apply storeAction) error {
+	writeCtx, cancel := s.writeContext(ctx)
+	defer cancel()
+	return apply(writeCtx, store)
+}
+
+// Context penulisan yang membawa nilai request (tenant, actor, trace) tanpa ikut dibatalkan
+func (s *ProductService) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if timeout := s.currentWritePolicy().Timeout; timeout > 0 {
+		return context.WithTimeout(detached, timeout)
+	}
+	return context.WithCancel(detached)
+}
+
+// Memulai penulisan produk. Setelah Drain dipanggil penulisan baru ditolak dengan
+// domain.ErrStoreUnavailable. release dipanggil setelah penulisan selesai.
+func (s *ProductService) beginWrite() (release func(), err error) {
+	if !s.pending.begin() {
+		return nil, fmt.Errorf("%w: product service is shutting down", domain.ErrStoreUnavailable)
+	}
+	return s.pending.done, nil
+}
+
+// Menunggu penulisan yang sedang berjalan dan yang berlanjut di background selesai,
+// dipanggil saat shutdown setelah server berhenti menerima request. Penulisan baru
+// ditolak sejak Drain dipanggil, termasuk jika Drain berhenti karena ctx.
+func (s *ProductService) Drain(ctx context.Context) error {
+	s.pending.close()
+	done := make(chan struct{})
+	go func() {
+		s.pending.wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Penulisan produk yang sedang berjalan. Setiap penulisan dimulai dengan begin, dan
+// goroutine background-nya ditambahkan dengan add selama penulisan itu belum selesai,
+// sehingga counter tidak pernah naik dari nol setelah close dan tidak bersamaan dengan wait.
+type pendingWrites struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Memulai penulisan baru, false jika sudah ditutup
+func (p *pendingWrites) begin() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	p.wg.Add(1)
+	return true
+}
+
+// Menambahkan goroutine background dari penulisan yang sedang berjalan
+func (p *pendingWrites) add() {
+	p.wg.Add(1)
+}
+
+func (p *pendingWrites) done() {
+	p.wg.Done()
+}
+
+// Menolak penulisan baru
+func (p *pendingWrites) close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+}
+
+// Menunggu semua penulisan selesai, dipanggil setelah close
+func (p *pendingWrites) wait() {
+	p.wg.Wait()
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"time"
+)
+
+type RevisionService struct {
+	productService ports.ProductService
+	revisionRepo   ports.RevisionRepository
+	authorizer     ports.Authorizer
+}
+
+// Membuat instance baru dari RevisionService.
+// Restore ditulis melalui productService agar tetap tercatat di audit log,
+// membuat revisi baru dan menghapus cache. Karena itu restore membutuhkan
+// products:restore sekaligus permission update dari productService, ditambah
+// permission create jika produk sudah dihapus.
+func NewRevisionService(productService ports.ProductService, revisionRepo ports.RevisionRepository, authorizer ports.Authorizer) *RevisionService {
+	return &RevisionService{
+		productService: productService,
+		revisionRepo:   revisionRepo,
+		authorizer:     authorizer,
+	}
+}
+
+func (s *RevisionService) ListRevisions(ctx context.Context, productID string) ([]*domain.ProductRevision, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.ListRevisions(ctx, productID)
+}
+
+func (s *RevisionService) GetProductAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionRepo.GetRevisionAsOf(ctx, productID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	// Produk sudah dihapus pada waktu tersebut
+	if revision.Deleted {
+		return nil, domain.ErrRevisionNotFound
+	}
+	product := revision.Product
+	return &product, nil
+}
+
+func (s *RevisionService) RestoreRevision(ctx context.Context, productID string, revision int) (*domain.Product, error) {
+	if err := authorize(ctx, s.authorizer, domain.PermissionProductsRestore); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.revisionRepo.GetRevision(ctx, productID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Deleted {
+		return nil, domain.ErrRevisionNotFound
+	}
+
+	// Tulis ulang snapshot ke semua store, termasuk identifier yang kosong
+	product := snapshot.Product
+	product.ID = productID
+	if err := s.productService.RestoreProduct(ctx, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
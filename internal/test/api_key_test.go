@@ -0,0 +1,328 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repository API key in-memory untuk pengujian alur issue/rotate/revoke
+type memoryAPIKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*domain.APIKey
+}
+
+func newMemoryAPIKeyRepository() *memoryAPIKeyRepository {
+	return &memoryAPIKeyRepository{keys: make(map[string]*domain.APIKey)}
+}
+
+func (r *memoryAPIKeyRepository) CreateAPIKey(_ context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key.ID = strconv.Itoa(len(r.keys) + 1)
+	stored := *key
+	r.keys[key.ID] = &stored
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKey(_ context.Context, id string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByPrefix(_ context.Context, prefix string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepository) ListAPIKeys(context.Context) ([]*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]*domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) RotateAPIKey(_ context.Context, id, prefix, hash string, rotatedAt time.Time) error {
+	return r.update(id, func(key *domain.APIKey) {
+		key.Prefix, key.Hash, key.RotatedAt = prefix, hash, &rotatedAt
+	})
+}
+
+func (r *memoryAPIKeyRepository) RevokeAPIKey(_ context.Context, id string, revokedAt time.Time) error {
+	return r.update(id, func(key *domain.APIKey) { key.RevokedAt = &revokedAt })
+}
+
+func (r *memoryAPIKeyRepository) TouchAPIKey(_ context.Context, id string, usedAt time.Time) error {
+	return r.update(id, func(key *domain.APIKey) { key.LastUsedAt = &usedAt })
+}
+
+func (r *memoryAPIKeyRepository) update(id string, fn func(*domain.APIKey)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return domain.ErrAPIKeyNotFound
+	}
+	fn(key)
+	return nil
+}
+
+// TestAPIKeyService adalah fungsi untuk menguji siklus hidup API key
+func TestAPIKeyService(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	service := services.NewAPIKeyService(repo)
+	ctx := domain.ContextWithActor(context.Background(), "admin")
+
+	issued, err := service.IssueAPIKey(ctx, domain.APIKeyRequest{Name: "batch-import", Scopes: []string{"products:write"}})
+	require.NoError(t, err)
+
+	// Key asli tidak disimpan, hanya hash-nya
+	stored, err := repo.GetAPIKey(ctx, issued.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Hash, issued.Key)
+	assert.NotEqual(t, issued.Key, stored.Hash)
+	assert.Equal(t, "admin", stored.CreatedBy)
+
+	// Test Authenticate
+	t.Run("Authenticate", func(t *testing.T) {
+		principal, err := service.AuthenticateAPIKey(context.Background(), issued.Key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "apikey:"+issued.ID, principal.Subject)
+		assert.Equal(t, []string{"products:write"}, principal.Scopes)
+		assert.Equal(t, domain.AuthMethodAPIKey, principal.Method)
+
+		// Waktu terakhir dipakai tercatat
+		stored, _ := repo.GetAPIKey(ctx, issued.ID)
+		assert.NotNil(t, stored.LastUsedAt)
+	})
+
+	// Test key dengan secret yang salah
+	t.Run("Wrong Secret", func(t *testing.T) {
+		_, err := service.AuthenticateAPIKey(context.Background(), issued.Key+"x")
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+		_, err = service.AuthenticateAPIKey(context.Background(), "not-a-key")
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	// Test Rotate membuat key lama tidak berlaku
+	t.Run("Rotate", func(t *testing.T) {
+		rotated, err := service.RotateAPIKey(ctx, issued.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, issued.Key, rotated.Key)
+
+		_, err = service.AuthenticateAPIKey(context.Background(), issued.Key)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+		_, err = service.AuthenticateAPIKey(context.Background(), rotated.Key)
+		assert.NoError(t, err)
+
+		// Test Revoke
+		assert.NoError(t, service.RevokeAPIKey(ctx, issued.ID))
+		_, err = service.AuthenticateAPIKey(context.Background(), rotated.Key)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	// Test key kedaluwarsa
+	t.Run("Expired", func(t *testing.T) {
+		expiresAt := time.Now().Add(50 * time.Millisecond)
+		expiring, err := service.IssueAPIKey(ctx, domain.APIKeyRequest{Name: "short-lived", ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+
+		time.Sleep(60 * time.Millisecond)
+		_, err = service.AuthenticateAPIKey(context.Background(), expiring.Key)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+}
+
+// TestAPIKeyEndpoints adalah fungsi untuk menguji middleware X-API-Key dan endpoint admin
+func TestAPIKeyEndpoints(t *testing.T) {
+	service := services.NewAPIKeyService(newMemoryAPIKeyRepository())
+	adminKey, err := service.IssueAPIKey(context.Background(), domain.APIKeyRequest{Name: "root", Scopes: []string{handlers.ScopeAdmin}})
+	require.NoError(t, err)
+	readerKey, err := service.IssueAPIKey(context.Background(), domain.APIKeyRequest{Name: "reader", Scopes: []string{"products:read"}})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	admin := app.Group("/admin", handlers.AuthMiddleware(nil, service), handlers.RequireScope(handlers.ScopeAdmin))
+	apiKeyHandler := handlers.NewAPIKeyHandler(service)
+	admin.Get("/api-keys", apiKeyHandler.ListAPIKeys)
+	admin.Post("/api-keys", apiKeyHandler.IssueAPIKey)
+	admin.Post("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+	admin.Delete("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+
+	// Test Issue dengan key admin
+	t.Run("Issue", func(t *testing.T) {
+		body, _ := json.Marshal(domain.APIKeyRequest{Name: "batch-export", Scopes: []string{"products:read"}})
+		req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(handlers.HeaderAPIKey, adminKey.Key)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+		var issued map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&issued))
+		assert.NotEmpty(t, issued["key"])
+		assert.NotContains(t, issued, "hash")
+	})
+
+	// Test List tidak menampilkan key asli
+	t.Run("List", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+		req.Header.Set(handlers.HeaderAPIKey, adminKey.Key)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var keys []map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&keys))
+		assert.Len(t, keys, 3)
+		for _, key := range keys {
+			assert.NotContains(t, key, "key")
+			assert.NotContains(t, key, "hash")
+		}
+	})
+
+	// Test key tanpa scope admin
+	t.Run("Forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+		req.Header.Set(handlers.HeaderAPIKey, readerKey.Key)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	// Test key tidak valid
+	t.Run("Unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+		req.Header.Set(handlers.HeaderAPIKey, "gfp_000000000000_invalid")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "APIKey")
+	})
+
+	// Test Revoke lalu key tidak bisa dipakai lagi
+	t.Run("Revoke", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/api-keys/"+readerKey.ID, nil)
+		req.Header.Set(handlers.HeaderAPIKey, adminKey.Key)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		req = httptest.NewRequest(http.MethodPost, "/admin/api-keys/"+readerKey.ID+"/rotate", nil)
+		req.Header.Set(handlers.HeaderAPIKey, adminKey.Key)
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+}
+
+// TestAPIKeyTenantScope adalah fungsi untuk menguji batas tenant dan scope saat mengelola API key
+func TestAPIKeyTenantScope(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	service := services.NewAPIKeyService(repo)
+
+	// Key tenant lain dibuat dari CLI (tanpa principal)
+	globexKey, err := service.IssueAPIKey(context.Background(), domain.APIKeyRequest{Name: "globex", Scopes: []string{"products:read"}, TenantID: "globex"})
+	require.NoError(t, err)
+
+	acmeAdmin := domain.ContextWithPrincipal(context.Background(), &domain.Principal{
+		Subject: "apikey:acme-admin", Scopes: []string{domain.ScopeAdmin}, TenantID: "acme",
+	})
+
+	// Test key baru mengikuti tenant pembuatnya
+	t.Run("Issue Inherits Tenant", func(t *testing.T) {
+		issued, err := service.IssueAPIKey(acmeAdmin, domain.APIKeyRequest{Name: "acme-import", Scopes: []string{"products:read"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, "acme", issued.TenantID)
+	})
+
+	// Test key untuk tenant lain atau lintas tenant ditolak
+	t.Run("Issue Other Tenant", func(t *testing.T) {
+		var permErr *domain.PermissionError
+
+		_, err := service.IssueAPIKey(acmeAdmin, domain.APIKeyRequest{Name: "globex-import", TenantID: "globex"})
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.ScopeCrossTenant, permErr.Permission)
+
+		_, err = service.IssueAPIKey(acmeAdmin, domain.APIKeyRequest{Name: "all-tenants", Scopes: []string{domain.ScopeCrossTenant}})
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.ScopeCrossTenant, permErr.Permission)
+	})
+
+	// Test scope yang tidak dimiliki pembuat ditolak
+	t.Run("Issue Unheld Scope", func(t *testing.T) {
+		reader := domain.ContextWithPrincipal(context.Background(), &domain.Principal{
+			Subject: "user-1", Scopes: []string{"products:read"}, TenantID: "acme",
+		})
+
+		_, err := service.IssueAPIKey(reader, domain.APIKeyRequest{Name: "escalate", Scopes: []string{"products:read", domain.ScopeAdmin}})
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.ScopeAdmin, permErr.Permission)
+	})
+
+	// Test daftar key hanya berisi key tenant pemanggil
+	t.Run("List", func(t *testing.T) {
+		keys, err := service.ListAPIKeys(acmeAdmin)
+
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "acme", keys[0].TenantID)
+
+		all, err := service.ListAPIKeys(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	// Test key tenant lain tidak bisa dirotasi atau dicabut
+	t.Run("Rotate And Revoke Other Tenant", func(t *testing.T) {
+		_, err := service.RotateAPIKey(acmeAdmin, globexKey.ID)
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+
+		err = service.RevokeAPIKey(acmeAdmin, globexKey.ID)
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+
+		stored, _ := repo.GetAPIKey(context.Background(), globexKey.ID)
+		assert.Nil(t, stored.RevokedAt)
+	})
+}
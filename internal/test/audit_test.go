@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffProducts adalah fungsi untuk menguji diff per field
+func TestDiffProducts(t *testing.T) {
+	before := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10}
+	after := &domain.Product{ID: "123", Name: "Test Product", Price: 1500, Stock: 10, UpdatedBy: "bob"}
+
+	// Test Update hanya mencatat field yang berubah
+	assert.Equal(t, []domain.FieldChange{{Field: "price", Before: 1000, After: 1500}}, domain.DiffProducts(before, after))
+
+	// Test Create mencatat semua field dengan nilai sebelum nil
+	changes := domain.DiffProducts(nil, before)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, domain.FieldChange{Field: "name", Before: nil, After: "Test Product"}, changes[0])
+
+	// Test Delete mencatat semua field dengan nilai sesudah nil
+	changes = domain.DiffProducts(before, nil)
+	assert.Len(t, changes, 3)
+	assert.Nil(t, changes[2].After)
+}
+
+// TestProductServiceAudit adalah fungsi untuk menguji pencatatan audit oleh ProductService
+func TestProductServiceAudit(t *testing.T) {
+	mockMongoRepo := new(mocks.MockMongoProductRepository)
+	mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuditLog(mockAuditRepo))
+
+	existing := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10}
+	mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(existing, nil).Once()
+	mockMongoRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+	mockMySQLRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+
+	var recorded *domain.AuditEntry
+	mockAuditRepo.On("AppendAudit", mock.Anything, mock.AnythingOfType("*domain.AuditEntry")).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*domain.AuditEntry)
+	}).Return(nil).Once()
+
+	// Context membawa actor, ID request dan IP asal
+	ctx := domain.ContextWithActor(context.Background(), "alice")
+	ctx = domain.ContextWithRequestInfo(ctx, domain.RequestInfo{RequestID: "req-1", SourceIP: "10.0.0.1"})
+
+	err := service.UpdateProduct(ctx, &domain.Product{ID: "123", Name: "Test Product", Price: 1200, Stock: 10})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, recorded) {
+		assert.Equal(t, "123", recorded.ProductID)
+		assert.Equal(t, domain.AuditActionUpdate, recorded.Action)
+		assert.Equal(t, "alice", recorded.Actor)
+		assert.Equal(t, "req-1", recorded.RequestID)
+		assert.Equal(t, "10.0.0.1", recorded.SourceIP)
+		assert.False(t, recorded.Timestamp.IsZero())
+		assert.Equal(t, []domain.FieldChange{{Field: "price", Before: 1000, After: 1200}}, recorded.Changes)
+	}
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// TestQueryAudit adalah fungsi untuk menguji endpoint pencarian audit log
+func TestQueryAudit(t *testing.T) {
+	mockAuditRepo := new(mocks.MockAuditRepository)
+
+	app := fiber.New()
+	auditHandler := handlers.NewAuditHandler(services.NewAuditService(mockAuditRepo, nil))
+	app.Get("/audit", auditHandler.QueryAudit)
+	app.Get("/products/:id/audit", auditHandler.ListProductAudit)
+
+	entries := []*domain.AuditEntry{{ID: "a1", ProductID: "123", Action: domain.AuditActionUpdate, Actor: "alice"}}
+
+	// Test Success
+	t.Run("Success", func(t *testing.T) {
+		mockAuditRepo.On("ListAudit", mock.Anything, domain.AuditQuery{
+			Actor: "alice",
+			From:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			To:    time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		}).Return(entries, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?actor=alice&from=2024-05-01T00:00:00Z&to=2024-05-02T00:00:00Z", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result []*domain.AuditEntry
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, entries, result)
+	})
+
+	// Test Product Audit
+	t.Run("Product Audit", func(t *testing.T) {
+		mockAuditRepo.On("ListAudit", mock.Anything, domain.AuditQuery{ProductID: "123"}).Return(entries, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/products/123/audit", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	// Test Bad Request
+	t.Run("Bad Request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/audit?from=yesterday", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// TestActorMiddleware adalah fungsi untuk menguji header X-Actor-ID hanya diterima dari gateway yang dipercaya
+func TestActorMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		trusted  []netip.Prefix
+		expected string
+	}{
+		// Request dari app.Test berasal dari 0.0.0.0
+		{name: "Trusted Proxy", trusted: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/32")}, expected: "alice"},
+		{name: "Untrusted Remote", trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, expected: domain.AnonymousActor},
+		{name: "No Trusted Proxies", expected: domain.AnonymousActor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(handlers.ActorMiddleware(tt.trusted))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString(domain.ActorFromContext(c.UserContext()))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(handlers.HeaderActorID, "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+
+	// Test konfigurasi gateway menerima IP dan CIDR
+	t.Run("Config", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.ActorTrustedProxies = "10.0.0.0/8, 192.168.1.10"
+		require.NoError(t, cfg.Validate())
+		assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")}, cfg.ActorTrustedProxyPrefixes())
+
+		cfg.ActorTrustedProxies = "10.0.0.0/8,gateway"
+		assert.ErrorContains(t, cfg.Validate(), "actor_trusted_proxies")
+	})
+}
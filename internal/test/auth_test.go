@@ -0,0 +1,274 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/auth"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Membuat dokumen JWKS dari public key RSA
+func jwksDocument(t *testing.T, kid string, key *rsa.PublicKey) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// Membuat token dengan claim standar untuk pengujian
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// TestJWTVerifier adalah fungsi untuk menguji verifikasi token HS256 dan RS256
+func TestJWTVerifier(t *testing.T) {
+	secret := []byte("test-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Simpan JWKS ke file sementara
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, jwksDocument(t, "key-1", &rsaKey.PublicKey), 0o600))
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		HS256Secret: secret,
+		KeySet:      auth.NewJWKSFileKeySet(jwksFile),
+		Issuer:      "https://issuer.test",
+		Audience:    "products-api",
+	})
+	require.NoError(t, err)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "alice",
+			"iss":   "https://issuer.test",
+			"aud":   "products-api",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "products:read products:write",
+			"roles": []string{"editor"},
+		}
+	}
+
+	// Test HS256
+	t.Run("HS256", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, secret, "", validClaims())
+
+		principal, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject)
+		assert.Equal(t, []string{"products:read", "products:write"}, principal.Scopes)
+		assert.Equal(t, []string{"editor"}, principal.Roles)
+		assert.Equal(t, domain.AuthMethodJWT, principal.Method)
+	})
+
+	// Test RS256 dengan JWKS file
+	t.Run("RS256", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodRS256, rsaKey, "key-1", validClaims())
+
+		principal, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject)
+	})
+
+	// Test secret salah
+	t.Run("Invalid Signature", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, []byte("other-secret"), "", validClaims())
+
+		_, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.Error(t, err)
+	})
+
+	// Test token kedaluwarsa
+	t.Run("Expired", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		token := signToken(t, jwt.SigningMethodHS256, secret, "", claims)
+
+		_, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	// Test audience salah
+	t.Run("Wrong Audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "other-api"
+		token := signToken(t, jwt.SigningMethodHS256, secret, "", claims)
+
+		_, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.Error(t, err)
+	})
+
+	// Test kid tidak dikenal
+	t.Run("Unknown Key", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodRS256, rsaKey, "key-2", validClaims())
+
+		_, err := verifier.VerifyToken(context.Background(), token)
+
+		assert.Error(t, err)
+	})
+}
+
+// TestJWKSURLKeySet adalah fungsi untuk menguji pengambilan JWKS dari endpoint lokal
+func TestJWKSURLKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jwksDocument(t, "key-1", &rsaKey.PublicKey))
+	}))
+	defer server.Close()
+
+	keySet := auth.NewJWKSURLKeySet(server.URL, server.Client())
+	require.NoError(t, keySet.Load(context.Background()))
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{KeySet: keySet})
+	require.NoError(t, err)
+
+	token := signToken(t, jwt.SigningMethodRS256, rsaKey, "key-1", jwt.MapClaims{
+		"sub": "batch-job",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"scp": []string{"products:read"},
+	})
+	principal, err := verifier.VerifyToken(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"products:read"}, principal.Scopes)
+
+	// Token HS256 ditolak karena secret tidak dikonfigurasi
+	hsToken := signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "x", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = verifier.VerifyToken(context.Background(), hsToken)
+	assert.Error(t, err)
+}
+
+// TestJWKSRefreshWithoutLock adalah fungsi untuk menguji bahwa refresh JWKS yang lambat tidak menahan pembacaan key
+func TestJWKSRefreshWithoutLock(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			close(fetching)
+			<-release
+		}
+		_, _ = w.Write(jwksDocument(t, "key-1", &rsaKey.PublicKey))
+	}))
+	defer server.Close()
+
+	keySet := auth.NewJWKSURLKeySet(server.URL, server.Client())
+	require.NoError(t, keySet.Load(context.Background()))
+
+	// Refresh kedua tertahan di endpoint JWKS
+	loaded := make(chan error, 1)
+	go func() { loaded <- keySet.Load(context.Background()) }()
+	<-fetching
+
+	// Key yang sudah dikenal tetap bisa dibaca selama refresh berjalan
+	found := make(chan error, 1)
+	go func() {
+		_, err := keySet.Key(context.Background(), "key-1")
+		found <- err
+	}()
+	select {
+	case err := <-found:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Key blocked while JWKS refresh was in progress")
+	}
+
+	close(release)
+	require.NoError(t, <-loaded)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+// TestAuthMiddleware adalah fungsi untuk menguji middleware autentikasi
+func TestAuthMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{HS256Secret: secret})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(handlers.AuthMiddleware(verifier, nil))
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		principal := domain.PrincipalFromContext(c.UserContext())
+		return c.JSON(fiber.Map{
+			"subject": principal.Subject,
+			"actor":   domain.ActorFromContext(c.UserContext()),
+		})
+	})
+
+	// Test Success
+	t.Run("Success", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result map[string]string
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "alice", result["subject"])
+		assert.Equal(t, "alice", result["actor"])
+	})
+
+	// Test tanpa token
+	t.Run("Missing Token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, `Bearer realm="api"`, resp.Header.Get("WWW-Authenticate"))
+	})
+
+	// Test token tidak valid
+	t.Run("Invalid Token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer not-a-jwt")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("WWW-Authenticate"), `error="invalid_token"`)
+	})
+}
@@ -0,0 +1,218 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/cache"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestMemoryCache adalah fungsi untuk menguji cache LRU+TTL in-process
+func TestMemoryCache(t *testing.T) {
+	// Test LRU eviction
+	t.Run("Evicts Least Recently Used", func(t *testing.T) {
+		c := cache.NewMemoryCache(2)
+		assert.NoError(t, c.Set("a", []byte("1"), 0))
+		assert.NoError(t, c.Set("b", []byte("2"), 0))
+
+		// Akses "a" agar "b" menjadi yang paling lama tidak dipakai
+		_, found, _ := c.Get("a")
+		assert.True(t, found)
+		assert.NoError(t, c.Set("c", []byte("3"), 0))
+
+		_, found, _ = c.Get("b")
+		assert.False(t, found)
+		value, found, _ := c.Get("a")
+		assert.True(t, found)
+		assert.Equal(t, []byte("1"), value)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	// Test TTL
+	t.Run("Expires Entries", func(t *testing.T) {
+		c := cache.NewMemoryCache(10)
+		assert.NoError(t, c.Set("a", []byte("1"), 20*time.Millisecond))
+		time.Sleep(30 * time.Millisecond)
+
+		_, found, _ := c.Get("a")
+		assert.False(t, found)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	// Test Delete
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewMemoryCache(10)
+		assert.NoError(t, c.Set("a", []byte("1"), 0))
+		assert.NoError(t, c.Delete("a", "missing"))
+
+		_, found, _ := c.Get("a")
+		assert.False(t, found)
+	})
+}
+
+// TestRedisCache adalah fungsi untuk menguji adapter cache Redis dengan miniredis
+func TestRedisCache(t *testing.T) {
+	// Jalankan server Redis lokal
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	c := cache.NewRedisCache(client, "test:")
+
+	// Test Set dan Get
+	assert.NoError(t, c.Set("a", []byte("1"), time.Minute))
+	value, found, err := c.Get("a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), value)
+	assert.True(t, server.Exists("test:a"))
+
+	// Test TTL
+	server.FastForward(2 * time.Minute)
+	_, found, err = c.Get("a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// Test Delete
+	assert.NoError(t, c.Set("b", []byte("2"), 0))
+	assert.NoError(t, c.Delete("b"))
+	_, found, err = c.Get("b")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+// TestCachedProductService adalah fungsi untuk menguji decorator cache ProductService
+func TestCachedProductService(t *testing.T) {
+	mockProduct := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10}
+
+	// Test Hit dan Miss
+	t.Run("Hit And Miss", func(t *testing.T) {
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+		// Hanya pembacaan pertama yang diteruskan ke service
+		mockProductService.On("GetProduct", mock.Anything, "123").Return(mockProduct, nil).Once()
+
+		for i := 0; i < 3; i++ {
+			product, err := service.GetProduct(context.Background(), "123")
+			assert.NoError(t, err)
+			assert.Equal(t, mockProduct, product)
+		}
+
+		assert.Equal(t, domain.CacheStats{Hits: 2, Misses: 1}, service.Stats())
+		mockProductService.AssertExpectations(t)
+	})
+
+	// Test invalidasi setelah update
+	t.Run("Invalidate On Write", func(t *testing.T) {
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+		updated := &domain.Product{ID: "123", Name: "Updated Product", Price: 1500, Stock: 15}
+		mockProductService.On("GetProduct", mock.Anything, "123").Return(mockProduct, nil).Once()
+		mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{}).Return([]*domain.Product{mockProduct}, nil).Once()
+		mockProductService.On("UpdateProduct", mock.Anything, updated).Return(nil).Once()
+		mockProductService.On("GetProduct", mock.Anything, "123").Return(updated, nil).Once()
+		mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{}).Return([]*domain.Product{updated}, nil).Once()
+
+		_, _ = service.GetProduct(context.Background(), "123")
+		_, _ = service.ListProducts(context.Background(), domain.ProductListOptions{})
+		assert.NoError(t, service.UpdateProduct(context.Background(), updated))
+
+		product, err := service.GetProduct(context.Background(), "123")
+		assert.NoError(t, err)
+		assert.Equal(t, updated, product)
+
+		products, err := service.ListProducts(context.Background(), domain.ProductListOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.Product{updated}, products)
+
+		mockProductService.AssertExpectations(t)
+	})
+
+	// Test error tidak disimpan ke cache
+	t.Run("Errors Are Not Cached", func(t *testing.T) {
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+		mockProductService.On("GetProduct", mock.Anything, "456").Return(nil, errors.New("product not found")).Twice()
+
+		_, err := service.GetProduct(context.Background(), "456")
+		assert.Error(t, err)
+		_, err = service.GetProduct(context.Background(), "456")
+		assert.Error(t, err)
+
+		mockProductService.AssertExpectations(t)
+	})
+
+	// Test singleflight menggabungkan miss yang bersamaan
+	t.Run("Singleflight", func(t *testing.T) {
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+		// Service lambat sehingga semua goroutine mengalami miss bersamaan
+		mockProductService.On("GetProduct", mock.Anything, mock.Anything).Return(mockProduct, nil).After(50 * time.Millisecond).Once()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				product, err := service.GetProduct(context.Background(), "123")
+				assert.NoError(t, err)
+				assert.Equal(t, mockProduct.ID, product.ID)
+			}()
+		}
+		wg.Wait()
+
+		mockProductService.AssertExpectations(t)
+	})
+
+	// Test pemanggil pertama yang dibatalkan tidak membatalkan pembacaan untuk pemanggil lain
+	t.Run("Singleflight Caller Canceled", func(t *testing.T) {
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil,
+			services.WithCacheFetchTimeout(time.Second))
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		mockProductService.On("GetProduct", mock.Anything, "123").Return(mockProduct, nil).Run(func(args mock.Arguments) {
+			close(started)
+			<-release
+			assert.NoError(t, args.Get(0).(context.Context).Err())
+		}).Once()
+
+		firstCtx, cancel := context.WithCancel(context.Background())
+		firstErr := make(chan error, 1)
+		go func() {
+			_, err := service.GetProduct(firstCtx, "123")
+			firstErr <- err
+		}()
+		<-started
+
+		secondResult := make(chan *domain.Product, 1)
+		go func() {
+			product, err := service.GetProduct(context.Background(), "123")
+			assert.NoError(t, err)
+			secondResult <- product
+		}()
+
+		// Pemanggil pertama berhenti menunggu, pembacaan bersama tetap berjalan
+		cancel()
+		assert.ErrorIs(t, <-firstErr, context.Canceled)
+		close(release)
+		assert.Equal(t, mockProduct.ID, (<-secondResult).ID)
+
+		mockProductService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,252 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/ratelimit"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/pkg/config"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Membuat Manager dari file konfigurasi sementara
+func newConfigManager(t *testing.T, content string) (*config.Manager, string) {
+	file := writeTempFile(t, "config.yaml", content)
+	loader, err := config.NewLoader("test", []string{"--config", file})
+	require.NoError(t, err)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+	return config.NewManager(loader, cfg), file
+}
+
+// Menimpa isi file konfigurasi
+func rewriteFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// Menjalankan Watch di background sampai test selesai
+func startWatch(t *testing.T, manager *config.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = manager.Watch(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// TestConfigReload adalah fungsi untuk menguji hot reload konfigurasi
+func TestConfigReload(t *testing.T) {
+	// Test perubahan key yang bisa di-reload diterapkan dan versi naik
+	t.Run("Apply Reloadable Changes", func(t *testing.T) {
+		manager, file := newConfigManager(t, "log_level: info\nrate_limit_read: 100\n")
+		before := manager.Current()
+
+		var reloaded *config.Config
+		manager.OnReload(func(_, current *config.Config) { reloaded = current })
+
+		rewriteFile(t, file, "log_level: debug\nrate_limit_read: 5\n")
+		event := manager.Reload(config.TriggerManual)
+
+		assert.Equal(t, config.ReloadApplied, event.Status)
+		assert.Equal(t, 2, event.Version)
+		assert.ElementsMatch(t, []string{"log_level", "rate_limit_read"}, event.Changed)
+		assert.Empty(t, event.RestartRequired)
+
+		current := manager.Current()
+		assert.Equal(t, "debug", current.LogLevel)
+		assert.Equal(t, 5, current.RateLimitRead)
+		assert.Same(t, current, reloaded)
+
+		// Snapshot lama tidak ikut berubah
+		assert.Equal(t, "info", before.LogLevel)
+		assert.Equal(t, 100, before.RateLimitRead)
+	})
+
+	// Test kebijakan penulisan dan read fallback bisa di-reload
+	t.Run("Apply Feature Toggles", func(t *testing.T) {
+		manager, file := newConfigManager(t, "write_consistency: all\nresilience_enabled: true\n")
+
+		rewriteFile(t, file, "write_consistency: primary\nwrite_timeout: 5s\nread_fallback:\n  products.get: 0s\nresilience_enabled: false\n")
+		event := manager.Reload(config.TriggerManual)
+
+		assert.Equal(t, config.ReloadApplied, event.Status)
+		assert.ElementsMatch(t, []string{"write_consistency", "write_timeout", "read_fallback"}, event.Changed)
+		assert.Equal(t, []string{"resilience_enabled"}, event.RestartRequired)
+		assert.Equal(t, "primary", manager.Current().WriteConsistency)
+		assert.Equal(t, 5*time.Second, manager.Current().WriteTimeout)
+		assert.True(t, manager.Current().ResilienceEnabled)
+	})
+
+	// Test konfigurasi tidak valid ditolak dan konfigurasi lama tetap dipakai
+	t.Run("Reject Invalid Config", func(t *testing.T) {
+		manager, file := newConfigManager(t, "rate_limit_read: 100\n")
+		before := manager.Current()
+
+		called := false
+		manager.OnReload(func(_, _ *config.Config) { called = true })
+
+		rewriteFile(t, file, "rate_limit_read: -1\n")
+		event := manager.Reload(config.TriggerManual)
+		assert.Equal(t, config.ReloadRejected, event.Status)
+		assert.Equal(t, 1, event.Version)
+		assert.Contains(t, event.Error, "rate_limit_read")
+
+		rewriteFile(t, file, "rate_limit_read: [broken\n")
+		event = manager.Reload(config.TriggerManual)
+		assert.Equal(t, config.ReloadRejected, event.Status)
+		assert.NotEmpty(t, event.Error)
+
+		assert.Same(t, before, manager.Current())
+		assert.False(t, called)
+	})
+
+	// Test perubahan key yang tidak bisa di-reload hanya dilaporkan
+	t.Run("Restart Required", func(t *testing.T) {
+		manager, file := newConfigManager(t, "server_address: \":8080\"\nrate_limit_write: 10\n")
+
+		rewriteFile(t, file, "server_address: \":9090\"\nrate_limit_write: 10\n")
+		event := manager.Reload(config.TriggerManual)
+		assert.Equal(t, config.ReloadUnchanged, event.Status)
+		assert.Equal(t, 1, event.Version)
+		assert.Equal(t, []string{"server_address"}, event.RestartRequired)
+		assert.Equal(t, ":8080", manager.Current().ServerAddress)
+
+		rewriteFile(t, file, "server_address: \":9090\"\nrate_limit_write: 20\n")
+		event = manager.Reload(config.TriggerManual)
+		assert.Equal(t, config.ReloadApplied, event.Status)
+		assert.Equal(t, []string{"rate_limit_write"}, event.Changed)
+		assert.Equal(t, []string{"server_address"}, event.RestartRequired)
+		assert.Equal(t, ":8080", manager.Current().ServerAddress)
+		assert.Equal(t, 20, manager.Current().RateLimitWrite)
+	})
+
+	// Test perubahan file terdeteksi oleh watcher
+	t.Run("Watch File", func(t *testing.T) {
+		manager, file := newConfigManager(t, "rate_limit_read: 100\n")
+		startWatch(t, manager)
+
+		// File ditulis ulang sampai watcher siap dan reload terjadi,
+		// jeda antar penulisan lebih lama dari debounce watcher
+		assert.Eventually(t, func() bool {
+			rewriteFile(t, file, "rate_limit_read: 7\n")
+			return manager.Current().RateLimitRead == 7
+		}, 5*time.Second, 500*time.Millisecond)
+
+		status, err := manager.Status()
+		require.NoError(t, err)
+		assert.Equal(t, config.TriggerFile, status.History[0].Trigger)
+	})
+
+	// Test SIGHUP memicu reload
+	t.Run("SIGHUP", func(t *testing.T) {
+		// Menangkap SIGHUP agar proses test tidak berhenti sebelum Watch siap
+		guard := make(chan os.Signal, 1)
+		signal.Notify(guard, syscall.SIGHUP)
+		defer signal.Stop(guard)
+
+		manager, file := newConfigManager(t, "log_level: info\n")
+		startWatch(t, manager)
+		rewriteFile(t, file, "log_level: warn\n")
+
+		assert.Eventually(t, func() bool {
+			require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+			return manager.Current().LogLevel == "warn"
+		}, 5*time.Second, 100*time.Millisecond)
+
+		status, err := manager.Status()
+		require.NoError(t, err)
+		triggers := make([]string, 0, len(status.History))
+		for _, event := range status.History {
+			triggers = append(triggers, event.Trigger)
+		}
+		assert.Contains(t, triggers, config.TriggerSignal)
+	})
+
+	// Test rate limit dinamis memakai batas terbaru setelah reload
+	t.Run("Dynamic Rate Limit", func(t *testing.T) {
+		manager, file := newConfigManager(t, "rate_limit_backend: memory\nrate_limit_read: 1\nrate_limit_period: 1m\n")
+
+		app := fiber.New()
+		app.Use(handlers.DynamicRateLimitMiddleware(ratelimit.NewMemoryRateLimiter(), func() handlers.RateLimitConfig {
+			cfg := manager.Current()
+			return handlers.RateLimitConfig{Read: domain.RateLimit{Limit: cfg.RateLimitRead, Period: cfg.RateLimitPeriod}}
+		}))
+		app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+		get := func() int {
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, fiber.StatusOK, get())
+		assert.Equal(t, fiber.StatusTooManyRequests, get())
+
+		rewriteFile(t, file, "rate_limit_backend: memory\nrate_limit_read: 0\nrate_limit_period: 1m\n")
+		require.Equal(t, config.ReloadApplied, manager.Reload(config.TriggerManual).Status)
+		assert.Equal(t, fiber.StatusOK, get())
+	})
+}
+
+// TestConfigHandler adalah fungsi untuk menguji endpoint admin konfigurasi
+func TestConfigHandler(t *testing.T) {
+	manager, file := newConfigManager(t, "mysql_dsn: \"root:secret@tcp(localhost:3306)/db\"\nrate_limit_read: 100\n")
+	configHandler := handlers.NewConfigHandler(manager)
+
+	app := fiber.New()
+	app.Get("/admin/config", configHandler.GetConfig)
+	app.Post("/admin/config/reload", configHandler.ReloadConfig)
+
+	// Test versi, konfigurasi tersamarkan dan riwayat ditampilkan
+	t.Run("Get Config", func(t *testing.T) {
+		rewriteFile(t, file, "mysql_dsn: \"root:secret@tcp(localhost:3306)/db\"\nrate_limit_read: 50\n")
+		manager.Reload(config.TriggerManual)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/admin/config", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+
+		var status config.Status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		assert.Equal(t, 2, status.Version)
+		assert.Equal(t, file, status.File)
+		assert.EqualValues(t, 50, status.Config["rate_limit_read"])
+		assert.Contains(t, status.Reloadable, "write_consistency")
+		assert.Contains(t, status.Reloadable, "read_fallback")
+		assert.NotContains(t, status.Reloadable, "resilience_enabled")
+		assert.NotContains(t, status.Config["mysql_dsn"], "secret")
+		require.Len(t, status.History, 2)
+		assert.Equal(t, config.ReloadApplied, status.History[0].Status)
+		assert.Equal(t, config.TriggerStartup, status.History[1].Trigger)
+	})
+
+	// Test reload manual yang ditolak mengembalikan 422
+	t.Run("Reload Rejected", func(t *testing.T) {
+		rewriteFile(t, file, "rate_limit_read: -5\n")
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/admin/config/reload", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+		var event config.ReloadEvent
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&event))
+		assert.Equal(t, config.ReloadRejected, event.Status)
+		assert.Equal(t, config.TriggerManual, event.Trigger)
+		assert.Equal(t, 50, manager.Current().RateLimitRead)
+	})
+}
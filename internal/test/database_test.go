@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/pkg/database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnectWithRetry adalah fungsi untuk menguji percobaan ulang koneksi database saat startup
+func TestConnectWithRetry(t *testing.T) {
+	errUnavailable := errors.New("connection refused")
+	opts := database.RetryOptions{Attempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+
+	// Test koneksi berhasil setelah beberapa kali gagal
+	t.Run("Success After Failures", func(t *testing.T) {
+		attempts := 0
+		conn, err := database.ConnectWithRetry(context.Background(), "test", opts, func(context.Context) (string, error) {
+			attempts++
+			if attempts < 3 {
+				return "", errUnavailable
+			}
+			return "connected", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "connected", conn)
+		assert.Equal(t, 3, attempts)
+	})
+
+	// Test error terakhir dikembalikan setelah percobaan habis
+	t.Run("Attempts Exhausted", func(t *testing.T) {
+		attempts := 0
+		_, err := database.ConnectWithRetry(context.Background(), "test", opts, func(context.Context) (string, error) {
+			attempts++
+			return "", errUnavailable
+		})
+		assert.ErrorIs(t, err, errUnavailable)
+		assert.Equal(t, 5, attempts)
+	})
+
+	// Test percobaan berhenti saat ctx dibatalkan
+	t.Run("Context Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		slow := database.RetryOptions{Attempts: 10, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+		_, err := database.ConnectWithRetry(ctx, "test", slow, func(context.Context) (string, error) {
+			attempts++
+			cancel()
+			return "", errUnavailable
+		})
+		assert.ErrorIs(t, err, errUnavailable)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+// TestDatabaseConnectionTimeouts adalah fungsi untuk menguji timeout koneksi ke database yang tidak tersedia
+func TestDatabaseConnectionTimeouts(t *testing.T) {
+	// Test MongoDB gagal setelah server selection timeout
+	t.Run("MongoDB", func(t *testing.T) {
+		start := time.Now()
+		client, err := database.NewMongoDBConnection(context.Background(), "mongodb://127.0.0.1:1/?directConnection=true", database.MongoOptions{
+			MaxPoolSize:            10,
+			ConnectTimeout:         100 * time.Millisecond,
+			ServerSelectionTimeout: 300 * time.Millisecond,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, client)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	// Test MySQL gagal setelah connect timeout dan DSN yang tidak valid ditolak
+	t.Run("MySQL", func(t *testing.T) {
+		opts := database.MySQLOptions{MaxOpenConns: 2, MaxIdleConns: 1, ConnectTimeout: 300 * time.Millisecond}
+
+		start := time.Now()
+		db, err := database.NewMySQLConnection(context.Background(), "root@tcp(127.0.0.1:1)/goproduct_db", opts)
+		assert.Error(t, err)
+		assert.Nil(t, db)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		_, err = database.NewMySQLConnection(context.Background(), "not a dsn", opts)
+		assert.Error(t, err)
+	})
+}
@@ -41,7 +41,7 @@ func TestGetProduct(t *testing.T) {
 	// Test Success
 	t.Run("Success", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan mock product
-		mockProductService.On("GetProduct", "123").Return(mockProduct, nil).Once()
+		mockProductService.On("GetProduct", mock.Anything, "123").Return(mockProduct, nil).Once()
 
 		// Buat request untuk GetProduct
 		req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
@@ -69,7 +69,7 @@ func TestGetProduct(t *testing.T) {
 	// Test Not Found
 	t.Run("Not Found", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan error
-		mockProductService.On("GetProduct", "456").Return(nil, errors.New("product not found")).Once()
+		mockProductService.On("GetProduct", mock.Anything, "456").Return(nil, domain.ErrProductNotFound).Once()
 
 		// Buat request untuk GetProduct
 		req := httptest.NewRequest(http.MethodGet, "/products/456", nil)
@@ -112,7 +112,7 @@ func TestCreateProduct(t *testing.T) {
 	// Test Success
 	t.Run("Success", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan nil
-		mockProductService.On("CreateProduct", mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockProductService.On("CreateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
 
 		// Buat request untuk CreateProduct
 		body, _ := json.Marshal(mockProduct)
@@ -178,7 +178,7 @@ func TestUpdateProduct(t *testing.T) {
 	// Test Success
 	t.Run("Success", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan nil
-		mockProductService.On("UpdateProduct", mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockProductService.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
 
 		// Buat request untuk UpdateProduct
 		body, _ := json.Marshal(mockProduct)
@@ -200,7 +200,7 @@ func TestUpdateProduct(t *testing.T) {
 	// Test Not Found
 	t.Run("Not Found", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan error
-		mockProductService.On("UpdateProduct", mock.AnythingOfType("*domain.Product")).Return(errors.New("product not found")).Once()
+		mockProductService.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(domain.ErrProductNotFound).Once()
 
 		// Buat request untuk UpdateProduct
 		body, _ := json.Marshal(mockProduct)
@@ -240,7 +240,7 @@ func TestDeleteProduct(t *testing.T) {
 	// Test Success
 	t.Run("Success", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan nil
-		mockProductService.On("DeleteProduct", "123").Return(nil).Once()
+		mockProductService.On("DeleteProduct", mock.Anything, "123").Return(nil).Once()
 
 		// Buat request untuk DeleteProduct
 		req := httptest.NewRequest(http.MethodDelete, "/product/123", nil)
@@ -258,7 +258,7 @@ func TestDeleteProduct(t *testing.T) {
 	// Test Not Found
 	t.Run("Not Found", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan error
-		mockProductService.On("DeleteProduct", "456").Return(errors.New("product not found")).Once()
+		mockProductService.On("DeleteProduct", mock.Anything, "456").Return(domain.ErrProductNotFound).Once()
 
 		// Buat request untuk DeleteProduct
 		req := httptest.NewRequest(http.MethodDelete, "/product/456", nil)
@@ -279,7 +279,7 @@ func TestDeleteProduct(t *testing.T) {
 
 // TestListProducts adalah fungsi untuk menguji metode ListProducts
 func TestListProducts(t *testing.T) {
- // Buat mock product service
+	// Buat mock product service
 	mockProductService := new(mocks.MockProductService)
 
 	// Buat fiber app
@@ -310,7 +310,7 @@ func TestListProducts(t *testing.T) {
 	// Test Success
 	t.Run("Success", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan mock products
-		mockProductService.On("ListProducts").Return(mockProducts, nil).Once()
+		mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{}).Return(mockProducts, nil).Once()
 
 		// Buat request untuk ListProducts
 		req := httptest.NewRequest(http.MethodGet, "/product", nil)
@@ -338,7 +338,7 @@ func TestListProducts(t *testing.T) {
 	// Test Not Found
 	t.Run("Not Found", func(t *testing.T) {
 		// Atur mock product service untuk mengembalikan error
-		mockProductService.On("ListProducts").Return(nil, errors.New("products not found")).Once()
+		mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{}).Return(nil, errors.New("products not found")).Once()
 
 		// Buat request untuk ListProducts
 		req := httptest.NewRequest(http.MethodGet, "/product", nil)
@@ -353,6 +353,39 @@ func TestListProducts(t *testing.T) {
 		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 	})
 
+	// Test Sorted
+	t.Run("Sorted", func(t *testing.T) {
+		// Atur mock product service untuk menerima opsi pengurutan
+		mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{SortBy: domain.SortByUpdatedAt, Descending: true}).Return(mockProducts, nil).Once()
+
+		// Buat request untuk ListProducts dengan parameter sort
+		req := httptest.NewRequest(http.MethodGet, "/product?sort=-updated_at", nil)
+
+		// Kirim request ke fiber app
+		resp, err := app.Test(req)
+
+		// Periksa apakah ada error
+		assert.NoError(t, err)
+
+		// Periksa status code
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	// Test Invalid Sort
+	t.Run("Invalid Sort", func(t *testing.T) {
+		// Buat request untuk ListProducts dengan field sort yang tidak dikenal
+		req := httptest.NewRequest(http.MethodGet, "/product?sort=password", nil)
+
+		// Kirim request ke fiber app
+		resp, err := app.Test(req)
+
+		// Periksa apakah ada error
+		assert.NoError(t, err)
+
+		// Periksa status code
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
 	// Periksa apakah mock product service telah dipanggil
 	mockProductService.AssertExpectations(t)
-}
\ No newline at end of file
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Membuat app dengan endpoint /healthz dan /readyz
+func newHealthApp(service *services.HealthService) *fiber.App {
+	app := fiber.New()
+	healthHandler := handlers.NewHealthHandler(service)
+	app.Get("/healthz", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+	return app
+}
+
+// Memanggil /readyz dan membaca laporan kesehatan
+func getReadiness(t *testing.T, app *fiber.App) (int, domain.HealthReport) {
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	var report domain.HealthReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	return resp.StatusCode, report
+}
+
+// TestHealth adalah fungsi untuk menguji endpoint liveness dan readiness
+func TestHealth(t *testing.T) {
+	// Membuat HealthService dengan MongoDB (kritis) dan MySQL (non-kritis)
+	setup := func(mongoErr, mysqlErr error) *services.HealthService {
+		mongoChecker := new(mocks.MockHealthChecker)
+		mongoChecker.On("Ping", mock.Anything).Return(mongoErr)
+		mysqlChecker := new(mocks.MockHealthChecker)
+		mysqlChecker.On("Ping", mock.Anything).Return(mysqlErr)
+		return services.NewHealthService(time.Second,
+			services.HealthDependency{Name: "mongodb", Checker: mongoChecker, Critical: true},
+			services.HealthDependency{Name: "mysql", Checker: mysqlChecker},
+		)
+	}
+
+	// Test liveness tidak bergantung pada dependency
+	t.Run("Liveness", func(t *testing.T) {
+		app := newHealthApp(setup(errors.New("down"), errors.New("down")))
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	// Test semua dependency tersedia
+	t.Run("Ready", func(t *testing.T) {
+		status, report := getReadiness(t, newHealthApp(setup(nil, nil)))
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, domain.HealthOK, report.Status)
+		assert.Equal(t, domain.DependencyUp, report.Dependencies["mongodb"].Status)
+		assert.Equal(t, domain.DependencyUp, report.Dependencies["mysql"].Status)
+	})
+
+	// Test MySQL tidak tersedia: tetap siap dalam mode degraded
+	t.Run("Degraded Without MySQL", func(t *testing.T) {
+		status, report := getReadiness(t, newHealthApp(setup(nil, errors.New("connection refused"))))
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, domain.HealthDegraded, report.Status)
+		assert.Equal(t, domain.DependencyDown, report.Dependencies["mysql"].Status)
+		assert.Equal(t, "connection refused", report.Dependencies["mysql"].Error)
+	})
+
+	// Test MongoDB tidak tersedia: tidak siap
+	t.Run("Unavailable Without MongoDB", func(t *testing.T) {
+		status, report := getReadiness(t, newHealthApp(setup(errors.New("no reachable servers"), nil)))
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, domain.HealthUnavailable, report.Status)
+	})
+
+	// Test ping yang menggantung dihentikan oleh timeout
+	t.Run("Ping Timeout", func(t *testing.T) {
+		slowChecker := new(mocks.MockHealthChecker)
+		slowChecker.On("Ping", mock.Anything).Return(context.DeadlineExceeded).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		})
+		service := services.NewHealthService(20*time.Millisecond,
+			services.HealthDependency{Name: "mongodb", Checker: slowChecker, Critical: true})
+
+		start := time.Now()
+		report := service.Readiness(context.Background())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, domain.HealthUnavailable, report.Status)
+		assert.GreaterOrEqual(t, report.Dependencies["mongodb"].LatencyMS, float64(20))
+	})
+
+	// Test readiness gagal selama shutdown meskipun dependency tersedia
+	t.Run("Shutting Down", func(t *testing.T) {
+		service := setup(nil, nil)
+		service.MarkShuttingDown()
+		status, report := getReadiness(t, newHealthApp(service))
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.True(t, report.ShuttingDown)
+		assert.Equal(t, domain.DependencyUp, report.Dependencies["mongodb"].Status)
+	})
+}
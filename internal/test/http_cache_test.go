@@ -0,0 +1,127 @@
+package test
+
+import (
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetProductHTTPCache adalah fungsi untuk menguji ETag, Last-Modified dan conditional GET
+func TestGetProductHTTPCache(t *testing.T) {
+	// Buat mock product service
+	mockProductService := new(mocks.MockProductService)
+
+	// Buat fiber app dengan Cache-Control untuk GetProduct
+	app := fiber.New()
+	productHandler := handlers.NewProductHandler(mockProductService, handlers.WithCacheControl(map[string]string{
+		handlers.RouteGetProduct: "private, max-age=60",
+	}))
+	app.Get("/products/:id", productHandler.GetProduct)
+
+	updatedAt := time.Date(2024, 5, 1, 10, 30, 15, 500_000_000, time.UTC)
+	mockProduct := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10, UpdatedAt: updatedAt}
+	mockProductService.On("GetProduct", mock.Anything, "123").Return(mockProduct, nil)
+
+	// Ambil validator dari response pertama
+	req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	etag := resp.Header.Get(fiber.HeaderETag)
+	assert.NotEmpty(t, etag)
+	assert.NotContains(t, etag, "W/")
+	assert.Equal(t, "Wed, 01 May 2024 10:30:15 GMT", resp.Header.Get(fiber.HeaderLastModified))
+	assert.Equal(t, "private, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+
+	// Test If-None-Match cocok
+	t.Run("If-None-Match Matches", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, `"other", `+etag)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+	})
+
+	// Test If-None-Match tidak cocok, If-Modified-Since diabaikan
+	t.Run("If-None-Match Differs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, `"other"`)
+		req.Header.Set(fiber.HeaderIfModifiedSince, "Wed, 01 May 2024 10:30:15 GMT")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	// Test If-Modified-Since tidak ada perubahan
+	t.Run("If-Modified-Since Not Modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+		req.Header.Set(fiber.HeaderIfModifiedSince, "Wed, 01 May 2024 10:30:15 GMT")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	})
+
+	// Test If-Modified-Since sebelum perubahan terakhir
+	t.Run("If-Modified-Since Modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+		req.Header.Set(fiber.HeaderIfModifiedSince, "Wed, 01 May 2024 10:30:14 GMT")
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+// TestListProductsHTTPCache adalah fungsi untuk menguji ETag daftar produk
+func TestListProductsHTTPCache(t *testing.T) {
+	// Buat mock product service
+	mockProductService := new(mocks.MockProductService)
+
+	app := fiber.New()
+	productHandler := handlers.NewProductHandler(mockProductService)
+	app.Get("/product", productHandler.ListProducts)
+
+	mockProducts := []*domain.Product{
+		{ID: "123", Name: "Test Product", UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "456", Name: "Test Product 2", UpdatedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	mockProductService.On("ListProducts", mock.Anything, domain.ProductListOptions{}).Return(mockProducts, nil).Times(3)
+
+	req := httptest.NewRequest(http.MethodGet, "/product", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// Daftar produk tidak memakai Last-Modified karena penghapusan tidak menggesernya
+	assert.Empty(t, resp.Header.Get(fiber.HeaderLastModified))
+	// Tanpa konfigurasi, Cache-Control tidak dikirim
+	assert.Empty(t, resp.Header.Get(fiber.HeaderCacheControl))
+
+	req = httptest.NewRequest(http.MethodGet, "/product", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, resp.Header.Get(fiber.HeaderETag))
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	// If-Modified-Since diabaikan, daftar selalu dikirim ulang
+	req = httptest.NewRequest(http.MethodGet, "/product", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, "Fri, 03 May 2024 10:00:00 GMT")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	mockProductService.AssertExpectations(t)
+}
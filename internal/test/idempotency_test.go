@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/idempotency"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIdempotencyStores adalah fungsi untuk menguji penyimpanan Idempotency-Key memory dan Redis
+func TestIdempotencyStores(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	stores := map[string]ports.IdempotencyStore{
+		"Memory": idempotency.NewMemoryStore(),
+		"Redis":  idempotency.NewRedisStore(client, "test:"),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pending := &domain.IdempotencyRecord{Fingerprint: "abc", Pending: true}
+
+			existing, reserved, err := store.Reserve(ctx, "key-1", pending, time.Minute)
+			require.NoError(t, err)
+			assert.True(t, reserved)
+			assert.Nil(t, existing)
+
+			// Reserve kedua mengembalikan record yang tersimpan
+			existing, reserved, err = store.Reserve(ctx, "key-1", pending, time.Minute)
+			require.NoError(t, err)
+			assert.False(t, reserved)
+			assert.True(t, existing.Pending)
+
+			completed := &domain.IdempotencyRecord{Fingerprint: "abc", Status: 201, Body: []byte(`{"id":"1"}`)}
+			require.NoError(t, store.Complete(ctx, "key-1", completed, time.Minute))
+			existing, _, err = store.Reserve(ctx, "key-1", pending, time.Minute)
+			require.NoError(t, err)
+			assert.False(t, existing.Pending)
+			assert.Equal(t, completed.Body, existing.Body)
+
+			// Setelah dihapus, key bisa dipakai lagi
+			require.NoError(t, store.Release(ctx, "key-1"))
+			_, reserved, err = store.Reserve(ctx, "key-1", pending, time.Minute)
+			require.NoError(t, err)
+			assert.True(t, reserved)
+
+			// Record pending yang diperpanjang tidak kedaluwarsa
+			_, reserved, err = store.Reserve(ctx, "key-2", pending, 30*time.Millisecond)
+			require.NoError(t, err)
+			require.True(t, reserved)
+			require.NoError(t, store.Extend(ctx, "key-2", time.Minute))
+			time.Sleep(50 * time.Millisecond)
+			server.FastForward(50 * time.Millisecond)
+			existing, reserved, err = store.Reserve(ctx, "key-2", pending, time.Minute)
+			require.NoError(t, err)
+			assert.False(t, reserved)
+			assert.True(t, existing.Pending)
+
+			// Key yang sudah tidak ada tidak dibuat ulang
+			require.NoError(t, store.Extend(ctx, "key-3", time.Minute))
+			_, reserved, err = store.Reserve(ctx, "key-3", pending, time.Minute)
+			require.NoError(t, err)
+			assert.True(t, reserved)
+		})
+	}
+}
+
+// TestIdempotencyMiddleware adalah fungsi untuk menguji replay, payload berbeda dan kedaluwarsa
+func TestIdempotencyMiddleware(t *testing.T) {
+	newApp := func(store ports.IdempotencyStore, ttl time.Duration, status int) (*fiber.App, *int) {
+		calls := 0
+		app := fiber.New()
+		app.Post("/products", handlers.IdempotencyMiddleware(store, ttl), func(c *fiber.Ctx) error {
+			calls++
+			return c.Status(status).JSON(fiber.Map{"id": strconv.Itoa(calls)})
+		})
+		return app, &calls
+	}
+	send := func(t *testing.T, app *fiber.App, key, body string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(handlers.HeaderIdempotencyKey, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	// Test retry dengan key dan payload yang sama mendapat response yang sama
+	t.Run("Replay", func(t *testing.T) {
+		app, calls := newApp(idempotency.NewMemoryStore(), time.Hour, fiber.StatusCreated)
+
+		resp, first := send(t, app, "key-1", `{"name":"Test Product"}`)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+		resp, second := send(t, app, "key-1", `{"name":"Test Product"}`)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(handlers.HeaderIdempotencyReplayed))
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, *calls)
+
+		// Tanpa header, setiap request diproses
+		send(t, app, "", `{"name":"Test Product"}`)
+		assert.Equal(t, 2, *calls)
+	})
+
+	// Test key yang sama dengan payload berbeda ditolak
+	t.Run("Mismatched Payload", func(t *testing.T) {
+		app, calls := newApp(idempotency.NewMemoryStore(), time.Hour, fiber.StatusCreated)
+
+		send(t, app, "key-1", `{"name":"Test Product"}`)
+		resp, _ := send(t, app, "key-1", `{"name":"Other Product"}`)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, 1, *calls)
+	})
+
+	// Test retry saat request pertama masih diproses ditolak dengan 409
+	t.Run("In Progress", func(t *testing.T) {
+		app := fiber.New()
+		var nestedStatus int
+		app.Post("/products", handlers.IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour), func(c *fiber.Ctx) error {
+			if nestedStatus == 0 {
+				// Retry dikirim sebelum request pertama selesai
+				resp, _ := send(t, app, "key-1", `{}`)
+				nestedStatus = resp.StatusCode
+			}
+			return c.SendStatus(fiber.StatusCreated)
+		})
+
+		resp, _ := send(t, app, "key-1", `{}`)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, fiber.StatusConflict, nestedStatus)
+	})
+
+	// Test response error server tidak disimpan
+	t.Run("Server Error", func(t *testing.T) {
+		app, calls := newApp(idempotency.NewMemoryStore(), time.Hour, fiber.StatusInternalServerError)
+
+		send(t, app, "key-1", `{}`)
+		send(t, app, "key-1", `{}`)
+		assert.Equal(t, 2, *calls)
+	})
+
+	// Test response error setelah perubahan tersimpan tetap disimpan agar tidak diulang
+	t.Run("Server Error After Commit", func(t *testing.T) {
+		app := fiber.New()
+		calls := 0
+		app.Post("/products", handlers.IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour), func(c *fiber.Ctx) error {
+			calls++
+			domain.RecordWriteCommitted(c.UserContext())
+			return c.SendStatus(fiber.StatusInternalServerError)
+		})
+
+		send(t, app, "key-1", `{}`)
+		resp, _ := send(t, app, "key-1", `{}`)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(handlers.HeaderIdempotencyReplayed))
+		assert.Equal(t, 1, calls)
+	})
+
+	// Test key kedaluwarsa setelah ttl
+	t.Run("Expiry", func(t *testing.T) {
+		app, calls := newApp(idempotency.NewMemoryStore(), 20*time.Millisecond, fiber.StatusCreated)
+
+		send(t, app, "key-1", `{}`)
+		time.Sleep(30 * time.Millisecond)
+		resp, _ := send(t, app, "key-1", `{}`)
+		assert.Empty(t, resp.Header.Get(handlers.HeaderIdempotencyReplayed))
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/logging"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Membaca satu baris log JSON
+func decodeLogLine(t *testing.T, line string) map[string]interface{} {
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(line), &entry))
+	return entry
+}
+
+// TestLogger adalah fungsi untuk menguji field request, redaksi dan level log
+func TestLogger(t *testing.T) {
+	// Test field dari context dan redaksi field sensitif
+	t.Run("Context And Redaction", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Level: "info", Format: "json"})
+		require.NoError(t, err)
+
+		ctx := domain.ContextWithRequestInfo(context.Background(), domain.RequestInfo{RequestID: "req-1"})
+		ctx = domain.ContextWithTenant(domain.ContextWithActor(ctx, "alice"), "acme")
+		logger.InfoContext(ctx, "login", "password", "hunter2", "api_key", "gfp_abc", slog.Group("auth", "Authorization", "Bearer x"), "product_id", "123")
+
+		entry := decodeLogLine(t, buf.String())
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, "acme", entry["tenant"])
+		assert.Equal(t, "alice", entry["actor"])
+		assert.Equal(t, logging.Redacted, entry["password"])
+		assert.Equal(t, logging.Redacted, entry["api_key"])
+		assert.Equal(t, logging.Redacted, entry["auth"].(map[string]interface{})["Authorization"])
+		assert.Equal(t, "123", entry["product_id"])
+	})
+
+	// Test trace_id dan span_id dari span yang aktif
+	t.Run("Trace Correlation", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Level: "info", Format: "json"})
+		require.NoError(t, err)
+
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "operation")
+		defer span.End()
+		logger.InfoContext(ctx, "traced")
+
+		entry := decodeLogLine(t, buf.String())
+		assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+		assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+	})
+
+	// Test level minimum dan format text
+	t.Run("Level And Format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Level: "warn", Format: "text"})
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		logger.Warn("shown")
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "msg=shown")
+
+		_, err = logging.New(&buf, logging.Options{Level: "verbose"})
+		assert.Error(t, err)
+		_, err = logging.New(&buf, logging.Options{Format: "xml"})
+		assert.Error(t, err)
+	})
+}
+
+// TestRequestLogging adalah fungsi untuk menguji X-Request-ID dan log akses
+func TestRequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Options{Level: "info", Format: "json"})
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	app := fiber.New()
+	app.Use(handlers.RequestIDMiddleware(), handlers.RequestInfoMiddleware(), handlers.RequestLogger())
+	app.Get("/products/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	// ID dari client dipakai jika valid
+	req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "client-req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "client-req-1", resp.Header.Get(fiber.HeaderXRequestID))
+
+	entry := decodeLogLine(t, strings.TrimSpace(buf.String()))
+	assert.Equal(t, "http request", entry["msg"])
+	assert.Equal(t, "client-req-1", entry["request_id"])
+	assert.Equal(t, "/products/:id", entry["route"])
+	assert.Equal(t, float64(fiber.StatusNoContent), entry["status"])
+
+	// ID yang tidak valid diganti dengan ID baru
+	req = httptest.NewRequest(http.MethodGet, "/products/123", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "bad id\nwith newline")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+	assert.NotEqual(t, "bad id\nwith newline", resp.Header.Get(fiber.HeaderXRequestID))
+}
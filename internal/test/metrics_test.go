@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/metrics"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Membaca isi endpoint /metrics
+func scrapeMetrics(t *testing.T, app *fiber.App) string {
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// TestMetrics adalah fungsi untuk menguji metric HTTP, repository, cache dan produk
+func TestMetrics(t *testing.T) {
+	// Test request dicatat per pola route dan status
+	t.Run("HTTP Requests", func(t *testing.T) {
+		m := metrics.New()
+		app := fiber.New()
+		app.Use(m.Middleware())
+		app.Get("/metrics", m.Handler())
+		app.Get("/api/products/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+		for _, path := range []string{"/api/products/1", "/api/products/2", "/unknown/path"} {
+			_, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+			require.NoError(t, err)
+		}
+
+		body := scrapeMetrics(t, app)
+		assert.Contains(t, body, `goproduct_http_requests_total{method="GET",route="/api/products/:id",status="204"} 2`)
+		assert.Contains(t, body, `goproduct_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Contains(t, body, `goproduct_http_request_duration_seconds_count{method="GET",route="/api/products/:id",status="204"} 2`)
+		assert.NotContains(t, body, `/unknown/path`)
+	})
+
+	// Test latensi dan error repository, not found tidak dihitung sebagai error
+	t.Run("Repository Operations", func(t *testing.T) {
+		m := metrics.New()
+		app := fiber.New()
+		app.Get("/metrics", m.Handler())
+
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments)
+		mongoRepo.On("DeleteProduct", mock.Anything, "1").Return(errors.New("connection reset"))
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("SaveProduct", mock.Anything, mock.Anything).Return(nil)
+
+		ctx := context.Background()
+		instrumentedMongo := m.InstrumentMongoProductRepository(mongoRepo)
+		_, err := instrumentedMongo.GetProduct(ctx, "missing")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		assert.Error(t, instrumentedMongo.DeleteProduct(ctx, "1"))
+		assert.NoError(t, m.InstrumentMySQLProductRepository(mysqlRepo).SaveProduct(ctx, &domain.Product{ID: "1"}))
+
+		body := scrapeMetrics(t, app)
+		assert.Contains(t, body, `goproduct_repository_operation_duration_seconds_count{operation="GetProduct",store="mongo"} 1`)
+		assert.Contains(t, body, `goproduct_repository_operation_duration_seconds_count{operation="SaveProduct",store="mysql"} 1`)
+		assert.Contains(t, body, `goproduct_repository_operation_errors_total{operation="DeleteProduct",store="mongo"} 1`)
+		assert.NotContains(t, body, `goproduct_repository_operation_errors_total{operation="GetProduct"`)
+	})
+
+	// Test statistik cache dan jumlah produk dibaca saat scrape
+	t.Run("Cache And Product Stats", func(t *testing.T) {
+		m := metrics.New()
+		app := fiber.New()
+		app.Get("/metrics", m.Handler())
+
+		statsRepo := new(mocks.MockProductStatsRepository)
+		statsRepo.On("ProductStats", mock.Anything, 5).Return([]domain.ProductStats{
+			{TenantID: "default", Total: 12, LowStock: 3},
+			{TenantID: "acme", Total: 4, LowStock: 0},
+		}, nil)
+		require.NoError(t, m.RegisterProductStats(statsRepo, 5, time.Second))
+		require.NoError(t, m.RegisterCacheStats(func() domain.CacheStats {
+			return domain.CacheStats{Hits: 7, Misses: 2}
+		}))
+
+		body := scrapeMetrics(t, app)
+		assert.Contains(t, body, `goproduct_products_total{tenant="default"} 12`)
+		assert.Contains(t, body, `goproduct_products_low_stock{tenant="default",threshold="5"} 3`)
+		assert.Contains(t, body, `goproduct_products_total{tenant="acme"} 4`)
+		assert.Contains(t, body, `goproduct_cache_requests_total{result="hit"} 7`)
+		assert.Contains(t, body, `goproduct_cache_requests_total{result="miss"} 2`)
+
+		// Statistik produk dihitung lintas tenant
+		ctx := statsRepo.Calls[0].Arguments.Get(0).(context.Context)
+		assert.True(t, domain.AllTenantsFromContext(ctx))
+	})
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"go-fiber-hexagonal-product/internal/core/domain"
 
 	"github.com/stretchr/testify/mock"
@@ -12,9 +13,21 @@ type MockProductService struct {
 }
 
 // GetProduct adalah mock implementasi dari metode GetProduct
-func (m *MockProductService) GetProduct(id string) (*domain.Product, error) {
-	// Panggil metode yang di-mock dengan argumen id
-	args := m.Called(id)
+func (m *MockProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	// Panggil metode yang di-mock dengan argumen ctx dan id
+	args := m.Called(ctx, id)
+	// Jika hasil panggilan memiliki nilai, kembalikan nilai tersebut
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Product), args.Error(1)
+	}
+	// Jika hasil panggilan tidak memiliki nilai, kembalikan error
+	return nil, args.Error(1)
+}
+
+// FindProduct adalah mock implementasi dari metode FindProduct
+func (m *MockProductService) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	// Panggil metode yang di-mock dengan argumen ctx, lookup dan value
+	args := m.Called(ctx, lookup, value)
 	// Jika hasil panggilan memiliki nilai, kembalikan nilai tersebut
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.Product), args.Error(1)
@@ -24,37 +37,56 @@ func (m *MockProductService) GetProduct(id string) (*domain.Product, error) {
 }
 
 // CreateProduct adalah mock implementasi dari metode CreateProduct
-func (m *MockProductService) CreateProduct(product *domain.Product) error {
-	// Panggil metode yang di-mock dengan argumen product
-	args := m.Called(product)
+func (m *MockProductService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	// Panggil metode yang di-mock dengan argumen ctx dan product
+	args := m.Called(ctx, product)
 	// Kembalikan error dari hasil panggilan
 	return args.Error(0)
 }
 
 // UpdateProduct adalah mock implementasi dari metode UpdateProduct
-func (m *MockProductService) UpdateProduct(product *domain.Product) error {
-	// Panggil metode yang di-mock dengan argumen product
-	args := m.Called(product)
+func (m *MockProductService) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	// Panggil metode yang di-mock dengan argumen ctx dan product
+	args := m.Called(ctx, product)
+	// Kembalikan error dari hasil panggilan
+	return args.Error(0)
+}
+
+// RestoreProduct adalah mock implementasi dari metode RestoreProduct
+func (m *MockProductService) RestoreProduct(ctx context.Context, product *domain.Product) error {
+	// Panggil metode yang di-mock dengan argumen ctx dan product
+	args := m.Called(ctx, product)
 	// Kembalikan error dari hasil panggilan
 	return args.Error(0)
 }
 
 // DeleteProduct adalah mock implementasi dari metode DeleteProduct
-func (m *MockProductService) DeleteProduct(id string) error {
-	// Panggil metode yang di-mock dengan argumen id
-	args := m.Called(id)
+func (m *MockProductService) DeleteProduct(ctx context.Context, id string) error {
+	// Panggil metode yang di-mock dengan argumen ctx dan id
+	args := m.Called(ctx, id)
 	// Kembalikan error dari hasil panggilan
 	return args.Error(0)
 }
 
 // ListProducts adalah mock implementasi dari metode ListProducts
-func (m *MockProductService) ListProducts() ([]*domain.Product, error) {
-	// Panggil metode yang di-mock
-	args := m.Called()
+func (m *MockProductService) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	// Panggil metode yang di-mock dengan argumen ctx dan opts
+	args := m.Called(ctx, opts)
 	// Jika hasil panggilan memiliki nilai, kembalikan nilai tersebut
 	if args.Get(0) != nil {
 		return args.Get(0).([]*domain.Product), args.Error(1)
 	}
 	// Jika hasil panggilan tidak memiliki nilai, kembalikan slice kosong untuk mencegah panic
 	return []*domain.Product{}, args.Error(1)
-}
\ No newline at end of file
+}
+
+// MockHealthChecker adalah mock implementasi dari HealthChecker
+type MockHealthChecker struct {
+	mock.Mock
+}
+
+// Ping adalah mock implementasi dari metode Ping
+func (m *MockHealthChecker) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -0,0 +1,187 @@
+package mocks
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMySQLProductRepository adalah mock implementasi dari MySQLProductRepository
+type MockMySQLProductRepository struct {
+	mock.Mock
+}
+
+// GetProduct adalah mock implementasi dari metode GetProduct
+func (m *MockMySQLProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindProduct adalah mock implementasi dari metode FindProduct
+func (m *MockMySQLProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	args := m.Called(ctx, lookup, value)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// CreateProduct adalah mock implementasi dari metode CreateProduct
+func (m *MockMySQLProductRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+// UpdateProduct adalah mock implementasi dari metode UpdateProduct
+func (m *MockMySQLProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+// SaveProduct adalah mock implementasi dari metode SaveProduct
+func (m *MockMySQLProductRepository) SaveProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+// DeleteProduct adalah mock implementasi dari metode DeleteProduct
+func (m *MockMySQLProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ListProducts adalah mock implementasi dari metode ListProducts
+func (m *MockMySQLProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Product), args.Error(1)
+	}
+	return []*domain.Product{}, args.Error(1)
+}
+
+// MockMongoProductRepository adalah mock implementasi dari MongoProductRepository
+type MockMongoProductRepository struct {
+	mock.Mock
+}
+
+// GetProduct adalah mock implementasi dari metode GetProduct
+func (m *MockMongoProductRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindProduct adalah mock implementasi dari metode FindProduct
+func (m *MockMongoProductRepository) FindProduct(ctx context.Context, lookup domain.ProductLookup, value string) (*domain.Product, error) {
+	args := m.Called(ctx, lookup, value)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// CreateProduct adalah mock implementasi dari metode CreateProduct
+func (m *MockMongoProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (string, error) {
+	args := m.Called(ctx, product)
+	return args.String(0), args.Error(1)
+}
+
+// UpdateProduct adalah mock implementasi dari metode UpdateProduct
+func (m *MockMongoProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+// DeleteProduct adalah mock implementasi dari metode DeleteProduct
+func (m *MockMongoProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ListProducts adalah mock implementasi dari metode ListProducts
+func (m *MockMongoProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]*domain.Product, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Product), args.Error(1)
+	}
+	return []*domain.Product{}, args.Error(1)
+}
+
+// MockAuditRepository adalah mock implementasi dari AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+// AppendAudit adalah mock implementasi dari metode AppendAudit
+func (m *MockAuditRepository) AppendAudit(ctx context.Context, entry *domain.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+// ListAudit adalah mock implementasi dari metode ListAudit
+func (m *MockAuditRepository) ListAudit(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.AuditEntry), args.Error(1)
+	}
+	return []*domain.AuditEntry{}, args.Error(1)
+}
+
+// MockRevisionRepository adalah mock implementasi dari RevisionRepository
+type MockRevisionRepository struct {
+	mock.Mock
+}
+
+// SaveRevision adalah mock implementasi dari metode SaveRevision
+func (m *MockRevisionRepository) SaveRevision(ctx context.Context, revision *domain.ProductRevision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+
+// ListRevisions adalah mock implementasi dari metode ListRevisions
+func (m *MockRevisionRepository) ListRevisions(ctx context.Context, productID string) ([]*domain.ProductRevision, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.ProductRevision), args.Error(1)
+	}
+	return []*domain.ProductRevision{}, args.Error(1)
+}
+
+// GetRevision adalah mock implementasi dari metode GetRevision
+func (m *MockRevisionRepository) GetRevision(ctx context.Context, productID string, revision int) (*domain.ProductRevision, error) {
+	args := m.Called(ctx, productID, revision)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.ProductRevision), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetRevisionAsOf adalah mock implementasi dari metode GetRevisionAsOf
+func (m *MockRevisionRepository) GetRevisionAsOf(ctx context.Context, productID string, asOf time.Time) (*domain.ProductRevision, error) {
+	args := m.Called(ctx, productID, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.ProductRevision), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// MockProductStatsRepository adalah mock implementasi dari ProductStatsRepository
+type MockProductStatsRepository struct {
+	mock.Mock
+}
+
+// ProductStats adalah mock implementasi dari metode ProductStats
+func (m *MockProductStatsRepository) ProductStats(ctx context.Context, lowStockThreshold int) ([]domain.ProductStats, error) {
+	args := m.Called(ctx, lowStockThreshold)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.ProductStats), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
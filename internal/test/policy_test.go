@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Context dengan principal yang memiliki role dan scope tertentu
+func principalContext(roles []string, scopes []string) context.Context {
+	return domain.ContextWithPrincipal(context.Background(), &domain.Principal{
+		Subject: "tester",
+		Roles:   roles,
+		Scopes:  scopes,
+		Method:  domain.AuthMethodJWT,
+	})
+}
+
+// TestPolicyAuthorizer adalah fungsi untuk menguji pemetaan role/scope ke permission
+func TestPolicyAuthorizer(t *testing.T) {
+	authorizer := services.NewPolicyAuthorizer(services.DefaultPolicy())
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		permission string
+		allowed    bool
+	}{
+		{"viewer read", principalContext([]string{"viewer"}, nil), domain.PermissionProductsRead, true},
+		{"viewer create", principalContext([]string{"viewer"}, nil), domain.PermissionProductsCreate, false},
+		{"editor update", principalContext([]string{"editor"}, nil), domain.PermissionProductsUpdate, true},
+		{"editor delete", principalContext([]string{"editor"}, nil), domain.PermissionProductsDelete, false},
+		{"stock clerk adjust", principalContext([]string{"stock_clerk"}, nil), domain.PermissionProductsAdjustStock, true},
+		{"admin wildcard", principalContext([]string{"admin"}, nil), domain.PermissionProductsRestore, true},
+		{"scope as permission", principalContext(nil, []string{"products:delete"}), domain.PermissionProductsDelete, true},
+		{"scope prefix wildcard", principalContext(nil, []string{"products:*"}), domain.PermissionProductsRestore, true},
+		{"scope as role", principalContext(nil, []string{"admin"}), domain.PermissionAuditRead, true},
+		{"unknown role", principalContext([]string{"intern"}, nil), domain.PermissionProductsRead, false},
+		{"no principal", context.Background(), domain.PermissionProductsRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.ctx, tt.permission)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var permErr *domain.PermissionError
+			require.ErrorAs(t, err, &permErr)
+			assert.Equal(t, tt.permission, permErr.Permission)
+		})
+	}
+}
+
+// TestProductServiceAuthorization adalah fungsi untuk menguji pemeriksaan permission di ProductService
+func TestProductServiceAuthorization(t *testing.T) {
+	authorizer := services.NewPolicyAuthorizer(services.DefaultPolicy())
+	existing := &domain.Product{ID: "123", Name: "Test Product", Price: 100, Stock: 5}
+
+	// Test stock clerk boleh mengubah stok saja
+	t.Run("StockClerkAdjustsStock", func(t *testing.T) {
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuthorizer(authorizer))
+
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(existing, nil).Once()
+		mockMongoRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockMySQLRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+
+		product := &domain.Product{ID: "123", Name: "Test Product", Price: 100, Stock: 2}
+		err := service.UpdateProduct(principalContext([]string{"stock_clerk"}, nil), product)
+
+		assert.NoError(t, err)
+		mockMongoRepo.AssertExpectations(t)
+		mockMySQLRepo.AssertExpectations(t)
+	})
+
+	// Test stock clerk tidak boleh mengubah harga
+	t.Run("StockClerkChangesPrice", func(t *testing.T) {
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuthorizer(authorizer))
+
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(existing, nil).Once()
+
+		product := &domain.Product{ID: "123", Name: "Test Product", Price: 1, Stock: 2}
+		err := service.UpdateProduct(principalContext([]string{"stock_clerk"}, nil), product)
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.PermissionProductsUpdate, permErr.Permission)
+		mockMongoRepo.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test viewer ditolak sebelum produk dibaca dari store
+	t.Run("ViewerUpdates", func(t *testing.T) {
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuthorizer(authorizer))
+
+		product := &domain.Product{ID: "123", Name: "Test Product", Price: 100, Stock: 2}
+		err := service.UpdateProduct(principalContext([]string{"viewer"}, nil), product)
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.PermissionProductsUpdate, permErr.Permission)
+		mockMongoRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test editor tidak boleh me-restore produk langsung lewat ProductService
+	t.Run("EditorRestores", func(t *testing.T) {
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuthorizer(authorizer))
+
+		product := &domain.Product{ID: "123", Name: "Test Product", Price: 100, Stock: 2}
+		err := service.RestoreProduct(principalContext([]string{"editor"}, nil), product)
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.PermissionProductsRestore, permErr.Permission)
+		mockMongoRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test viewer tidak boleh menghapus produk
+	t.Run("ViewerDeletes", func(t *testing.T) {
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithAuthorizer(authorizer))
+
+		err := service.DeleteProduct(principalContext([]string{"viewer"}, nil), "123")
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.PermissionProductsDelete, permErr.Permission)
+		mockMongoRepo.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything)
+	})
+}
+
+// TestForbiddenResponse adalah fungsi untuk menguji response 403 problem+json
+func TestForbiddenResponse(t *testing.T) {
+	app := fiber.New()
+	mockProductService := new(mocks.MockProductService)
+	productHandler := handlers.NewProductHandler(mockProductService)
+	app.Delete("/products/:id", productHandler.DeleteProduct)
+
+	mockProductService.On("DeleteProduct", mock.Anything, "123").
+		Return(&domain.PermissionError{Permission: domain.PermissionProductsDelete}).Once()
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/products/123", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, handlers.MIMEApplicationProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+
+	var problem handlers.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, domain.PermissionProductsDelete, problem.MissingPermission)
+	assert.Equal(t, fiber.StatusForbidden, problem.Status)
+}
+
+// TestLoadPolicyFile adalah fungsi untuk menguji pembacaan file policy YAML
+func TestLoadPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("roles:\n  auditor: [audit:read, products:read]\n"), 0o600))
+
+	roles, err := config.LoadPolicyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audit:read", "products:read"}, roles["auditor"])
+
+	// File tanpa role ditolak
+	require.NoError(t, os.WriteFile(path, []byte("roles: {}\n"), 0o600))
+	_, err = config.LoadPolicyFile(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/idgen"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIDGenerators adalah fungsi untuk menguji format, keunikan dan urutan ID dari setiap strategi
+func TestIDGenerators(t *testing.T) {
+	snowflake, err := idgen.NewSnowflakeGenerator(7)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		generator ports.IDGenerator
+		pattern   string
+		// ID yang dibuat berurutan juga terurut secara leksikografis
+		sortable bool
+	}{
+		{name: "ObjectID", generator: idgen.NewObjectIDGenerator(), pattern: `^[0-9a-f]{24}$`},
+		{name: "UUIDv7", generator: idgen.NewUUIDv7Generator(), pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, sortable: true},
+		{name: "ULID", generator: idgen.NewULIDGenerator(), pattern: `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, sortable: true},
+		{name: "Snowflake", generator: snowflake, pattern: `^[0-9]{1,19}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := regexp.MustCompile(tt.pattern)
+			ids := make([]string, 5000)
+			seen := make(map[string]bool, len(ids))
+			for i := range ids {
+				ids[i] = tt.generator.NewID()
+				require.Regexp(t, pattern, ids[i])
+				require.False(t, seen[ids[i]], "duplicate id %s", ids[i])
+				require.True(t, domain.ValidProductID(ids[i]))
+				seen[ids[i]] = true
+			}
+			if tt.sortable {
+				assert.True(t, sort.StringsAreSorted(ids))
+			}
+		})
+	}
+
+	// Test ID Snowflake naik dan memuat node ID
+	t.Run("Snowflake Layout", func(t *testing.T) {
+		previous := int64(-1)
+		for i := 0; i < 5000; i++ {
+			id, err := strconv.ParseInt(snowflake.NewID(), 10, 64)
+			require.NoError(t, err)
+			require.Greater(t, id, previous)
+			assert.Equal(t, int64(7), id>>12&idgen.MaxSnowflakeNodeID)
+			previous = id
+		}
+
+		_, err := idgen.NewSnowflakeGenerator(idgen.MaxSnowflakeNodeID + 1)
+		assert.Error(t, err)
+	})
+}
+
+// TestValidProductID adalah fungsi untuk menguji format ID produk dari client
+func TestValidProductID(t *testing.T) {
+	for _, id := range []string{"sku-123", "A", "01HZY6J3K4M5N6P7Q8R9S0T1V2", "550e8400-e29b-41d4-a716-446655440000", strings.Repeat("a", 64)} {
+		assert.True(t, domain.ValidProductID(id), id)
+	}
+	for _, id := range []string{"", "-leading", "has space", "slash/id", "dot.id", strings.Repeat("a", 65)} {
+		assert.False(t, domain.ValidProductID(id), id)
+	}
+}
+
+// TestClientProductIDs adalah fungsi untuk menguji ID produk dari client
+func TestClientProductIDs(t *testing.T) {
+	ctx := context.Background()
+
+	// Test ID dari client dipakai di semua store jika diizinkan
+	t.Run("Accepted", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, productWithID("sku-1")).Return("sku-1", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("sku-1")).Return(nil)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithClientIDs())
+		product := &domain.Product{ID: "sku-1", Name: "Test Product"}
+		require.NoError(t, service.CreateProduct(ctx, product))
+		assert.Equal(t, "sku-1", product.ID)
+		mongoRepo.AssertExpectations(t)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test ID kosong tetap dibuat oleh pembuat ID
+	t.Run("Generated When Empty", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return(nil)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithClientIDs())
+		product := &domain.Product{Name: "Test Product"}
+		require.NoError(t, service.CreateProduct(ctx, product))
+		assert.Equal(t, "abc", product.ID)
+	})
+
+	// Test ID tidak valid ditolak sebelum menulis store
+	t.Run("Invalid", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithClientIDs())
+		err := service.CreateProduct(ctx, &domain.Product{ID: "../etc", Name: "Test Product"})
+		assert.ErrorIs(t, err, domain.ErrInvalidProductID)
+		mongoRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+		mysqlRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test ID dari client diabaikan jika tidak diizinkan
+	t.Run("Ignored When Disabled", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return(nil)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll)
+		product := &domain.Product{ID: "sku-1", Name: "Test Product"}
+		require.NoError(t, service.CreateProduct(ctx, product))
+		assert.Equal(t, "abc", product.ID)
+	})
+
+	// Test ID yang sudah dipakai tidak menghapus produk yang ada
+	t.Run("Duplicate", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		errExists := fmt.Errorf("%w: sku-1", domain.ErrProductExists)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("", errExists)
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(errExists)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithClientIDs())
+		err := service.CreateProduct(ctx, &domain.Product{ID: "sku-1", Name: "Test Product"})
+		assert.ErrorIs(t, err, domain.ErrProductExists)
+		mongoRepo.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything)
+		mysqlRepo.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything)
+	})
+}
+
+// TestCreateProductIDErrors adalah fungsi untuk menguji response handler untuk error ID produk
+func TestCreateProductIDErrors(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	app := fiber.New()
+	app.Post("/products", handlers.NewProductHandler(mockProductService).CreateProduct)
+
+	mockProductService.On("CreateProduct", mock.Anything, productWithID("bad id")).Return(fmt.Errorf("%w: must be 1-64 letters", domain.ErrInvalidProductID))
+	mockProductService.On("CreateProduct", mock.Anything, productWithID("sku-1")).Return(fmt.Errorf("mongodb: %w", domain.ErrProductExists))
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "Invalid", body: `{"id":"bad id","name":"Test Product"}`, status: fiber.StatusBadRequest},
+		{name: "Conflict", body: `{"id":"sku-1","name":"Test Product"}`, status: fiber.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+// TestIDStrategyConfig adalah fungsi untuk menguji validasi konfigurasi strategi ID
+func TestIDStrategyConfig(t *testing.T) {
+	cfg := config.Default()
+	assert.Equal(t, "objectid", cfg.IDStrategy)
+	assert.False(t, cfg.ClientProductIDs)
+
+	cfg.IDStrategy = "snowflake"
+	cfg.SnowflakeNodeID = 1024
+	err := cfg.Validate()
+	var validationErr *config.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Errors, 1)
+	assert.Equal(t, "snowflake_node_id", validationErr.Errors[0].Key)
+
+	cfg.IDStrategy = "autoincrement"
+	cfg.SnowflakeNodeID = 0
+	err = cfg.Validate()
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Errors, 1)
+	assert.Equal(t, "id_strategy", validationErr.Errors[0].Key)
+}
@@ -0,0 +1,303 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Semua slug belum dipakai, dipakai test yang membuat produk tanpa slug
+func slugAvailable(mongoRepo *mocks.MockMongoProductRepository) {
+	mongoRepo.On("FindProduct", mock.Anything, domain.LookupSlug, mock.Anything).Return(nil, domain.ErrProductNotFound).Maybe()
+}
+
+// TestNormalizeGTIN adalah fungsi untuk menguji validasi check digit dan normalisasi barcode
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+		valid    bool
+	}{
+		{name: "GTIN-8", code: "96385074", expected: "00000096385074", valid: true},
+		{name: "UPC-A", code: "036000291452", expected: "00036000291452", valid: true},
+		{name: "EAN-13", code: "4006381333931", expected: "04006381333931", valid: true},
+		{name: "GTIN-14", code: "00036000291452", expected: "00036000291452", valid: true},
+		{name: "Wrong Check Digit", code: "4006381333932"},
+		{name: "Not Digits", code: "40063813339a1"},
+		{name: "Wrong Length", code: "1234567890"},
+		{name: "Empty", code: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode, err := domain.NormalizeGTIN(tt.code)
+			if !tt.valid {
+				assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, barcode)
+		})
+	}
+}
+
+// TestSlugify adalah fungsi untuk menguji pembuatan slug dari nama produk
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "kopi-susu-gula-aren", domain.Slugify("Kopi Susu Gula Aren"))
+	assert.Equal(t, "caf-latte-250ml", domain.Slugify("  Café -- Latte (250ml)!! "))
+	assert.Equal(t, "", domain.Slugify("!!!"))
+
+	long := domain.Slugify(strings.Repeat("ab ", 100))
+	assert.LessOrEqual(t, len(long), domain.MaxSlugLength)
+	assert.True(t, domain.ValidSlug(long))
+
+	assert.False(t, domain.ValidSlug("Has-Upper"))
+	assert.False(t, domain.ValidSlug("double--dash"))
+	assert.True(t, domain.ValidSKU("SKU-001.a_b"))
+	assert.False(t, domain.ValidSKU("-sku"))
+}
+
+// TestProductIdentifiers adalah fungsi untuk menguji validasi dan pengisian SKU, barcode dan slug oleh ProductService
+func TestProductIdentifiers(t *testing.T) {
+	ctx := context.Background()
+
+	// Test barcode dinormalkan dan slug yang sudah dipakai diberi akhiran angka
+	t.Run("Create", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("FindProduct", mock.Anything, domain.LookupSlug, "kopi-susu").Return(&domain.Product{ID: "other"}, nil).Once()
+		mongoRepo.On("FindProduct", mock.Anything, domain.LookupSlug, "kopi-susu-2").Return(nil, domain.ErrProductNotFound).Once()
+		stored := mock.MatchedBy(func(product *domain.Product) bool {
+			return product.SKU == "KS-1" && product.Barcode == "00036000291452" && product.Slug == "kopi-susu-2"
+		})
+		mongoRepo.On("CreateProduct", mock.Anything, stored).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, stored).Return(nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		product := &domain.Product{Name: "Kopi Susu", SKU: " KS-1 ", Barcode: "036000291452"}
+		require.NoError(t, service.CreateProduct(ctx, product))
+		assert.Equal(t, "kopi-susu-2", product.Slug)
+		mongoRepo.AssertExpectations(t)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test slug dari client tidak dicek ulang, keunikannya dijamin index unik
+	t.Run("Explicit Slug", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		require.NoError(t, service.CreateProduct(ctx, &domain.Product{Name: "Kopi Susu", Slug: "kopi"}))
+		mongoRepo.AssertNotCalled(t, "FindProduct", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// Test identifier tidak valid ditolak sebelum menulis store
+	t.Run("Invalid", func(t *testing.T) {
+		for _, product := range []*domain.Product{
+			{Name: "Test Product", SKU: "bad sku"},
+			{Name: "Test Product", Barcode: "4006381333932"},
+			{Name: "Test Product", Slug: "Not A Slug"},
+		} {
+			mongoRepo := new(mocks.MockMongoProductRepository)
+			mysqlRepo := new(mocks.MockMySQLProductRepository)
+
+			service := services.NewProductService(mongoRepo, mysqlRepo)
+			assert.ErrorIs(t, service.CreateProduct(ctx, product), domain.ErrInvalidProduct)
+			mongoRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+			mysqlRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+		}
+	})
+
+	// Test update tanpa identifier mempertahankan nilai yang tersimpan
+	t.Run("Update Keeps Identifiers", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		existing := &domain.Product{ID: "abc", Name: "Kopi", SKU: "KS-1", Barcode: "00036000291452", Slug: "kopi"}
+		mongoRepo.On("GetProduct", mock.Anything, "abc").Return(existing, nil)
+		kept := mock.MatchedBy(func(product *domain.Product) bool {
+			return product.SKU == "KS-1" && product.Barcode == "00036000291452" && product.Slug == "kopi"
+		})
+		mongoRepo.On("UpdateProduct", mock.Anything, kept).Return(nil)
+		mysqlRepo.On("UpdateProduct", mock.Anything, kept).Return(nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		require.NoError(t, service.UpdateProduct(ctx, &domain.Product{ID: "abc", Name: "Kopi", Stock: 5}))
+		mongoRepo.AssertExpectations(t)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test stock clerk tidak boleh mengubah SKU
+	t.Run("Stock Clerk Changes SKU", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "abc").Return(&domain.Product{ID: "abc", Name: "Kopi", SKU: "KS-1"}, nil)
+
+		authorizer := services.NewPolicyAuthorizer(services.DefaultPolicy())
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithAuthorizer(authorizer))
+		err := service.UpdateProduct(principalContext([]string{"stock_clerk"}, nil), &domain.Product{ID: "abc", Name: "Kopi", SKU: "KS-2"})
+
+		var permErr *domain.PermissionError
+		require.ErrorAs(t, err, &permErr)
+		assert.Equal(t, domain.PermissionProductsUpdate, permErr.Permission)
+		mongoRepo.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test perubahan identifier tercatat di audit, identifier kosong tidak dicatat
+	t.Run("Audit Diff", func(t *testing.T) {
+		before := &domain.Product{Name: "Kopi", SKU: "KS-1"}
+		after := &domain.Product{Name: "Kopi", SKU: "KS-2", Slug: "kopi"}
+		assert.Equal(t, []domain.FieldChange{
+			{Field: "sku", Before: "KS-1", After: "KS-2"},
+			{Field: "slug", Before: nil, After: "kopi"},
+		}, domain.DiffProducts(before, after))
+		assert.Len(t, domain.DiffProducts(nil, &domain.Product{Name: "Kopi"}), 3)
+	})
+
+	// Test pencarian barcode memakai bentuk GTIN-14
+	t.Run("Find Barcode", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("FindProduct", mock.Anything, domain.LookupBarcode, "00036000291452").Return(&domain.Product{ID: "abc"}, nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		product, err := service.FindProduct(ctx, domain.LookupBarcode, "036000291452")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", product.ID)
+
+		_, err = service.FindProduct(ctx, domain.LookupBarcode, "036000291453")
+		assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+	})
+
+	// Test pencarian beralih ke MySQL saat MongoDB tidak tersedia
+	t.Run("Find Fallback", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("FindProduct", mock.Anything, domain.LookupSKU, "KS-1").Return(nil, domain.ErrStoreUnavailable)
+		mysqlRepo.On("FindProduct", mock.Anything, domain.LookupSKU, "KS-1").Return(&domain.Product{ID: "abc"}, nil)
+
+		policy := services.ReadFallbackPolicy{Get: services.ReadFallback{Enabled: true}}
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(policy))
+		readCtx, source := domain.ContextWithReadSource(ctx)
+		product, err := service.FindProduct(readCtx, domain.LookupSKU, "KS-1")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", product.ID)
+		assert.Equal(t, domain.DataSourceMySQL, source.Source)
+	})
+}
+
+// TestProductLookupRoutes adalah fungsi untuk menguji endpoint pencarian produk berdasarkan SKU, barcode dan slug
+func TestProductLookupRoutes(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	productHandler := handlers.NewProductHandler(mockProductService)
+	app := fiber.New()
+	// Urutan route sama dengan App.SetupRoutes
+	app.Get("/products/by-sku/:sku", productHandler.GetProductBySKU)
+	app.Get("/products/by-barcode/:code", productHandler.GetProductByBarcode)
+	app.Get("/products/by-slug/:slug", productHandler.GetProductBySlug)
+	app.Get("/products/:id", productHandler.GetProduct)
+
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupSKU, "KS-1").Return(&domain.Product{ID: "abc", SKU: "KS-1"}, nil)
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupSKU, "missing").Return(nil, fmt.Errorf("%w: sku missing", domain.ErrProductNotFound))
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupBarcode, "4006381333931").Return(&domain.Product{ID: "abc"}, nil)
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupBarcode, "123").Return(nil, fmt.Errorf("%w: barcode must have 8, 12, 13 or 14 digits", domain.ErrInvalidProduct))
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupSlug, "kopi-susu").Return(&domain.Product{ID: "abc"}, nil)
+	mockProductService.On("FindProduct", mock.Anything, domain.LookupSlug, "down").Return(nil, domain.ErrStoreUnavailable)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "SKU", path: "/products/by-sku/KS-1", status: fiber.StatusOK},
+		{name: "SKU Not Found", path: "/products/by-sku/missing", status: fiber.StatusNotFound},
+		{name: "Barcode", path: "/products/by-barcode/4006381333931", status: fiber.StatusOK},
+		{name: "Invalid Barcode", path: "/products/by-barcode/123", status: fiber.StatusBadRequest},
+		{name: "Slug", path: "/products/by-slug/kopi-susu", status: fiber.StatusOK},
+		{name: "Store Unavailable", path: "/products/by-slug/down", status: fiber.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == fiber.StatusOK {
+				var product domain.Product
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&product))
+				assert.Equal(t, "abc", product.ID)
+			}
+		})
+	}
+	mockProductService.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+}
+
+// TestProductIdentifierConflict adalah fungsi untuk menguji response 409 saat identifier sudah dipakai
+func TestProductIdentifierConflict(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	productHandler := handlers.NewProductHandler(mockProductService)
+	app := fiber.New()
+	app.Put("/products/:id", productHandler.UpdateProduct)
+
+	mockProductService.On("UpdateProduct", mock.Anything, productWithID("abc")).Return(fmt.Errorf("mysql: %w", domain.ErrProductExists))
+	mockProductService.On("UpdateProduct", mock.Anything, productWithID("bad")).Return(fmt.Errorf("%w: sku must be 1-64 letters", domain.ErrInvalidProduct))
+
+	for id, status := range map[string]int{"abc": fiber.StatusConflict, "bad": fiber.StatusBadRequest} {
+		req := httptest.NewRequest(http.MethodPut, "/products/"+id, strings.NewReader(`{"name":"Kopi","sku":"KS-1"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, id)
+	}
+}
+
+// TestProductNotFoundResponses adalah fungsi untuk menguji response 404 untuk domain.ErrProductNotFound yang dibungkus
+func TestProductNotFoundResponses(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	productHandler := handlers.NewProductHandler(mockProductService)
+	app := fiber.New()
+	app.Get("/products/:id", productHandler.GetProduct)
+	app.Put("/products/:id", productHandler.UpdateProduct)
+	app.Delete("/products/:id", productHandler.DeleteProduct)
+
+	errNotFound := fmt.Errorf("%w: missing", domain.ErrProductNotFound)
+	mockProductService.On("GetProduct", mock.Anything, "missing").Return(nil, errNotFound)
+	mockProductService.On("GetProduct", mock.Anything, "broken").Return(nil, errors.New("decode failed"))
+	mockProductService.On("UpdateProduct", mock.Anything, productWithID("missing")).Return(errNotFound)
+	mockProductService.On("DeleteProduct", mock.Anything, "missing").Return(errNotFound)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "Get", method: http.MethodGet, path: "/products/missing", status: fiber.StatusNotFound},
+		{name: "Get Error", method: http.MethodGet, path: "/products/broken", status: fiber.StatusInternalServerError},
+		{name: "Update", method: http.MethodPut, path: "/products/missing", status: fiber.StatusNotFound},
+		{name: "Delete", method: http.MethodDelete, path: "/products/missing", status: fiber.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"name":"Kopi"}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/ratelimit"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/ports"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimiterBackends adalah fungsi untuk menguji token bucket pada backend memory dan Redis
+func TestRateLimiterBackends(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	backends := map[string]ports.RateLimiter{
+		"Memory": ratelimit.NewMemoryRateLimiter(),
+		"Redis":  ratelimit.NewRedisRateLimiter(client, "test:"),
+	}
+	limit := domain.RateLimit{Limit: 2, Period: time.Minute}
+
+	for name, limiter := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Kapasitas bucket sebesar Limit
+			res, err := limiter.Allow(ctx, "client-a", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 1, res.Remaining)
+
+			res, err = limiter.Allow(ctx, "client-a", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			// Bucket kosong, satu token terisi setiap 30 detik
+			res, err = limiter.Allow(ctx, "client-a", limit)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.InDelta(t, 30*time.Second, res.RetryAfter, float64(time.Second))
+			assert.InDelta(t, time.Minute, res.Reset, float64(time.Second))
+
+			// Bucket client lain terpisah
+			res, err = limiter.Allow(ctx, "client-b", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+
+			// Token tidak diambil dari bucket mana pun jika salah satu bucket kosong
+			results, err := limiter.AllowAll(ctx, []domain.RateLimitBucket{
+				{Key: "client-c", Limit: limit},
+				{Key: "client-a", Limit: limit},
+			})
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			assert.True(t, results[0].Allowed)
+			assert.Equal(t, 2, results[0].Remaining)
+			assert.False(t, results[1].Allowed)
+
+			res, err = limiter.Allow(ctx, "client-c", limit)
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.Remaining)
+		})
+	}
+}
+
+// TestMemoryRateLimiterRefill adalah fungsi untuk menguji pengisian ulang token
+func TestMemoryRateLimiterRefill(t *testing.T) {
+	limiter := ratelimit.NewMemoryRateLimiter()
+	limit := domain.RateLimit{Limit: 1, Period: 50 * time.Millisecond}
+
+	res, _ := limiter.Allow(context.Background(), "client", limit)
+	assert.True(t, res.Allowed)
+	res, _ = limiter.Allow(context.Background(), "client", limit)
+	assert.False(t, res.Allowed)
+
+	time.Sleep(60 * time.Millisecond)
+	res, _ = limiter.Allow(context.Background(), "client", limit)
+	assert.True(t, res.Allowed)
+}
+
+// TestRateLimitMiddleware adalah fungsi untuk menguji header RateLimit-* dan response 429
+func TestRateLimitMiddleware(t *testing.T) {
+	newApp := func(config handlers.RateLimitConfig) *fiber.App {
+		app := fiber.New()
+		app.Use(handlers.TenantMiddleware(handlers.TenantResolverConfig{}))
+		app.Use(handlers.RateLimitMiddleware(ratelimit.NewMemoryRateLimiter(), config))
+		app.Get("/products", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		app.Post("/products", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+		return app
+	}
+	send := func(t *testing.T, app *fiber.App, method, tenant string) *http.Response {
+		req := httptest.NewRequest(method, "/products", nil)
+		if tenant != "" {
+			req.Header.Set(handlers.HeaderTenantID, tenant)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Test budget tulis habis tanpa memengaruhi budget baca
+	t.Run("Separate Budgets", func(t *testing.T) {
+		app := newApp(handlers.RateLimitConfig{
+			Read:  domain.RateLimit{Limit: 5, Period: time.Minute},
+			Write: domain.RateLimit{Limit: 1, Period: time.Minute},
+		})
+
+		resp := send(t, app, http.MethodPost, "")
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get(handlers.HeaderRateLimitLimit))
+		assert.Equal(t, "0", resp.Header.Get(handlers.HeaderRateLimitRemaining))
+		assert.Equal(t, "1;w=60", resp.Header.Get(handlers.HeaderRateLimitPolicy))
+
+		resp = send(t, app, http.MethodPost, "")
+		assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, handlers.MIMEApplicationProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+
+		resp = send(t, app, http.MethodGet, "")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "5", resp.Header.Get(handlers.HeaderRateLimitLimit))
+		assert.Equal(t, "4", resp.Header.Get(handlers.HeaderRateLimitRemaining))
+	})
+
+	// Test batas tenant berlaku untuk semua client dalam tenant tersebut
+	t.Run("Tenant Budget", func(t *testing.T) {
+		app := newApp(handlers.RateLimitConfig{
+			Read:       domain.RateLimit{Limit: 10, Period: time.Minute},
+			TenantRead: domain.RateLimit{Limit: 2, Period: time.Minute},
+		})
+
+		assert.Equal(t, fiber.StatusOK, send(t, app, http.MethodGet, "acme").StatusCode)
+		resp := send(t, app, http.MethodGet, "acme")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(handlers.HeaderRateLimitLimit))
+		assert.Equal(t, fiber.StatusTooManyRequests, send(t, app, http.MethodGet, "acme").StatusCode)
+
+		// Tenant lain masih memiliki budget
+		assert.Equal(t, fiber.StatusOK, send(t, app, http.MethodGet, "globex").StatusCode)
+	})
+
+	// Test request yang ditolak tenant tidak mengurangi budget client
+	t.Run("Denied Request Keeps Client Budget", func(t *testing.T) {
+		app := newApp(handlers.RateLimitConfig{
+			Read:       domain.RateLimit{Limit: 3, Period: time.Minute},
+			TenantRead: domain.RateLimit{Limit: 1, Period: time.Minute},
+		})
+
+		assert.Equal(t, fiber.StatusOK, send(t, app, http.MethodGet, "acme").StatusCode)
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, fiber.StatusTooManyRequests, send(t, app, http.MethodGet, "acme").StatusCode)
+		}
+		// Budget client 3 masih cukup untuk dua tenant lain
+		assert.Equal(t, fiber.StatusOK, send(t, app, http.MethodGet, "globex").StatusCode)
+		assert.Equal(t, fiber.StatusOK, send(t, app, http.MethodGet, "initech").StatusCode)
+	})
+
+	// Test batas IP berlaku sebelum middleware berikutnya dijalankan
+	t.Run("IP Budget", func(t *testing.T) {
+		reached := 0
+		app := fiber.New()
+		app.Use(handlers.IPRateLimitMiddleware(ratelimit.NewMemoryRateLimiter(), func() domain.RateLimit {
+			return domain.RateLimit{Limit: 1, Period: time.Minute}
+		}))
+		app.Use(func(c *fiber.Ctx) error {
+			reached++
+			return c.SendStatus(fiber.StatusUnauthorized)
+		})
+
+		assert.Equal(t, fiber.StatusUnauthorized, send(t, app, http.MethodGet, "").StatusCode)
+		resp := send(t, app, http.MethodPost, "")
+		assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, 1, reached)
+	})
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/cache"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Kebijakan fallback dengan batas waktu singkat untuk test
+func testReadFallbackPolicy() services.ReadFallbackPolicy {
+	return services.ReadFallbackPolicy{
+		Get:  services.ReadFallback{Enabled: true, Timeout: 50 * time.Millisecond},
+		List: services.ReadFallback{Enabled: true},
+	}
+}
+
+// TestReadFallback adalah fungsi untuk menguji pembacaan dari MySQL saat MongoDB tidak tersedia
+func TestReadFallback(t *testing.T) {
+	mysqlProduct := &domain.Product{ID: "1", Name: "From MySQL", Price: 1000, Stock: 5}
+
+	// Test MongoDB normal dicatat sebagai sumber data
+	t.Run("Primary", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(&domain.Product{ID: "1"}, nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(testReadFallbackPolicy()))
+		ctx, source := domain.ContextWithReadSource(context.Background())
+		_, err := service.GetProduct(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DataSourceMongo, source.Source)
+		assert.False(t, source.Stale)
+		mysqlRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test store tidak tersedia dialihkan ke MySQL dan ditandai stale
+	t.Run("Store Unavailable", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("ListProducts", mock.Anything, mock.Anything).Return(nil, domain.ErrStoreUnavailable)
+		mysqlRepo.On("ListProducts", mock.Anything, mock.Anything).Return([]*domain.Product{mysqlProduct}, nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(testReadFallbackPolicy()))
+		ctx, source := domain.ContextWithReadSource(context.Background())
+		products, err := service.ListProducts(ctx, domain.ProductListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Product{mysqlProduct}, products)
+		assert.Equal(t, domain.DataSourceMySQL, source.Source)
+		assert.True(t, source.Stale)
+		assert.Contains(t, source.Reason, domain.ErrStoreUnavailable.Error())
+	})
+
+	// Test MongoDB yang melewati batas waktu dialihkan ke MySQL
+	t.Run("Primary Timeout", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, context.DeadlineExceeded).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		})
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(mysqlProduct, nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(testReadFallbackPolicy()))
+		ctx, source := domain.ContextWithReadSource(context.Background())
+		start := time.Now()
+		product, err := service.GetProduct(ctx, "1")
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, "From MySQL", product.Name)
+		assert.True(t, source.Stale)
+	})
+
+	// Test error klasifikasi tambahan dialihkan ke MySQL
+	t.Run("Classified Error", func(t *testing.T) {
+		errNetwork := errors.New("connection reset by peer")
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, errNetwork)
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(mysqlProduct, nil)
+
+		policy := testReadFallbackPolicy()
+		policy.Unavailable = func(err error) bool { return errors.Is(err, errNetwork) }
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(policy))
+		_, err := service.GetProduct(context.Background(), "1")
+		require.NoError(t, err)
+		mysqlRepo.AssertNumberOfCalls(t, "GetProduct", 1)
+	})
+
+	// Test error bisnis dan permission tidak dialihkan
+	t.Run("No Fallback", func(t *testing.T) {
+		errQuery := errors.New("invalid product id")
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, errQuery)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(testReadFallbackPolicy()))
+		_, err := service.GetProduct(context.Background(), "1")
+		assert.ErrorIs(t, err, errQuery)
+
+		denied := services.NewProductService(mongoRepo, mysqlRepo,
+			services.WithReadFallback(testReadFallbackPolicy()),
+			services.WithAuthorizer(services.NewPolicyAuthorizer(map[string][]string{"viewer": {}})),
+		)
+		viewer := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "u1", Roles: []string{"viewer"}})
+		_, err = denied.GetProduct(viewer, "1")
+		var permErr *domain.PermissionError
+		assert.ErrorAs(t, err, &permErr)
+		mysqlRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test fallback nonaktif mengembalikan error MongoDB
+	t.Run("Disabled", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, domain.ErrStoreUnavailable)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		_, err := service.GetProduct(context.Background(), "1")
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		mysqlRepo.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test MySQL yang juga gagal mengembalikan error MongoDB
+	t.Run("Secondary Fails", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, domain.ErrStoreUnavailable)
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(nil, errors.New("mysql down"))
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithReadFallback(testReadFallbackPolicy()))
+		ctx, source := domain.ContextWithReadSource(context.Background())
+		_, err := service.GetProduct(ctx, "1")
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		assert.Empty(t, source.Source)
+	})
+}
+
+// TestCachedReadFallback adalah fungsi untuk menguji cache terhadap response stale
+func TestCachedReadFallback(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+	staleProduct := &domain.Product{ID: "1", Name: "From MySQL"}
+	freshProduct := &domain.Product{ID: "1", Name: "From MongoDB"}
+	mockProductService.On("GetProduct", mock.Anything, "1").Return(staleProduct, nil).Once().Run(func(args mock.Arguments) {
+		domain.RecordReadSource(args.Get(0).(context.Context), domain.ReadSource{Source: domain.DataSourceMySQL, Stale: true})
+	})
+	mockProductService.On("GetProduct", mock.Anything, "1").Return(freshProduct, nil).Once().Run(func(args mock.Arguments) {
+		domain.RecordReadSource(args.Get(0).(context.Context), domain.ReadSource{Source: domain.DataSourceMongo})
+	})
+
+	// Test response stale diteruskan tapi tidak disimpan di cache
+	ctx, source := domain.ContextWithReadSource(context.Background())
+	product, err := service.GetProduct(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "From MySQL", product.Name)
+	assert.Equal(t, domain.ReadSource{Source: domain.DataSourceMySQL, Stale: true}, *source)
+
+	// Test pembacaan berikutnya kembali ke MongoDB lalu disimpan di cache
+	ctx, source = domain.ContextWithReadSource(context.Background())
+	product, err = service.GetProduct(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "From MongoDB", product.Name)
+	assert.Equal(t, domain.DataSourceMongo, source.Source)
+
+	ctx, source = domain.ContextWithReadSource(context.Background())
+	product, err = service.GetProduct(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "From MongoDB", product.Name)
+	assert.Equal(t, domain.DataSourceCache, source.Source)
+	mockProductService.AssertNumberOfCalls(t, "GetProduct", 2)
+}
+
+// TestReadSourceHeaders adalah fungsi untuk menguji header sumber data pada response produk
+func TestReadSourceHeaders(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+
+	app := fiber.New()
+	productHandler := handlers.NewProductHandler(mockProductService, handlers.WithCacheControl(map[string]string{
+		handlers.RouteGetProduct: "private, max-age=60",
+	}))
+	app.Get("/products/:id", productHandler.GetProduct)
+
+	recordSource := func(source domain.ReadSource) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			domain.RecordReadSource(args.Get(0).(context.Context), source)
+		}
+	}
+	mockProductService.On("GetProduct", mock.Anything, "fresh").Return(&domain.Product{ID: "fresh"}, nil).
+		Run(recordSource(domain.ReadSource{Source: domain.DataSourceMongo}))
+	mockProductService.On("GetProduct", mock.Anything, "stale").Return(&domain.Product{ID: "stale"}, nil).
+		Run(recordSource(domain.ReadSource{Source: domain.DataSourceMySQL, Stale: true}))
+
+	// Test response dari MongoDB memakai Cache-Control yang dikonfigurasi
+	t.Run("Fresh", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/products/fresh", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.DataSourceMongo, resp.Header.Get(handlers.HeaderDataSource))
+		assert.Empty(t, resp.Header.Get(handlers.HeaderDataStale))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderWarning))
+		assert.Equal(t, "private, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+
+	// Test response dari MySQL ditandai stale dan tidak boleh di-cache
+	t.Run("Stale", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/products/stale", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.DataSourceMySQL, resp.Header.Get(handlers.HeaderDataSource))
+		assert.Equal(t, "true", resp.Header.Get(handlers.HeaderDataStale))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderWarning), "110")
+		assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+}
+
+// TestReadFallbackConfig adalah fungsi untuk menguji konfigurasi fallback pembacaan
+func TestReadFallbackConfig(t *testing.T) {
+	// Test nilai default dikonversi ke batas waktu per endpoint
+	t.Run("Timeouts", func(t *testing.T) {
+		timeouts := config.Default().ReadFallbackTimeouts()
+		assert.Equal(t, 2*time.Second, timeouts["products.get"])
+		assert.Equal(t, 5*time.Second, timeouts["products.list"])
+	})
+
+	// Test endpoint dan durasi yang tidak valid ditolak
+	t.Run("Validation", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.ReadFallback = map[string]string{"products.delete": "1s", "products.get": "soon"}
+
+		err := cfg.Validate()
+		var validationErr *config.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		keys := make([]string, len(validationErr.Errors))
+		for i, fieldErr := range validationErr.Errors {
+			keys[i] = fieldErr.Key
+		}
+		assert.Equal(t, []string{"read_fallback.products.delete", "read_fallback.products.get"}, keys)
+	})
+}
@@ -0,0 +1,312 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/metrics"
+	"go-fiber-hexagonal-product/internal/adapters/resilience"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kebijakan resilience dengan jeda singkat untuk test
+func testResilienceOptions() resilience.Options {
+	return resilience.Options{
+		Breaker: resilience.BreakerOptions{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond, SuccessThreshold: 1},
+		Retry:   resilience.RetryOptions{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	}
+}
+
+// TestResiliencePolicy adalah fungsi untuk menguji circuit breaker, retry dan bulkhead repository
+func TestResiliencePolicy(t *testing.T) {
+	ctx := context.Background()
+	errTransient := &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+
+	// Test error sementara dicoba ulang sampai berhasil
+	t.Run("Retry Transient Error", func(t *testing.T) {
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(nil, errTransient).Twice()
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(&domain.Product{ID: "1"}, nil).Once()
+
+		policy := resilience.NewPolicy("mysql", testResilienceOptions(), resilience.IsMySQLTransient)
+		product, err := resilience.ProtectMySQLProductRepository(mysqlRepo, policy).GetProduct(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "1", product.ID)
+		mysqlRepo.AssertNumberOfCalls(t, "GetProduct", 3)
+		assert.Equal(t, uint64(2), policy.Stats().Retries)
+		assert.Equal(t, domain.CircuitClosed, policy.CircuitState())
+	})
+
+	// Test insert tidak dicoba ulang dan error bisnis tidak dicoba ulang
+	t.Run("No Retry", func(t *testing.T) {
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(errTransient)
+		mysqlRepo.On("DeleteProduct", mock.Anything, "1").Return(&mysql.MySQLError{Number: 1451, Message: "foreign key"})
+
+		policy := resilience.NewPolicy("mysql", testResilienceOptions(), resilience.IsMySQLTransient)
+		repo := resilience.ProtectMySQLProductRepository(mysqlRepo, policy)
+		assert.ErrorIs(t, repo.CreateProduct(ctx, &domain.Product{ID: "1"}), errTransient)
+		assert.Error(t, repo.DeleteProduct(ctx, "1"))
+		mysqlRepo.AssertNumberOfCalls(t, "CreateProduct", 1)
+		mysqlRepo.AssertNumberOfCalls(t, "DeleteProduct", 1)
+		assert.Zero(t, policy.Stats().Retries)
+	})
+
+	// Test circuit terbuka setelah kegagalan berturut-turut lalu pulih melalui half-open
+	t.Run("Circuit Breaker", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, mongo.CommandError{Code: 189, Message: "primary stepped down"}).Times(2)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(&domain.Product{ID: "1"}, nil)
+
+		opts := testResilienceOptions()
+		opts.Retry.MaxAttempts = 1
+		policy := resilience.NewPolicy("mongodb", opts, resilience.IsMongoTransient)
+		repo := resilience.ProtectMongoProductRepository(mongoRepo, policy)
+
+		for i := 0; i < 2; i++ {
+			_, err := repo.GetProduct(ctx, "1")
+			assert.Error(t, err)
+		}
+		assert.Equal(t, domain.CircuitOpen, policy.CircuitState())
+
+		// Circuit terbuka menolak panggilan tanpa menghubungi store
+		_, err := repo.GetProduct(ctx, "1")
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		mongoRepo.AssertNumberOfCalls(t, "GetProduct", 2)
+		assert.Equal(t, uint64(1), policy.Stats().ShortCircuits)
+
+		// Setelah open timeout satu panggilan percobaan menutup kembali circuit
+		assert.Eventually(t, func() bool { return policy.CircuitState() == domain.CircuitHalfOpen }, time.Second, 10*time.Millisecond)
+		product, err := repo.GetProduct(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "1", product.ID)
+		assert.Equal(t, domain.CircuitClosed, policy.CircuitState())
+	})
+
+	// Test panggilan percobaan yang dibatalkan pemanggil tidak menutup circuit
+	t.Run("Canceled Probe", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, mongo.CommandError{Code: 189, Message: "primary stepped down"}).Times(2)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, context.Canceled).Once()
+
+		opts := testResilienceOptions()
+		opts.Retry.MaxAttempts = 1
+		policy := resilience.NewPolicy("mongodb", opts, resilience.IsMongoTransient)
+		repo := resilience.ProtectMongoProductRepository(mongoRepo, policy)
+		for i := 0; i < 2; i++ {
+			_, err := repo.GetProduct(ctx, "1")
+			assert.Error(t, err)
+		}
+		assert.Eventually(t, func() bool { return policy.CircuitState() == domain.CircuitHalfOpen }, time.Second, 10*time.Millisecond)
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := repo.GetProduct(canceledCtx, "1")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, domain.CircuitHalfOpen, policy.CircuitState())
+
+		// Slot percobaan dilepas sehingga percobaan berikutnya diizinkan
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(&domain.Product{ID: "1"}, nil).Once()
+		_, err = repo.GetProduct(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.CircuitClosed, policy.CircuitState())
+	})
+
+	// Test error not found tidak membuka circuit
+	t.Run("Business Errors Do Not Trip", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments)
+
+		policy := resilience.NewPolicy("mongodb", testResilienceOptions(), resilience.IsMongoTransient)
+		repo := resilience.ProtectMongoProductRepository(mongoRepo, policy)
+		for i := 0; i < 5; i++ {
+			_, err := repo.GetProduct(ctx, "missing")
+			assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		}
+		mongoRepo.AssertNumberOfCalls(t, "GetProduct", 5)
+		assert.Equal(t, domain.CircuitClosed, policy.CircuitState())
+	})
+
+	// Test percobaan yang melewati batas waktu dihitung sebagai kegagalan
+	t.Run("Attempt Timeout", func(t *testing.T) {
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("ListProducts", mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		})
+
+		opts := testResilienceOptions()
+		opts.Retry.MaxAttempts = 1
+		opts.Timeout = 20 * time.Millisecond
+		policy := resilience.NewPolicy("mysql", opts, resilience.IsMySQLTransient)
+		repo := resilience.ProtectMySQLProductRepository(mysqlRepo, policy)
+		for i := 0; i < 2; i++ {
+			_, err := repo.ListProducts(ctx, domain.ProductListOptions{})
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+		assert.Equal(t, domain.CircuitOpen, policy.CircuitState())
+	})
+
+	// Test MongoDB yang melewati batas waktu read fallback membuka circuit
+	t.Run("Read Fallback Timeout", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "1").Return(nil, context.DeadlineExceeded).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		})
+		mysqlRepo.On("GetProduct", mock.Anything, "1").Return(&domain.Product{ID: "1"}, nil)
+
+		opts := testResilienceOptions()
+		opts.Retry.MaxAttempts = 1
+		policy := resilience.NewPolicy("mongodb", opts, resilience.IsMongoTransient)
+		service := services.NewProductService(resilience.ProtectMongoProductRepository(mongoRepo, policy), mysqlRepo,
+			services.WithReadFallback(services.ReadFallbackPolicy{Get: services.ReadFallback{Enabled: true, Timeout: 20 * time.Millisecond}}))
+		for i := 0; i < 2; i++ {
+			product, err := service.GetProduct(ctx, "1")
+			require.NoError(t, err)
+			assert.Equal(t, "1", product.ID)
+		}
+		assert.Equal(t, domain.CircuitOpen, policy.CircuitState())
+
+		// Pembatalan oleh pemanggil tidak dihitung sebagai kegagalan store
+		callerCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.False(t, domain.StoreTimedOut(callerCtx))
+	})
+
+	// Test bulkhead menolak panggilan saat semua slot terpakai
+	t.Run("Bulkhead", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{})
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("GetProduct", mock.Anything, "slow").Return(&domain.Product{ID: "slow"}, nil).Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		})
+
+		opts := testResilienceOptions()
+		opts.MaxConcurrent = 1
+		opts.MaxWait = 10 * time.Millisecond
+		policy := resilience.NewPolicy("mysql", opts, resilience.IsMySQLTransient)
+		repo := resilience.ProtectMySQLProductRepository(mysqlRepo, policy)
+
+		done := make(chan error)
+		go func() {
+			_, err := repo.GetProduct(ctx, "slow")
+			done <- err
+		}()
+		<-started
+		assert.Equal(t, 1, policy.Stats().InFlight)
+
+		_, err := repo.GetProduct(ctx, "other")
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		assert.Equal(t, uint64(1), policy.Stats().BulkheadRejections)
+
+		close(release)
+		assert.NoError(t, <-done)
+		assert.Equal(t, 0, policy.Stats().InFlight)
+		assert.Equal(t, domain.CircuitClosed, policy.CircuitState())
+	})
+}
+
+// TestTransientErrorClassification adalah fungsi untuk menguji klasifikasi error sementara per driver
+func TestTransientErrorClassification(t *testing.T) {
+	// Test error MongoDB
+	t.Run("MongoDB", func(t *testing.T) {
+		assert.True(t, resilience.IsMongoTransient(mongo.CommandError{Code: 10107, Message: "not primary"}))
+		assert.True(t, resilience.IsMongoTransient(mongo.CommandError{Code: 1, Labels: []string{"RetryableWriteError"}}))
+		assert.True(t, resilience.IsMongoTransient(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+		assert.False(t, resilience.IsMongoTransient(mongo.ErrNoDocuments))
+		assert.False(t, resilience.IsMongoTransient(mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}))
+		assert.False(t, resilience.IsMongoTransient(nil))
+	})
+
+	// Test error MySQL
+	t.Run("MySQL", func(t *testing.T) {
+		assert.True(t, resilience.IsMySQLTransient(&mysql.MySQLError{Number: 1205}))
+		assert.True(t, resilience.IsMySQLTransient(driver.ErrBadConn))
+		assert.True(t, resilience.IsMySQLTransient(mysql.ErrInvalidConn))
+		assert.False(t, resilience.IsMySQLTransient(&mysql.MySQLError{Number: 1062}))
+		assert.False(t, resilience.IsMySQLTransient(errors.New("product not found")))
+	})
+}
+
+// TestResilienceReporting adalah fungsi untuk menguji status circuit breaker pada health, metrics dan response HTTP
+func TestResilienceReporting(t *testing.T) {
+	ctx := context.Background()
+
+	// Membuat kebijakan dengan circuit yang sudah terbuka
+	openPolicy := func(t *testing.T) *resilience.Policy {
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mysqlRepo.On("GetProduct", mock.Anything, mock.Anything).Return(nil, driver.ErrBadConn)
+		opts := testResilienceOptions()
+		opts.Breaker.OpenTimeout = time.Minute
+		opts.Retry.MaxAttempts = 1
+		policy := resilience.NewPolicy("mysql", opts, resilience.IsMySQLTransient)
+		repo := resilience.ProtectMySQLProductRepository(mysqlRepo, policy)
+		for i := 0; i < 2; i++ {
+			_, _ = repo.GetProduct(ctx, "1")
+		}
+		require.Equal(t, domain.CircuitOpen, policy.CircuitState())
+		return policy
+	}
+
+	// Test circuit terbuka membuat dependency tidak tersedia pada readiness
+	t.Run("Health", func(t *testing.T) {
+		checker := new(mocks.MockHealthChecker)
+		checker.On("Ping", mock.Anything).Return(nil)
+		service := services.NewHealthService(time.Second, services.HealthDependency{
+			Name: "mysql", Checker: checker, Resilience: openPolicy(t),
+		})
+
+		report := service.Readiness(ctx)
+		assert.Equal(t, domain.HealthDegraded, report.Status)
+		assert.Equal(t, domain.DependencyDown, report.Dependencies["mysql"].Status)
+		assert.Equal(t, domain.CircuitOpen, report.Dependencies["mysql"].Circuit)
+		assert.Equal(t, "circuit breaker is open", report.Dependencies["mysql"].Error)
+	})
+
+	// Test status circuit dan penolakan tercatat di metrics
+	t.Run("Metrics", func(t *testing.T) {
+		m := metrics.New()
+		app := fiber.New()
+		app.Get("/metrics", m.Handler())
+		policy := openPolicy(t)
+		require.NoError(t, m.RegisterResilienceStats(policy))
+
+		body := scrapeMetrics(t, app)
+		assert.Contains(t, body, `goproduct_circuit_breaker_state{state="open",store="mysql"} 1`)
+		assert.Contains(t, body, `goproduct_circuit_breaker_state{state="closed",store="mysql"} 0`)
+		assert.Contains(t, body, `goproduct_repository_rejected_calls_total{reason="circuit_open",store="mysql"} 0`)
+		assert.Contains(t, body, `goproduct_repository_in_flight_calls{store="mysql"} 0`)
+	})
+
+	// Test pembacaan saat store tidak tersedia mengembalikan 503
+	t.Run("HTTP 503", func(t *testing.T) {
+		productService := new(mocks.MockProductService)
+		productService.On("GetProduct", mock.Anything, "1").Return(nil, domain.ErrStoreUnavailable)
+		productHandler := handlers.NewProductHandler(productService)
+		app := fiber.New()
+		app.Get("/api/products/:id", productHandler.GetProduct)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/products/1", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "5", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, handlers.MIMEApplicationProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+	})
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestProductServiceRevisions adalah fungsi untuk menguji penyimpanan snapshot revisi
+func TestProductServiceRevisions(t *testing.T) {
+	mockMongoRepo := new(mocks.MockMongoProductRepository)
+	mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+	mockRevisionRepo := new(mocks.MockRevisionRepository)
+	service := services.NewProductService(mockMongoRepo, mockMySQLRepo, services.WithRevisions(mockRevisionRepo))
+
+	// Test Update menyimpan snapshot lengkap
+	t.Run("Update", func(t *testing.T) {
+		existing := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10}
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(existing, nil).Once()
+		mockMongoRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockMySQLRepo.On("UpdateProduct", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockRevisionRepo.On("SaveRevision", mock.Anything, mock.MatchedBy(func(revision *domain.ProductRevision) bool {
+			return revision.ProductID == "123" && !revision.Deleted &&
+				revision.Product.Price == 1500 && revision.Actor == "alice" &&
+				revision.Timestamp.Equal(revision.Product.UpdatedAt)
+		})).Return(nil).Once()
+
+		ctx := domain.ContextWithActor(context.Background(), "alice")
+		err := service.UpdateProduct(ctx, &domain.Product{ID: "123", Name: "Test Product", Price: 1500, Stock: 10})
+
+		assert.NoError(t, err)
+	})
+
+	// Test Delete menyimpan revisi tombstone
+	t.Run("Delete", func(t *testing.T) {
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(&domain.Product{ID: "123"}, nil).Once()
+		mockMongoRepo.On("DeleteProduct", mock.Anything, "123").Return(nil).Once()
+		mockMySQLRepo.On("DeleteProduct", mock.Anything, "123").Return(nil).Once()
+		mockRevisionRepo.On("SaveRevision", mock.Anything, mock.MatchedBy(func(revision *domain.ProductRevision) bool {
+			return revision.ProductID == "123" && revision.Deleted
+		})).Return(nil).Once()
+
+		assert.NoError(t, service.DeleteProduct(context.Background(), "123"))
+	})
+
+	// Test Restore mengosongkan identifier yang kosong pada snapshot
+	t.Run("Restore Clears Identifiers", func(t *testing.T) {
+		existing := &domain.Product{ID: "123", Name: "Test Product", SKU: "SKU-1", Slug: "test-product", Price: 1000, Stock: 10}
+		restored := func(product *domain.Product) bool {
+			return product.Name == "Old Product" && product.SKU == "" && product.Slug == ""
+		}
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(existing, nil).Once()
+		mockMongoRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(restored)).Return(nil).Once()
+		mockMySQLRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(restored)).Return(nil).Once()
+		mockRevisionRepo.On("SaveRevision", mock.Anything, mock.MatchedBy(func(revision *domain.ProductRevision) bool {
+			return revision.ProductID == "123" && restored(&revision.Product)
+		})).Return(nil).Once()
+
+		err := service.RestoreProduct(context.Background(), &domain.Product{ID: "123", Name: "Old Product", Price: 900, Stock: 5})
+
+		assert.NoError(t, err)
+	})
+
+	// Test Restore membuat ulang produk yang sudah dihapus dengan ID yang sama
+	t.Run("Restore Deleted", func(t *testing.T) {
+		createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		restored := func(product *domain.Product) bool {
+			return product.ID == "123" && product.Name == "Old Product" && product.CreatedAt.Equal(createdAt)
+		}
+		mockMongoRepo.On("GetProduct", mock.Anything, "123").Return(nil, domain.ErrProductNotFound).Once()
+		mockMongoRepo.On("CreateProduct", mock.Anything, mock.MatchedBy(restored)).Return("123", nil).Once()
+		mockMySQLRepo.On("CreateProduct", mock.Anything, mock.MatchedBy(restored)).Return(nil).Once()
+		mockRevisionRepo.On("SaveRevision", mock.Anything, mock.MatchedBy(func(revision *domain.ProductRevision) bool {
+			return !revision.Deleted && restored(&revision.Product)
+		})).Return(nil).Once()
+
+		err := service.RestoreProduct(context.Background(), &domain.Product{ID: "123", Name: "Old Product", Price: 900, Stock: 5, CreatedAt: createdAt})
+
+		assert.NoError(t, err)
+		mockMongoRepo.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.MatchedBy(restored))
+	})
+
+	mockMongoRepo.AssertExpectations(t)
+	mockMySQLRepo.AssertExpectations(t)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+// TestRevisionService adalah fungsi untuk menguji as_of dan restore
+func TestRevisionService(t *testing.T) {
+	asOf := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := &domain.ProductRevision{
+		ProductID: "123",
+		Revision:  2,
+		Product:   domain.Product{ID: "123", Name: "Old Product", Price: 900, Stock: 5},
+		Timestamp: asOf.Add(-time.Hour),
+	}
+
+	// Test As Of
+	t.Run("As Of", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.MockRevisionRepository)
+		service := services.NewRevisionService(new(mocks.MockProductService), mockRevisionRepo, nil)
+		mockRevisionRepo.On("GetRevisionAsOf", mock.Anything, "123", asOf).Return(snapshot, nil).Once()
+
+		product, err := service.GetProductAsOf(context.Background(), "123", asOf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Old Product", product.Name)
+	})
+
+	// Test As Of setelah produk dihapus
+	t.Run("As Of Deleted", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.MockRevisionRepository)
+		service := services.NewRevisionService(new(mocks.MockProductService), mockRevisionRepo, nil)
+		mockRevisionRepo.On("GetRevisionAsOf", mock.Anything, "123", asOf).Return(&domain.ProductRevision{ProductID: "123", Revision: 3, Deleted: true}, nil).Once()
+
+		_, err := service.GetProductAsOf(context.Background(), "123", asOf)
+
+		assert.ErrorIs(t, err, domain.ErrRevisionNotFound)
+	})
+
+	// Test Restore menulis ulang snapshot melalui ProductService
+	t.Run("Restore", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.MockRevisionRepository)
+		mockProductService := new(mocks.MockProductService)
+		service := services.NewRevisionService(mockProductService, mockRevisionRepo, nil)
+		mockRevisionRepo.On("GetRevision", mock.Anything, "123", 2).Return(snapshot, nil).Once()
+		mockProductService.On("RestoreProduct", mock.Anything, &domain.Product{ID: "123", Name: "Old Product", Price: 900, Stock: 5}).Return(nil).Once()
+
+		product, err := service.RestoreRevision(context.Background(), "123", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 900, product.Price)
+		mockProductService.AssertExpectations(t)
+	})
+}
+
+// TestRevisionHandlers adalah fungsi untuk menguji endpoint revisi dan as_of
+func TestRevisionHandlers(t *testing.T) {
+	mockRevisionRepo := new(mocks.MockRevisionRepository)
+	mockProductService := new(mocks.MockProductService)
+	revisionService := services.NewRevisionService(mockProductService, mockRevisionRepo, nil)
+
+	app := fiber.New()
+	productHandler := handlers.NewProductHandler(mockProductService, handlers.WithRevisionService(revisionService))
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
+	app.Get("/products/:id", productHandler.GetProduct)
+	app.Get("/products/:id/revisions", revisionHandler.ListRevisions)
+	app.Post("/products/:id/revisions/:rev/restore", revisionHandler.RestoreRevision)
+
+	asOf := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Test GetProduct dengan as_of
+	t.Run("As Of", func(t *testing.T) {
+		mockRevisionRepo.On("GetRevisionAsOf", mock.Anything, "123", asOf).Return(&domain.ProductRevision{
+			ProductID: "123",
+			Revision:  1,
+			Product:   domain.Product{ID: "123", Name: "Old Product"},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/products/123?as_of=2024-05-01T12:00:00Z", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result domain.Product
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "Old Product", result.Name)
+	})
+
+	// Test as_of sebelum produk dibuat
+	t.Run("As Of Not Found", func(t *testing.T) {
+		mockRevisionRepo.On("GetRevisionAsOf", mock.Anything, "123", asOf).Return(nil, domain.ErrRevisionNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/products/123?as_of=2024-05-01T12:00:00Z", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	// Test as_of tidak valid
+	t.Run("Invalid As Of", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/123?as_of=yesterday", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	// Test List Revisions
+	t.Run("List Revisions", func(t *testing.T) {
+		mockRevisionRepo.On("ListRevisions", mock.Anything, "123").Return([]*domain.ProductRevision{{ProductID: "123", Revision: 1}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/products/123/revisions", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	// Test Restore revisi yang tidak ada
+	t.Run("Restore Not Found", func(t *testing.T) {
+		mockRevisionRepo.On("GetRevision", mock.Anything, "123", 9).Return(nil, domain.ErrRevisionNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/products/123/revisions/9/restore", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	// Test Restore dengan nomor revisi tidak valid
+	t.Run("Restore Invalid Revision", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/products/123/revisions/abc/restore", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	mockRevisionRepo.AssertExpectations(t)
+}
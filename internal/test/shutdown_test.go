@@ -0,0 +1,115 @@
+package test
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/app"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mencari port TCP lokal yang sedang tidak dipakai
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// Menunggu server menerima koneksi
+func waitForServer(t *testing.T, address string) {
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestGracefulShutdown adalah fungsi untuk menguji urutan shutdown App
+func TestGracefulShutdown(t *testing.T) {
+	cfg := config.Default()
+	cfg.ServerAddress = freeAddress(t)
+	cfg.ShutdownDelay = 200 * time.Millisecond
+	cfg.ShutdownTimeout = 5 * time.Second
+
+	// Request daftar produk yang lambat agar masih berjalan saat sinyal diterima
+	listStarted := make(chan struct{})
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	mockMongoRepo := new(mocks.MockMongoProductRepository)
+	mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+	mockMongoRepo.On("ListProducts", mock.Anything, mock.Anything).Return([]*domain.Product{}, nil).Run(func(mock.Arguments) {
+		close(listStarted)
+		time.Sleep(400 * time.Millisecond)
+		record("request handled")
+	})
+
+	application := app.NewApp(cfg, mockMongoRepo, mockMySQLRepo,
+		app.WithBackgroundWorker("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			record("worker stopped")
+			return ctx.Err()
+		}),
+		app.WithCloser("mysql", func(context.Context) error { record("mysql closed"); return nil }),
+		app.WithCloser("mongodb", func(context.Context) error { record("mongodb closed"); return nil }),
+	)
+
+	ctx, stop := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- application.Run(ctx) }()
+	waitForServer(t, cfg.ServerAddress)
+
+	// Request yang sedang berjalan tetap diselesaikan
+	listStatus := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + cfg.ServerAddress + "/api/products")
+		if err != nil {
+			listStatus <- 0
+			return
+		}
+		resp.Body.Close()
+		listStatus <- resp.StatusCode
+	}()
+	<-listStarted
+	stop()
+
+	// Selama jeda shutdown /readyz sudah tidak siap
+	time.Sleep(50 * time.Millisecond)
+	resp, err := http.Get("http://" + cfg.ServerAddress + "/readyz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Equal(t, http.StatusOK, <-listStatus)
+	select {
+	case err := <-runErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after shutdown")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"request handled", "worker stopped", "mysql closed", "mongodb closed"}, events)
+
+	// Server tidak lagi menerima koneksi
+	_, err = net.Dial("tcp", cfg.ServerAddress)
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/repositories"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TestChangeStreamApplyChange adalah fungsi untuk menguji penerapan event change stream ke MySQL
+func TestChangeStreamApplyChange(t *testing.T) {
+	// Buat mock repository MySQL
+	mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+
+	// Collection dan token store tidak dipakai oleh ApplyChange
+	syncer := repositories.NewMongoChangeStreamSyncer(nil, mockMySQLRepo, nil, "test")
+
+	// Test Insert
+	t.Run("Insert", func(t *testing.T) {
+		product := &domain.Product{ID: "123", Name: "Test Product", Price: 1000, Stock: 10}
+		mockMySQLRepo.On("SaveProduct", mock.Anything, product).Return(nil).Once()
+
+		err := syncer.ApplyChange(context.Background(), repositories.ProductChange{
+			Operation: repositories.ChangeOperationInsert,
+			ProductID: "123",
+			Product:   product,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	// Test Update tanpa ID di dokumen, ID diambil dari documentKey
+	t.Run("Update", func(t *testing.T) {
+		product := &domain.Product{Name: "Updated Product", Price: 1500, Stock: 15}
+		mockMySQLRepo.On("SaveProduct", mock.Anything, &domain.Product{ID: "123", Name: "Updated Product", Price: 1500, Stock: 15}).Return(nil).Once()
+
+		err := syncer.ApplyChange(context.Background(), repositories.ProductChange{
+			Operation: repositories.ChangeOperationUpdate,
+			ProductID: "123",
+			Product:   product,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	// Test Update untuk dokumen yang sudah terhapus dilewati
+	t.Run("Update Without Document", func(t *testing.T) {
+		err := syncer.ApplyChange(context.Background(), repositories.ProductChange{
+			Operation: repositories.ChangeOperationUpdate,
+			ProductID: "456",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	// Test Delete
+	t.Run("Delete", func(t *testing.T) {
+		mockMySQLRepo.On("DeleteProduct", mock.Anything, "123").Return(nil).Once()
+
+		err := syncer.ApplyChange(context.Background(), repositories.ProductChange{
+			Operation: repositories.ChangeOperationDelete,
+			ProductID: "123",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	// Test error MySQL diteruskan agar event diproses ulang
+	t.Run("Error", func(t *testing.T) {
+		mockMySQLRepo.On("DeleteProduct", mock.Anything, "789").Return(errors.New("connection refused")).Once()
+
+		err := syncer.ApplyChange(context.Background(), repositories.ProductChange{
+			Operation: repositories.ChangeOperationDelete,
+			ProductID: "789",
+		})
+
+		assert.Error(t, err)
+	})
+
+	// Periksa apakah mock repository telah dipanggil
+	mockMySQLRepo.AssertExpectations(t)
+}
+
+// TestChangeStreamResync adalah fungsi untuk menguji sinkronisasi ulang setelah resume token hilang
+func TestChangeStreamResync(t *testing.T) {
+	// Test produk MongoDB disimpan dan produk yang sudah dihapus dari MongoDB ikut dihapus di MySQL
+	t.Run("Reconcile Products", func(t *testing.T) {
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		syncer := repositories.NewMongoChangeStreamSyncer(nil, mockMySQLRepo, nil, "test")
+
+		products := []*domain.Product{
+			{ID: "1", TenantID: "acme", Name: "Kept Product"},
+			{ID: "2", TenantID: "globex", Name: "New Product"},
+		}
+		allTenants := mock.MatchedBy(domain.AllTenantsFromContext)
+		mockMySQLRepo.On("SaveProduct", allTenants, products[0]).Return(nil).Once()
+		mockMySQLRepo.On("SaveProduct", allTenants, products[1]).Return(nil).Once()
+		mockMySQLRepo.On("ListProducts", allTenants, domain.ProductListOptions{}).Return([]*domain.Product{
+			{ID: "1", TenantID: "acme"},
+			{ID: "3", TenantID: "acme"},
+		}, nil).Once()
+		mockMySQLRepo.On("DeleteProduct", allTenants, "3").Return(nil).Once()
+
+		err := syncer.ReconcileProducts(context.Background(), products)
+
+		assert.NoError(t, err)
+		mockMySQLRepo.AssertExpectations(t)
+	})
+
+	// Test error MySQL menghentikan sinkronisasi ulang agar dicoba lagi
+	t.Run("Save Error", func(t *testing.T) {
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		syncer := repositories.NewMongoChangeStreamSyncer(nil, mockMySQLRepo, nil, "test")
+
+		mockMySQLRepo.On("SaveProduct", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+
+		err := syncer.ReconcileProducts(context.Background(), []*domain.Product{{ID: "1"}})
+
+		assert.Error(t, err)
+		mockMySQLRepo.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
+	})
+
+	// Test error resume token yang sudah keluar dari oplog dikenali
+	t.Run("History Lost", func(t *testing.T) {
+		assert.True(t, repositories.IsChangeStreamHistoryLost(mongo.CommandError{Code: 286, Name: "ChangeStreamHistoryLost"}))
+		assert.True(t, repositories.IsChangeStreamHistoryLost(mongo.CommandError{Code: 260, Name: "InvalidResumeToken"}))
+		assert.False(t, repositories.IsChangeStreamHistoryLost(mongo.CommandError{Code: 11600, Name: "InterruptedAtShutdown"}))
+		assert.False(t, repositories.IsChangeStreamHistoryLost(errors.New("connection refused")))
+	})
+}
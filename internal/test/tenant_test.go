@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"go-fiber-hexagonal-product/internal/adapters/cache"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTenantMiddleware adalah fungsi untuk menguji resolusi tenant per request
+func TestTenantMiddleware(t *testing.T) {
+	newApp := func(principal *domain.Principal) *fiber.App {
+		app := fiber.New()
+		if principal != nil {
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(domain.ContextWithPrincipal(c.UserContext(), principal))
+				return c.Next()
+			})
+		}
+		app.Use(handlers.TenantMiddleware(handlers.TenantResolverConfig{
+			BaseDomain: "shop.example.com",
+			Tenants:    []string{"acme", "globex"},
+		}))
+		app.Get("/tenant", func(c *fiber.Ctx) error {
+			return c.SendString(domain.TenantFromContext(c.UserContext()))
+		})
+		return app
+	}
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		host      string
+		header    string
+		status    int
+		tenant    string
+	}{
+		{name: "default", status: fiber.StatusOK, tenant: domain.DefaultTenant},
+		{name: "header", header: "acme", status: fiber.StatusOK, tenant: "acme"},
+		{name: "subdomain", host: "globex.shop.example.com:8080", status: fiber.StatusOK, tenant: "globex"},
+		{name: "header before subdomain", host: "globex.shop.example.com", header: "acme", status: fiber.StatusOK, tenant: "acme"},
+		{name: "token claim", principal: &domain.Principal{Subject: "alice", TenantID: "acme"}, status: fiber.StatusOK, tenant: "acme"},
+		{name: "token claim mismatch", principal: &domain.Principal{Subject: "alice", TenantID: "acme"}, header: "globex", status: fiber.StatusForbidden},
+		{name: "principal without claim", principal: &domain.Principal{Subject: "ops"}, status: fiber.StatusOK, tenant: domain.DefaultTenant},
+		{name: "principal without claim header", principal: &domain.Principal{Subject: "ops"}, header: "globex", status: fiber.StatusForbidden},
+		{name: "principal without claim subdomain", principal: &domain.Principal{Subject: "ops"}, host: "globex.shop.example.com", status: fiber.StatusForbidden},
+		{name: "cross tenant scope", principal: &domain.Principal{Subject: "ops", Scopes: []string{handlers.ScopeCrossTenant}}, header: "globex", status: fiber.StatusOK, tenant: "globex"},
+		{name: "invalid tenant", header: "../acme", status: fiber.StatusBadRequest},
+		{name: "unknown tenant", header: "initech", status: fiber.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set(handlers.HeaderTenantID, tt.header)
+			}
+			resp, err := newApp(tt.principal).Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == fiber.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.tenant, string(body))
+			} else {
+				assert.Equal(t, handlers.MIMEApplicationProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+			}
+		})
+	}
+}
+
+// TestCachedProductServiceTenantIsolation adalah fungsi untuk menguji pemisahan cache per tenant
+func TestCachedProductServiceTenantIsolation(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	service := services.NewCachedProductService(mockProductService, cache.NewMemoryCache(10), time.Minute, nil)
+
+	acme := domain.ContextWithTenant(context.Background(), "acme")
+	globex := domain.ContextWithTenant(context.Background(), "globex")
+	acmeProduct := &domain.Product{ID: "123", Name: "Acme Product"}
+
+	// Cache menambahkan pencatat sumber data ke context, sehingga yang dicocokkan tenant-nya
+	forTenant := func(tenant string) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool { return domain.TenantFromContext(ctx) == tenant })
+	}
+
+	// Produk yang sama tidak boleh dibaca tenant lain dari cache
+	mockProductService.On("GetProduct", forTenant("acme"), "123").Return(acmeProduct, nil).Once()
+	mockProductService.On("GetProduct", forTenant("globex"), "123").Return(nil, nil).Once()
+
+	product, err := service.GetProduct(acme, "123")
+	require.NoError(t, err)
+	assert.Equal(t, acmeProduct, product)
+
+	product, err = service.GetProduct(globex, "123")
+	require.NoError(t, err)
+	assert.Nil(t, product)
+
+	// Penulisan tenant lain tidak menghapus cache tenant ini
+	mockProductService.On("DeleteProduct", globex, "123").Return(nil).Once()
+	require.NoError(t, service.DeleteProduct(globex, "123"))
+
+	product, err = service.GetProduct(acme, "123")
+	require.NoError(t, err)
+	assert.Equal(t, acmeProduct.Name, product.Name)
+	mockProductService.AssertExpectations(t)
+}
+
+// TestTenantCacheControl adalah fungsi untuk menguji override Cache-Control per tenant
+func TestTenantCacheControl(t *testing.T) {
+	mockProductService := new(mocks.MockProductService)
+	productHandler := handlers.NewProductHandler(mockProductService,
+		handlers.WithCacheControl(map[string]string{handlers.RouteGetProduct: "private, no-cache"}),
+		handlers.WithTenantCacheControl(map[string]map[string]string{
+			"acme": {handlers.RouteGetProduct: "public, max-age=60"},
+		}),
+	)
+	app := fiber.New()
+	app.Use(handlers.TenantMiddleware(handlers.TenantResolverConfig{}))
+	app.Get("/products/:id", productHandler.GetProduct)
+
+	mockProductService.On("GetProduct", mock.Anything, "123").Return(&domain.Product{ID: "123", Name: "Test Product"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/123", nil)
+	req.Header.Set(handlers.HeaderTenantID, "acme")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+
+	req = httptest.NewRequest(http.MethodGet, "/products/123", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "private, no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+}
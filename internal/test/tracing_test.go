@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/adapters/handlers"
+	"go-fiber-hexagonal-product/internal/adapters/tracing"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace ID dari header traceparent yang dikirim client pada test
+const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+// Memasang tracer provider dengan span recorder selama test berjalan
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+// Mencari span yang sudah selesai berdasarkan nama
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "span %q", name)
+	return nil
+}
+
+// TestTracing adalah fungsi untuk menguji span HTTP, service dan repository
+func TestTracing(t *testing.T) {
+	// Test span request memakai traceparent client dan menjadi parent span service dan database
+	t.Run("Dual Write Span Tree", func(t *testing.T) {
+		recorder := setupSpanRecorder(t)
+
+		mockMongoRepo := new(mocks.MockMongoProductRepository)
+		mockMySQLRepo := new(mocks.MockMySQLProductRepository)
+		slugAvailable(mockMongoRepo)
+		mockMongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("507f1f77bcf86cd799439011", nil)
+		mockMySQLRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(errors.New("mysql unavailable"))
+
+		service := tracing.InstrumentProductService(services.NewProductService(
+			tracing.InstrumentMongoProductRepository(mockMongoRepo),
+			tracing.InstrumentMySQLProductRepository(mockMySQLRepo),
+		))
+		app := fiber.New()
+		app.Use(tracing.Middleware())
+		app.Post("/api/products", handlers.NewProductHandler(service).CreateProduct)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/products", strings.NewReader(`{"name":"Laptop","price":1000,"stock":5}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		server := findSpan(t, spans, "POST /api/products")
+		serviceSpan := findSpan(t, spans, "ProductService.CreateProduct")
+		mongoSpan := findSpan(t, spans, "CreateProduct products")
+		mysqlSpan := findSpan(t, spans, "CreateProduct product")
+
+		assert.Equal(t, incomingTraceID, server.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+		assert.Equal(t, server.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+		assert.Equal(t, serviceSpan.SpanContext().SpanID(), mongoSpan.Parent().SpanID())
+		assert.Equal(t, serviceSpan.SpanContext().SpanID(), mysqlSpan.Parent().SpanID())
+		assert.Equal(t, codes.Unset, mongoSpan.Status().Code)
+		assert.Equal(t, codes.Error, mysqlSpan.Status().Code)
+		// Produk sudah tersimpan di MongoDB sehingga create tidak dilaporkan gagal
+		assert.Equal(t, codes.Unset, serviceSpan.Status().Code)
+	})
+
+	// Test request yang tidak cocok dengan route tidak memakai route middleware sebagai nama span
+	t.Run("Unmatched Route", func(t *testing.T) {
+		recorder := setupSpanRecorder(t)
+		app := fiber.New()
+		app.Use(tracing.Middleware())
+
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		require.NoError(t, err)
+		require.Len(t, recorder.Ended(), 1)
+		assert.Equal(t, "HTTP GET", recorder.Ended()[0].Name())
+	})
+
+	// Test header traceparent dikirim pada request keluar
+	t.Run("Outgoing Propagation", func(t *testing.T) {
+		recorder := setupSpanRecorder(t)
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Get("traceparent")
+		}))
+		defer server.Close()
+
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: tracing.Transport(nil)}).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		parent.End()
+
+		client := findSpan(t, recorder.Ended(), "HTTP GET")
+		assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+		assert.Equal(t, "00-"+parent.SpanContext().TraceID().String()+"-"+client.SpanContext().SpanID().String()+"-01", received)
+	})
+
+	// Test exporter stdout menulis span ke file
+	t.Run("Stdout Exporter To File", func(t *testing.T) {
+		previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+		t.Cleanup(func() {
+			otel.SetTracerProvider(previousProvider)
+			otel.SetTextMapPropagator(previousPropagator)
+		})
+
+		file := filepath.Join(t.TempDir(), "spans.json")
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+			Exporter:    tracing.ExporterStdout,
+			ServiceName: "test-service",
+			File:        file,
+			SampleRatio: 1,
+		})
+		require.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(domain.ContextWithTenant(context.Background(), "acme"), "offline-span")
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "offline-span")
+		assert.Contains(t, string(data), "test-service")
+
+		_, err = tracing.Setup(context.Background(), tracing.Options{Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
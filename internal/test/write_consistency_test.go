@@ -0,0 +1,293 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-hexagonal-product/internal/core/domain"
+	"go-fiber-hexagonal-product/internal/core/services"
+	"go-fiber-hexagonal-product/internal/test/mocks"
+	"go-fiber-hexagonal-product/pkg/config"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Pembuat ID tetap untuk test
+type fixedIDGenerator string
+
+func (g fixedIDGenerator) NewID() string {
+	return string(g)
+}
+
+// Mencocokkan produk dengan ID tertentu
+func productWithID(id string) interface{} {
+	return mock.MatchedBy(func(product *domain.Product) bool { return product.ID == id })
+}
+
+// Membuat ProductService dengan tingkat konsistensi dan ID tetap, semua slug belum dipakai
+func newWriteService(mongoRepo *mocks.MockMongoProductRepository, mysqlRepo *mocks.MockMySQLProductRepository, consistency services.WriteConsistency, opts ...services.ProductServiceOption) *services.ProductService {
+	slugAvailable(mongoRepo)
+	opts = append([]services.ProductServiceOption{
+		services.WithWritePolicy(services.WritePolicy{Consistency: consistency, Timeout: time.Second}),
+		services.WithIDGenerator(fixedIDGenerator("abc")),
+	}, opts...)
+	return services.NewProductService(mongoRepo, mysqlRepo, opts...)
+}
+
+// TestWriteConsistency adalah fungsi untuk menguji penulisan produk sesuai tingkat konsistensi
+func TestWriteConsistency(t *testing.T) {
+	ctx := context.Background()
+	errWrite := errors.New("write failed")
+
+	// Test mode all menulis MongoDB dan MySQL bersamaan dengan ID yang sama
+	t.Run("All Parallel", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+
+		// Masing-masing store menunggu store lain mulai, hanya selesai jika ditulis bersamaan
+		var arrived atomic.Int32
+		both := make(chan struct{})
+		barrier := func(mock.Arguments) {
+			if arrived.Add(1) == 2 {
+				close(both)
+			}
+			select {
+			case <-both:
+			case <-time.After(time.Second):
+			}
+		}
+		mongoRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return("abc", nil).Run(barrier)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return(nil).Run(barrier)
+
+		product := &domain.Product{ID: "client-id", Name: "Test Product"}
+		start := time.Now()
+		require.NoError(t, newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll).CreateProduct(ctx, product))
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, "abc", product.ID)
+		mongoRepo.AssertExpectations(t)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test mode all gagal jika salah satu store gagal dan pembuatan yang berhasil dibatalkan
+	t.Run("All Failure", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		auditRepo := new(mocks.MockAuditRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("abc", nil)
+		mongoRepo.On("DeleteProduct", mock.Anything, "abc").Return(nil).Once()
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(errWrite)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithAuditLog(auditRepo))
+		err := service.CreateProduct(ctx, &domain.Product{Name: "Test Product"})
+		assert.ErrorIs(t, err, errWrite)
+		assert.Contains(t, err.Error(), "mysql")
+		mongoRepo.AssertExpectations(t)
+		auditRepo.AssertNotCalled(t, "AppendAudit", mock.Anything, mock.Anything)
+	})
+
+	// Test mode all tetap mencatat update jika MongoDB sudah berubah
+	t.Run("All Update Partial", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		auditRepo := new(mocks.MockAuditRepository)
+		mongoRepo.On("GetProduct", mock.Anything, "abc").Return(&domain.Product{ID: "abc", Name: "Old"}, nil)
+		mongoRepo.On("UpdateProduct", mock.Anything, mock.Anything).Return(nil)
+		mysqlRepo.On("UpdateProduct", mock.Anything, mock.Anything).Return(errWrite)
+		auditRepo.On("AppendAudit", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyAll, services.WithAuditLog(auditRepo))
+		err := service.UpdateProduct(ctx, &domain.Product{ID: "abc", Name: "New"})
+		assert.ErrorIs(t, err, errWrite)
+		auditRepo.AssertExpectations(t)
+	})
+
+	// Test mode primary ack setelah MongoDB, MySQL diselesaikan di background
+	t.Run("Primary", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		release := make(chan struct{})
+		var replicated atomic.Bool
+		mongoRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("abc")).Return(nil).Run(func(mock.Arguments) {
+			<-release
+			replicated.Store(true)
+		})
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyPrimary)
+		require.NoError(t, service.CreateProduct(ctx, &domain.Product{Name: "Test Product"}))
+		assert.False(t, replicated.Load())
+
+		// Drain menunggu penulisan background sampai batas waktu
+		drainCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, service.Drain(drainCtx), context.DeadlineExceeded)
+
+		close(release)
+		require.NoError(t, service.Drain(ctx))
+		assert.True(t, replicated.Load())
+	})
+
+	// Test penulisan baru ditolak setelah Drain, walaupun Drain berhenti karena batas waktu
+	t.Run("Drain Rejects Writes", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		release := make(chan struct{})
+		mongoRepo.On("DeleteProduct", mock.Anything, "abc").Return(nil)
+		mysqlRepo.On("DeleteProduct", mock.Anything, "abc").Return(nil).Run(func(mock.Arguments) { <-release })
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyPrimary)
+		require.NoError(t, service.DeleteProduct(ctx, "abc"))
+
+		drainCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, service.Drain(drainCtx), context.DeadlineExceeded)
+
+		assert.ErrorIs(t, service.DeleteProduct(ctx, "abc"), domain.ErrStoreUnavailable)
+		mongoRepo.AssertNumberOfCalls(t, "DeleteProduct", 1)
+
+		close(release)
+		require.NoError(t, service.Drain(ctx))
+	})
+
+	// Test kebijakan dinamis memakai tingkat konsistensi terbaru setiap penulisan
+	t.Run("Dynamic Policy", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("DeleteProduct", mock.Anything, "abc").Return(nil)
+		mysqlRepo.On("DeleteProduct", mock.Anything, "abc").Return(errWrite)
+
+		consistency := services.WriteConsistencyAll
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithDynamicWritePolicy(func() services.WritePolicy {
+			return services.WritePolicy{Consistency: consistency}
+		}))
+		assert.ErrorIs(t, service.DeleteProduct(ctx, "abc"), errWrite)
+
+		consistency = services.WriteConsistencyPrimary
+		assert.NoError(t, service.DeleteProduct(ctx, "abc"))
+		require.NoError(t, service.Drain(ctx))
+	})
+
+	// Test mode primary gagal tanpa menulis store lain jika MongoDB gagal
+	t.Run("Primary Failure", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("DeleteProduct", mock.Anything, "abc").Return(errWrite)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyPrimary)
+		assert.ErrorIs(t, service.DeleteProduct(ctx, "abc"), errWrite)
+		require.NoError(t, service.Drain(ctx))
+		mysqlRepo.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test mode quorum ack setelah mayoritas dari tiga store berhasil
+	t.Run("Quorum", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		replica := new(mocks.MockMySQLProductRepository)
+		release := make(chan struct{})
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("abc", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(nil)
+		replica.On("CreateProduct", mock.Anything, mock.Anything).Return(errWrite).Run(func(mock.Arguments) { <-release })
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyQuorum, services.WithReplica("search", replica))
+		require.NoError(t, service.CreateProduct(ctx, &domain.Product{Name: "Test Product"}))
+
+		close(release)
+		require.NoError(t, service.Drain(ctx))
+		replica.AssertNumberOfCalls(t, "CreateProduct", 1)
+		mongoRepo.AssertNotCalled(t, "DeleteProduct", mock.Anything, mock.Anything)
+	})
+
+	// Test mode quorum gagal jika mayoritas store gagal
+	t.Run("Quorum Failure", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		replica := new(mocks.MockMySQLProductRepository)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("abc", nil)
+		mongoRepo.On("DeleteProduct", mock.Anything, "abc").Return(nil).Once()
+		mysqlRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(errWrite)
+		replica.On("CreateProduct", mock.Anything, mock.Anything).Return(errWrite)
+
+		service := newWriteService(mongoRepo, mysqlRepo, services.WriteConsistencyQuorum, services.WithReplica("search", replica))
+		err := service.CreateProduct(ctx, &domain.Product{Name: "Test Product"})
+		assert.ErrorIs(t, err, errWrite)
+		assert.Contains(t, err.Error(), "search")
+		mongoRepo.AssertExpectations(t)
+	})
+
+	// Test tanpa pembuat ID, MySQL ditulis setelah MongoDB dengan ID dari MongoDB
+	t.Run("ID From MongoDB", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		slugAvailable(mongoRepo)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("from-mongo", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("from-mongo")).Return(nil)
+
+		service := services.NewProductService(mongoRepo, mysqlRepo)
+		product := &domain.Product{Name: "Test Product"}
+		require.NoError(t, service.CreateProduct(ctx, product))
+		assert.Equal(t, "from-mongo", product.ID)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test ID dari MongoDB tetap memakai batas waktu penulisan dan tidak ikut dibatalkan bersama request
+	t.Run("ID From MongoDB Write Context", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		slugAvailable(mongoRepo)
+		writeContext := mock.MatchedBy(func(ctx context.Context) bool {
+			_, hasDeadline := ctx.Deadline()
+			return hasDeadline && ctx.Err() == nil
+		})
+		mongoRepo.On("CreateProduct", writeContext, mock.Anything).Return("from-mongo", nil).Once()
+		mysqlRepo.On("CreateProduct", writeContext, productWithID("from-mongo")).Return(nil).Once()
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithWritePolicy(services.WritePolicy{Timeout: time.Second}))
+		requestCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, service.CreateProduct(requestCtx, &domain.Product{Name: "Test Product"}))
+		mongoRepo.AssertExpectations(t)
+		mysqlRepo.AssertExpectations(t)
+	})
+
+	// Test create yang sudah tersimpan di MongoDB tidak dilaporkan gagal walaupun MySQL
+	// dan audit log gagal, dan perubahan ditandai tersimpan
+	t.Run("Committed Create", func(t *testing.T) {
+		mongoRepo := new(mocks.MockMongoProductRepository)
+		mysqlRepo := new(mocks.MockMySQLProductRepository)
+		auditRepo := new(mocks.MockAuditRepository)
+		slugAvailable(mongoRepo)
+		mongoRepo.On("CreateProduct", mock.Anything, mock.Anything).Return("from-mongo", nil)
+		mysqlRepo.On("CreateProduct", mock.Anything, productWithID("from-mongo")).Return(errWrite)
+		auditRepo.On("AppendAudit", mock.Anything, mock.Anything).Return(errWrite).Once()
+
+		service := services.NewProductService(mongoRepo, mysqlRepo, services.WithAuditLog(auditRepo))
+		writeCtx, write := domain.ContextWithWriteRecord(ctx)
+		product := &domain.Product{Name: "Test Product"}
+		require.NoError(t, service.CreateProduct(writeCtx, product))
+		assert.Equal(t, "from-mongo", product.ID)
+		assert.True(t, write.Committed)
+		auditRepo.AssertExpectations(t)
+	})
+}
+
+// TestWriteConsistencyConfig adalah fungsi untuk menguji validasi konfigurasi penulisan
+func TestWriteConsistencyConfig(t *testing.T) {
+	cfg := config.Default()
+	assert.Equal(t, "all", cfg.WriteConsistency)
+
+	cfg.WriteConsistency = "majority"
+	cfg.WriteTimeout = -time.Second
+	err := cfg.Validate()
+	var validationErr *config.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	keys := make([]string, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		keys[i] = fieldErr.Key
+	}
+	assert.Equal(t, []string{"write_consistency", "write_timeout"}, keys)
+}
@@ -0,0 +1,3 @@
+-- Waktu terakhir produk diubah, dipakai untuk header Last-Modified
+ALTER TABLE product
+    ADD COLUMN updated_at DATETIME(3) NULL;
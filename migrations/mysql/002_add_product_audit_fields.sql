@@ -0,0 +1,9 @@
+-- Waktu pembuatan dan actor yang membuat/mengubah produk
+ALTER TABLE product
+    ADD COLUMN created_at DATETIME(3) NULL,
+    ADD COLUMN created_by VARCHAR(255) NULL,
+    ADD COLUMN updated_by VARCHAR(255) NULL;
+
+-- Index untuk pengurutan daftar produk berdasarkan waktu
+CREATE INDEX idx_product_created_at ON product (created_at);
+CREATE INDEX idx_product_updated_at ON product (updated_at);
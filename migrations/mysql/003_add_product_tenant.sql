@@ -0,0 +1,7 @@
+-- Tenant pemilik produk, produk yang sudah ada menjadi milik tenant default
+ALTER TABLE product
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER product_id;
+
+-- Index untuk query produk per tenant
+CREATE INDEX idx_product_tenant ON product (tenant_id, product_id);
+CREATE INDEX idx_product_tenant_updated_at ON product (tenant_id, updated_at);
@@ -0,0 +1,3 @@
+-- ID produk berupa string dari pembuat ID (ObjectID, UUIDv7, ULID, Snowflake) atau dari client
+ALTER TABLE product
+    MODIFY COLUMN product_id VARCHAR(64) NOT NULL;
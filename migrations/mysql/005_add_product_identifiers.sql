@@ -0,0 +1,10 @@
+-- SKU, barcode (GTIN-14) dan slug produk, NULL jika produk tidak memiliki identifier tersebut
+ALTER TABLE product
+    ADD COLUMN sku VARCHAR(64) NULL AFTER product_name,
+    ADD COLUMN barcode VARCHAR(14) NULL AFTER sku,
+    ADD COLUMN slug VARCHAR(128) NULL AFTER barcode;
+
+-- Identifier unik per tenant, NULL tidak ikut diperiksa
+CREATE UNIQUE INDEX uq_product_tenant_sku ON product (tenant_id, sku);
+CREATE UNIQUE INDEX uq_product_tenant_barcode ON product (tenant_id, barcode);
+CREATE UNIQUE INDEX uq_product_tenant_slug ON product (tenant_id, slug);
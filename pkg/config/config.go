@@ -1,17 +1,378 @@
 package config
 
+import (
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Konfigurasi aplikasi. Nilai diisi berlapis oleh Loader: Default, file YAML/TOML,
+// environment variable GOPRODUCT_<KEY> lalu flag --<key>. Key mengikuti tag yaml.
+// Field dengan tag secret disamarkan oleh Redacted, field dengan tag reload bisa
+// diubah saat aplikasi berjalan melalui Manager. Hanya level log, batas rate limit,
+// kebijakan penulisan dan read fallback yang dibaca ulang; koneksi, resilience, cache,
+// autentikasi dan fitur lain dibangun saat startup sehingga baru berlaku setelah restart.
 type Config struct {
-	ServerAddress     string
-	MongoURI          string
-	MySQLDSN          string
-	MongoDatabaseName string
+	ServerAddress string `yaml:"server_address" toml:"server_address"`
+	// Batas waktu membaca request, menulis response dan koneksi keep-alive yang idle, 0 berarti tanpa batas
+	ServerReadTimeout  time.Duration `yaml:"server_read_timeout" toml:"server_read_timeout"`
+	ServerWriteTimeout time.Duration `yaml:"server_write_timeout" toml:"server_write_timeout"`
+	ServerIdleTimeout  time.Duration `yaml:"server_idle_timeout" toml:"server_idle_timeout"`
+	// Ukuran maksimum body request dalam byte
+	ServerBodyLimit int `yaml:"server_body_limit" toml:"server_body_limit"`
+
+	MongoURI          string `yaml:"mongo_uri" toml:"mongo_uri" secret:"uri"`
+	MySQLDSN          string `yaml:"mysql_dsn" toml:"mysql_dsn" secret:"dsn"`
+	MongoDatabaseName string `yaml:"mongo_database" toml:"mongo_database"`
+
+	// Pool koneksi MySQL, 0 berarti tanpa batas
+	MySQLMaxOpenConns int `yaml:"mysql_max_open_conns" toml:"mysql_max_open_conns"`
+	MySQLMaxIdleConns int `yaml:"mysql_max_idle_conns" toml:"mysql_max_idle_conns"`
+	// Umur maksimum koneksi MySQL dan lama koneksi boleh idle, 0 berarti tanpa batas
+	MySQLConnMaxLifetime time.Duration `yaml:"mysql_conn_max_lifetime" toml:"mysql_conn_max_lifetime"`
+	MySQLConnMaxIdleTime time.Duration `yaml:"mysql_conn_max_idle_time" toml:"mysql_conn_max_idle_time"`
+	// Timeout dial, baca dan tulis MySQL jika tidak diatur pada DSN
+	MySQLConnectTimeout time.Duration `yaml:"mysql_connect_timeout" toml:"mysql_connect_timeout"`
+	MySQLReadTimeout    time.Duration `yaml:"mysql_read_timeout" toml:"mysql_read_timeout"`
+	MySQLWriteTimeout   time.Duration `yaml:"mysql_write_timeout" toml:"mysql_write_timeout"`
+
+	// Pool koneksi MongoDB per server, 0 pada ukuran maksimum memakai default driver
+	MongoMaxPoolSize     int           `yaml:"mongo_max_pool_size" toml:"mongo_max_pool_size"`
+	MongoMinPoolSize     int           `yaml:"mongo_min_pool_size" toml:"mongo_min_pool_size"`
+	MongoMaxConnIdleTime time.Duration `yaml:"mongo_max_conn_idle_time" toml:"mongo_max_conn_idle_time"`
+	// Timeout dial, operasi socket dan pemilihan server MongoDB jika tidak diatur pada URI
+	MongoConnectTimeout         time.Duration `yaml:"mongo_connect_timeout" toml:"mongo_connect_timeout"`
+	MongoSocketTimeout          time.Duration `yaml:"mongo_socket_timeout" toml:"mongo_socket_timeout"`
+	MongoServerSelectionTimeout time.Duration `yaml:"mongo_server_selection_timeout" toml:"mongo_server_selection_timeout"`
+	// Retryable writes dan reads MongoDB
+	MongoRetryWrites bool `yaml:"mongo_retry_writes" toml:"mongo_retry_writes"`
+	MongoRetryReads  bool `yaml:"mongo_retry_reads" toml:"mongo_retry_reads"`
+
+	// Percobaan koneksi database saat startup, jeda naik dua kali lipat sampai db_connect_max_backoff
+	DBConnectAttempts   int           `yaml:"db_connect_attempts" toml:"db_connect_attempts"`
+	DBConnectBackoff    time.Duration `yaml:"db_connect_backoff" toml:"db_connect_backoff"`
+	DBConnectMaxBackoff time.Duration `yaml:"db_connect_max_backoff" toml:"db_connect_max_backoff"`
+
+	// Circuit breaker, retry dan bulkhead untuk repository produk MongoDB dan MySQL.
+	// Kebijakan dibuat saat startup, perubahan baru berlaku setelah restart.
+	ResilienceEnabled bool `yaml:"resilience_enabled" toml:"resilience_enabled"`
+	// Kegagalan berturut-turut yang membuka circuit, lama circuit terbuka dan
+	// jumlah panggilan percobaan sukses yang menutupnya kembali
+	BreakerFailureThreshold int           `yaml:"breaker_failure_threshold" toml:"breaker_failure_threshold"`
+	BreakerOpenTimeout      time.Duration `yaml:"breaker_open_timeout" toml:"breaker_open_timeout"`
+	BreakerSuccessThreshold int           `yaml:"breaker_success_threshold" toml:"breaker_success_threshold"`
+	// Percobaan maksimum untuk error sementara, jeda acak (full jitter) naik dari retry_base_delay sampai retry_max_delay
+	RetryMaxAttempts int           `yaml:"retry_max_attempts" toml:"retry_max_attempts"`
+	RetryBaseDelay   time.Duration `yaml:"retry_base_delay" toml:"retry_base_delay"`
+	RetryMaxDelay    time.Duration `yaml:"retry_max_delay" toml:"retry_max_delay"`
+	// Batas waktu satu percobaan panggilan repository, 0 berarti tanpa batas
+	RepositoryTimeout time.Duration `yaml:"repository_timeout" toml:"repository_timeout"`
+	// Panggilan bersamaan maksimum per store (0 berarti tanpa batas) dan lama menunggu slot
+	BulkheadMongoMaxConcurrent int           `yaml:"bulkhead_mongo_max_concurrent" toml:"bulkhead_mongo_max_concurrent"`
+	BulkheadMySQLMaxConcurrent int           `yaml:"bulkhead_mysql_max_concurrent" toml:"bulkhead_mysql_max_concurrent"`
+	BulkheadMaxWait            time.Duration `yaml:"bulkhead_max_wait" toml:"bulkhead_max_wait"`
+
+	// Konsistensi penulisan produk: "primary" (ack setelah MongoDB, MySQL di background),
+	// "all" (paralel, gagal jika salah satu gagal) atau "quorum" (paralel, ack setelah mayoritas)
+	WriteConsistency string `yaml:"write_consistency" toml:"write_consistency" reload:"true"`
+	// Batas waktu penulisan ke setiap store, termasuk yang berlanjut di background, 0 berarti tanpa batas
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout" reload:"true"`
+
+	// Strategi pembuatan ID produk: "objectid", "uuidv7", "ulid" atau "snowflake"
+	IDStrategy string `yaml:"id_strategy" toml:"id_strategy"`
+	// Node ID Snowflake (0-1023), wajib berbeda untuk setiap instance aplikasi
+	SnowflakeNodeID int `yaml:"snowflake_node_id" toml:"snowflake_node_id"`
+	// Menerima field id dari client saat pembuatan produk, selain itu ID selalu dibuat server
+	ClientProductIDs bool `yaml:"client_product_ids" toml:"client_product_ids"`
+
+	// Menjalankan consumer change stream MongoDB -> MySQL di dalam proses aplikasi
+	ChangeStreamSyncEnabled bool `yaml:"change_stream_sync_enabled" toml:"change_stream_sync_enabled"`
+	// Nama consumer change stream, dipakai sebagai kunci resume token
+	ChangeStreamSyncName string `yaml:"change_stream_sync_name" toml:"change_stream_sync_name"`
+
+	// Backend cache pembacaan produk: "" (nonaktif), "memory" atau "redis"
+	CacheBackend string `yaml:"cache_backend" toml:"cache_backend"`
+	// Masa berlaku entry cache
+	CacheTTL time.Duration `yaml:"cache_ttl" toml:"cache_ttl"`
+	// Jumlah maksimum entry untuk cache memory
+	CacheMaxEntries int `yaml:"cache_max_entries" toml:"cache_max_entries"`
+
+	// Koneksi server Redis untuk cache backend "redis"
+	RedisAddr     string `yaml:"redis_addr" toml:"redis_addr"`
+	RedisPassword string `yaml:"redis_password" toml:"redis_password" secret:"true"`
+	RedisDB       int    `yaml:"redis_db" toml:"redis_db"`
+
+	// Nilai header Cache-Control per route, key sesuai handlers.Route*
+	HTTPCacheControl map[string]string `yaml:"http_cache_control" toml:"http_cache_control"`
+
+	// Route yang dibaca dari MySQL saat MongoDB tidak tersedia, key sesuai handlers.Route*.
+	// Nilai adalah batas waktu pembacaan MongoDB sebelum beralih ke MySQL, "0s" berarti hanya saat error.
+	ReadFallback map[string]string `yaml:"read_fallback" toml:"read_fallback" reload:"true"`
+
+	// Mewajibkan autentikasi JWT untuk semua endpoint /api
+	AuthEnabled bool `yaml:"auth_enabled" toml:"auth_enabled"`
+	// Secret untuk token HS256
+	JWTHS256Secret string `yaml:"jwt_hs256_secret" toml:"jwt_hs256_secret" secret:"true"`
+	// Sumber public key RS256: file JWKS atau endpoint JWKS
+	JWKSFile string `yaml:"jwks_file" toml:"jwks_file"`
+	JWKSURL  string `yaml:"jwks_url" toml:"jwks_url"`
+	// Claim iss dan aud yang wajib, kosong berarti tidak diperiksa
+	JWTIssuer   string `yaml:"jwt_issuer" toml:"jwt_issuer"`
+	JWTAudience string `yaml:"jwt_audience" toml:"jwt_audience"`
+	// Toleransi selisih jam saat memeriksa exp/nbf
+	JWTLeeway time.Duration `yaml:"jwt_leeway" toml:"jwt_leeway"`
+
+	// IP atau CIDR (dipisah koma) gateway yang boleh mengirim header X-Actor-ID saat autentikasi
+	// nonaktif. Kosong berarti header diabaikan dan actor selalu anonymous.
+	ActorTrustedProxies string `yaml:"actor_trusted_proxies" toml:"actor_trusted_proxies"`
+
+	// Menerima header X-API-Key dan mengaktifkan endpoint /admin/api-keys
+	APIKeysEnabled bool `yaml:"api_keys_enabled" toml:"api_keys_enabled"`
+
+	// File YAML pemetaan role -> permission, kosong berarti memakai policy bawaan.
+	// Policy hanya diterapkan jika autentikasi JWT atau API key aktif.
+	PolicyFile string `yaml:"policy_file" toml:"policy_file"`
+
+	// Domain dasar untuk tenant dari subdomain, misalnya "shop.example.com"
+	TenantBaseDomain string `yaml:"tenant_base_domain" toml:"tenant_base_domain"`
+	// Nama claim JWT yang berisi tenant
+	JWTTenantClaim string `yaml:"jwt_tenant_claim" toml:"jwt_tenant_claim"`
+	// Override konfigurasi per tenant. Jika diisi, hanya tenant yang terdaftar
+	// (dan tenant default) yang diterima.
+	Tenants map[string]TenantConfig `yaml:"tenants" toml:"tenants"`
+
+	// Backend rate limit: "" (nonaktif), "memory" atau "redis"
+	RateLimitBackend string `yaml:"rate_limit_backend" toml:"rate_limit_backend"`
+	// Jumlah request baca dan tulis per client (API key, subject token atau IP) per periode, 0 berarti tanpa batas
+	RateLimitRead  int `yaml:"rate_limit_read" toml:"rate_limit_read" reload:"true"`
+	RateLimitWrite int `yaml:"rate_limit_write" toml:"rate_limit_write" reload:"true"`
+	// Jumlah request baca dan tulis gabungan per tenant per periode, 0 berarti tanpa batas
+	RateLimitTenantRead  int `yaml:"rate_limit_tenant_read" toml:"rate_limit_tenant_read" reload:"true"`
+	RateLimitTenantWrite int `yaml:"rate_limit_tenant_write" toml:"rate_limit_tenant_write" reload:"true"`
+	// Jumlah request per alamat IP per periode, diperiksa sebelum autentikasi, 0 berarti tanpa batas
+	RateLimitIP int `yaml:"rate_limit_ip" toml:"rate_limit_ip" reload:"true"`
+	// Periode isi ulang bucket rate limit
+	RateLimitPeriod time.Duration `yaml:"rate_limit_period" toml:"rate_limit_period" reload:"true"`
+
+	// Backend penyimpanan Idempotency-Key: "" (nonaktif), "memory" atau "redis"
+	IdempotencyBackend string `yaml:"idempotency_backend" toml:"idempotency_backend"`
+	// Lama response disimpan untuk Idempotency-Key yang sama
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" toml:"idempotency_ttl"`
+
+	// Level log minimum: debug, info, warn atau error
+	LogLevel string `yaml:"log_level" toml:"log_level" reload:"true"`
+	// Format log: json atau text
+	LogFormat string `yaml:"log_format" toml:"log_format"`
+
+	// Mengaktifkan endpoint metrics Prometheus dan instrumentasi HTTP/repository
+	MetricsEnabled bool `yaml:"metrics_enabled" toml:"metrics_enabled"`
+	// Path endpoint metrics
+	MetricsPath string `yaml:"metrics_path" toml:"metrics_path"`
+	// Produk dengan stok di bawah nilai ini dihitung sebagai stok rendah
+	LowStockThreshold int `yaml:"low_stock_threshold" toml:"low_stock_threshold"`
+
+	// Exporter tracing OpenTelemetry: "" (nonaktif), "otlp" atau "stdout"
+	TracingExporter string `yaml:"tracing_exporter" toml:"tracing_exporter"`
+	// Nama service pada span
+	TracingServiceName string `yaml:"tracing_service_name" toml:"tracing_service_name"`
+	// Alamat collector OTLP/HTTP (host:port)
+	TracingEndpoint string `yaml:"tracing_endpoint" toml:"tracing_endpoint"`
+	// Mengirim span ke collector tanpa TLS
+	TracingInsecure bool `yaml:"tracing_insecure" toml:"tracing_insecure"`
+	// File tujuan exporter stdout, kosong berarti stdout
+	TracingFile string `yaml:"tracing_file" toml:"tracing_file"`
+	// Rasio sampling trace baru (0-1)
+	TracingSampleRatio float64 `yaml:"tracing_sample_ratio" toml:"tracing_sample_ratio"`
+
+	// Batas waktu ping setiap dependency pada /readyz
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" toml:"health_check_timeout"`
+
+	// Batas waktu seluruh proses shutdown (request, worker dan penutupan koneksi)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// Jeda antara /readyz gagal dan server berhenti menerima koneksi
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
 }
 
-func LoadConfig() *Config {
+// Konfigurasi yang bisa diubah per tenant, nilai kosong memakai konfigurasi global
+type TenantConfig struct {
+	// Masa berlaku entry cache
+	CacheTTL time.Duration `yaml:"cache_ttl" toml:"cache_ttl"`
+	// Nilai header Cache-Control per route
+	HTTPCacheControl map[string]string `yaml:"http_cache_control" toml:"http_cache_control"`
+}
+
+// Daftar tenant yang terdaftar
+func (c *Config) TenantIDs() []string {
+	ids := make([]string, 0, len(c.Tenants))
+	for id := range c.Tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Jaringan gateway yang dipercaya untuk header X-Actor-ID, nilai yang tidak valid dilewati
+func (c *Config) ActorTrustedProxyPrefixes() []netip.Prefix {
+	prefixes, _ := parsePrefixes(c.ActorTrustedProxies)
+	return prefixes
+}
+
+// Membaca daftar IP atau CIDR yang dipisah koma, IP tunggal menjadi prefix /32 atau /128
+func parsePrefixes(value string) ([]netip.Prefix, []string) {
+	var prefixes []netip.Prefix
+	var invalid []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(item); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(item); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		invalid = append(invalid, item)
+	}
+	return prefixes, invalid
+}
+
+// Batas waktu pembacaan MongoDB per route yang boleh dibaca dari MySQL
+func (c *Config) ReadFallbackTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(c.ReadFallback))
+	for route, value := range c.ReadFallback {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			timeouts[route] = timeout
+		}
+	}
+	return timeouts
+}
+
+// Masa berlaku cache per tenant yang di-override
+func (c *Config) TenantCacheTTL() map[string]time.Duration {
+	ttl := make(map[string]time.Duration)
+	for id, tenant := range c.Tenants {
+		if tenant.CacheTTL > 0 {
+			ttl[id] = tenant.CacheTTL
+		}
+	}
+	return ttl
+}
+
+// Cache-Control per tenant yang di-override
+func (c *Config) TenantHTTPCacheControl() map[string]map[string]string {
+	cacheControl := make(map[string]map[string]string)
+	for id, tenant := range c.Tenants {
+		if len(tenant.HTTPCacheControl) > 0 {
+			cacheControl[id] = tenant.HTTPCacheControl
+		}
+	}
+	return cacheControl
+}
+
+// Konfigurasi bawaan untuk pengembangan lokal, tanpa secret.
+// Password database diisi melalui file konfigurasi, environment atau file secret.
+func Default() *Config {
 	return &Config{
-		ServerAddress:     ":8080",
-		MySQLDSN:          "root:mysql123456@tcp(localhost:3306)/goproduct_db",
+		ServerAddress:      ":8080",
+		ServerReadTimeout:  15 * time.Second,
+		ServerWriteTimeout: 15 * time.Second,
+		ServerIdleTimeout:  time.Minute,
+		ServerBodyLimit:    4 * 1024 * 1024,
+
+		MySQLDSN:          "root@tcp(localhost:3306)/goproduct_db",
 		MongoURI:          "mongodb://localhost:27017",
 		MongoDatabaseName: "goproduct_db",
+
+		MySQLMaxOpenConns:    25,
+		MySQLMaxIdleConns:    10,
+		MySQLConnMaxLifetime: 30 * time.Minute,
+		MySQLConnMaxIdleTime: 5 * time.Minute,
+		MySQLConnectTimeout:  5 * time.Second,
+		MySQLReadTimeout:     30 * time.Second,
+		MySQLWriteTimeout:    30 * time.Second,
+
+		MongoMaxPoolSize:            100,
+		MongoMaxConnIdleTime:        5 * time.Minute,
+		MongoConnectTimeout:         10 * time.Second,
+		MongoSocketTimeout:          30 * time.Second,
+		MongoServerSelectionTimeout: 5 * time.Second,
+		MongoRetryWrites:            true,
+		MongoRetryReads:             true,
+
+		DBConnectAttempts:   10,
+		DBConnectBackoff:    500 * time.Millisecond,
+		DBConnectMaxBackoff: 10 * time.Second,
+
+		ResilienceEnabled:          true,
+		BreakerFailureThreshold:    5,
+		BreakerOpenTimeout:         30 * time.Second,
+		BreakerSuccessThreshold:    2,
+		RetryMaxAttempts:           3,
+		RetryBaseDelay:             50 * time.Millisecond,
+		RetryMaxDelay:              time.Second,
+		RepositoryTimeout:          10 * time.Second,
+		BulkheadMongoMaxConcurrent: 100,
+		BulkheadMySQLMaxConcurrent: 25,
+		BulkheadMaxWait:            100 * time.Millisecond,
+
+		WriteConsistency: "all",
+		WriteTimeout:     30 * time.Second,
+
+		IDStrategy: "objectid",
+
+		ChangeStreamSyncEnabled: false,
+		ChangeStreamSyncName:    "products-mysql-sync",
+
+		CacheBackend:    "memory",
+		CacheTTL:        30 * time.Second,
+		CacheMaxEntries: 10000,
+
+		RedisAddr: "localhost:6379",
+
+		// Client wajib melakukan revalidasi dengan ETag/Last-Modified
+		HTTPCacheControl: map[string]string{
+			"products.get":  "private, no-cache",
+			"products.list": "private, no-cache",
+		},
+
+		ReadFallback: map[string]string{
+			"products.get":  "2s",
+			"products.list": "5s",
+		},
+
+		AuthEnabled: false,
+		JWTLeeway:   30 * time.Second,
+
+		JWTTenantClaim: "tenant_id",
+
+		RateLimitBackend: "memory",
+		RateLimitRead:    300,
+		RateLimitWrite:   60,
+		RateLimitIP:      600,
+		RateLimitPeriod:  time.Minute,
+
+		IdempotencyBackend: "memory",
+		IdempotencyTTL:     24 * time.Hour,
+
+		LogLevel:  "info",
+		LogFormat: "json",
+
+		MetricsEnabled:    true,
+		MetricsPath:       "/metrics",
+		LowStockThreshold: 10,
+
+		TracingExporter:    "",
+		TracingServiceName: "go-fiber-hexagonal-product",
+		TracingEndpoint:    "localhost:4318",
+		TracingInsecure:    true,
+		TracingSampleRatio: 1,
+
+		HealthCheckTimeout: 2 * time.Second,
+
+		ShutdownTimeout: 30 * time.Second,
 	}
 }
@@ -0,0 +1,39 @@
+package config
+
+import "go-fiber-hexagonal-product/pkg/database"
+
+// Pengaturan pool dan timeout koneksi MySQL
+func (c *Config) MySQLOptions() database.MySQLOptions {
+	return database.MySQLOptions{
+		MaxOpenConns:    c.MySQLMaxOpenConns,
+		MaxIdleConns:    c.MySQLMaxIdleConns,
+		ConnMaxLifetime: c.MySQLConnMaxLifetime,
+		ConnMaxIdleTime: c.MySQLConnMaxIdleTime,
+		ConnectTimeout:  c.MySQLConnectTimeout,
+		ReadTimeout:     c.MySQLReadTimeout,
+		WriteTimeout:    c.MySQLWriteTimeout,
+	}
+}
+
+// Pengaturan pool dan timeout koneksi MongoDB
+func (c *Config) MongoOptions() database.MongoOptions {
+	return database.MongoOptions{
+		MaxPoolSize:            uint64(c.MongoMaxPoolSize),
+		MinPoolSize:            uint64(c.MongoMinPoolSize),
+		MaxConnIdleTime:        c.MongoMaxConnIdleTime,
+		ConnectTimeout:         c.MongoConnectTimeout,
+		SocketTimeout:          c.MongoSocketTimeout,
+		ServerSelectionTimeout: c.MongoServerSelectionTimeout,
+		RetryWrites:            c.MongoRetryWrites,
+		RetryReads:             c.MongoRetryReads,
+	}
+}
+
+// Pengaturan percobaan ulang koneksi database saat startup
+func (c *Config) ConnectRetry() database.RetryOptions {
+	return database.RetryOptions{
+		Attempts:       c.DBConnectAttempts,
+		InitialBackoff: c.DBConnectBackoff,
+		MaxBackoff:     c.DBConnectMaxBackoff,
+	}
+}
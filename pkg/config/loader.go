@@ -0,0 +1,316 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Prefix environment variable konfigurasi, misalnya GOPRODUCT_SERVER_ADDRESS
+const EnvPrefix = "GOPRODUCT_"
+
+// Environment variable path file konfigurasi jika flag --config tidak dipakai
+const EnvConfigFile = EnvPrefix + "CONFIG_FILE"
+
+// Suffix environment variable dan flag untuk membaca secret dari file,
+// misalnya GOPRODUCT_MYSQL_DSN_FILE=/run/secrets/mysql_dsn
+const secretFileSuffix = "_FILE"
+
+// Membaca konfigurasi berlapis: Default -> file YAML/TOML -> environment -> flag.
+// Loader yang sama bisa dipakai ulang untuk membaca ulang konfigurasi.
+type Loader struct {
+	// Path file konfigurasi (.yaml, .yml atau .toml), kosong berarti tanpa file
+	File string
+	// Mencetak konfigurasi efektif (secret disamarkan) lalu keluar
+	PrintConfig bool
+
+	// Nilai flag yang diberikan di command line, key sesuai tag yaml
+	flags map[string]string
+	// Path file secret dari flag --<key>-file
+	secretFlags map[string]string
+}
+
+// Membaca argumen command line dan menyiapkan Loader.
+// Setiap field konfigurasi tersedia sebagai flag --<key> (underscore menjadi tanda hubung).
+func NewLoader(name string, args []string) (*Loader, error) {
+	l := &Loader{
+		File:        os.Getenv(EnvConfigFile),
+		flags:       make(map[string]string),
+		secretFlags: make(map[string]string),
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&l.File, "config", l.File, "path to a YAML or TOML config file (env "+EnvConfigFile+")")
+	fs.BoolVar(&l.PrintConfig, "print-config", false, "print the effective configuration with secrets redacted and exit")
+	for _, field := range configFields() {
+		if !field.scalar() {
+			continue
+		}
+		key := field.key
+		fs.Var(&fieldFlag{
+			isBool: field.kind.Kind() == reflect.Bool,
+			set: func(value string) error {
+				l.flags[key] = value
+				return nil
+			},
+		}, flagName(key), "sets "+key+" (env "+envName(key)+")")
+		if field.secret != "" {
+			fs.Func(flagName(key)+"-file", "reads "+key+" from a file", func(path string) error {
+				l.secretFlags[key] = path
+				return nil
+			})
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return l, nil
+}
+
+// Flag untuk satu field konfigurasi, nilai disimpan sebagai string dan diterapkan oleh Load.
+// Field bool bisa dipakai tanpa nilai, misalnya --auth-enabled.
+type fieldFlag struct {
+	isBool bool
+	set    func(string) error
+}
+
+func (f *fieldFlag) String() string     { return "" }
+func (f *fieldFlag) Set(v string) error { return f.set(v) }
+func (f *fieldFlag) IsBoolFlag() bool   { return f.isBool }
+
+// Membaca konfigurasi dari semua lapisan lalu memvalidasinya
+func (l *Loader) Load() (*Config, error) {
+	cfg := Default()
+	if l.File != "" {
+		if err := decodeFile(l.File, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := configFields()
+	for _, field := range fields {
+		if !field.scalar() {
+			continue
+		}
+		value := field.value(cfg)
+		name := envName(field.key)
+		if raw, ok := os.LookupEnv(name); ok {
+			if err := setField(value, raw); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		if path, ok := os.LookupEnv(name + secretFileSuffix); ok && field.secret != "" {
+			if err := setSecretFromFile(value, path); err != nil {
+				return nil, fmt.Errorf("%s: %w", name+secretFileSuffix, err)
+			}
+		}
+	}
+	for _, field := range fields {
+		value := field.value(cfg)
+		if raw, ok := l.flags[field.key]; ok {
+			if err := setField(value, raw); err != nil {
+				return nil, fmt.Errorf("--%s: %w", flagName(field.key), err)
+			}
+		}
+		if path, ok := l.secretFlags[field.key]; ok {
+			if err := setSecretFromFile(value, path); err != nil {
+				return nil, fmt.Errorf("--%s-file: %w", flagName(field.key), err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Membaca file konfigurasi sesuai ekstensinya, key yang tidak dikenal ditolak
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return fmt.Errorf("config file %s: unknown keys %s", path, strings.Join(keys, ", "))
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// Metadata satu field Config
+type configField struct {
+	index      int
+	key        string
+	secret     string
+	reloadable bool
+	kind       reflect.Type
+}
+
+// Field yang bisa diisi dari environment dan flag
+func (f configField) scalar() bool {
+	if f.kind == durationType {
+		return true
+	}
+	switch f.kind.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+		return true
+	case reflect.Map:
+		return f.kind.Key().Kind() == reflect.String && f.kind.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func (f configField) value(cfg *Config) reflect.Value {
+	return reflect.ValueOf(cfg).Elem().Field(f.index)
+}
+
+// Daftar field Config beserta key dari tag yaml
+func configFields() []configField {
+	t := reflect.TypeOf(Config{})
+	fields := make([]configField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, configField{
+			index:      i,
+			key:        key,
+			secret:     field.Tag.Get("secret"),
+			reloadable: field.Tag.Get("reload") == "true",
+			kind:       field.Type,
+		})
+	}
+	return fields
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Mengisi field dari string. Durasi memakai format time.ParseDuration, map memakai
+// format "key=value;key=value" (titik koma agar nilai Cache-Control boleh berisi koma).
+func setField(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(f)
+	case reflect.Map:
+		entries := reflect.MakeMap(value.Type())
+		for _, entry := range strings.Split(raw, ";") {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(entry, "=")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, expected key=value", entry)
+			}
+			entries.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)), reflect.ValueOf(strings.TrimSpace(v)))
+		}
+		value.Set(entries)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// Mengisi field secret dari isi file, newline di akhir file diabaikan
+func setSecretFromFile(value reflect.Value, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return setField(value, strings.TrimRight(string(data), "\r\n"))
+}
+
+// Membaca konfigurasi untuk fungsi main. Jika --print-config dipakai, konfigurasi
+// efektif dicetak lalu proses keluar. Kesalahan flag atau validasi dicetak ke stderr
+// dan proses keluar dengan kode 2.
+func MustLoad(name string, args []string) (*Config, *Loader) {
+	loader, err := NewLoader(name, args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if loader.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	return cfg, loader
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Pemicu reload konfigurasi
+const (
+	TriggerStartup = "startup"
+	TriggerFile    = "file"
+	TriggerSignal  = "sighup"
+	TriggerManual  = "manual"
+)
+
+// Hasil reload konfigurasi
+const (
+	// Perubahan diterapkan dan versi naik
+	ReloadApplied = "applied"
+	// Konfigurasi baru tidak valid, konfigurasi lama tetap dipakai
+	ReloadRejected = "rejected"
+	// Tidak ada perubahan pada key yang bisa di-reload
+	ReloadUnchanged = "unchanged"
+)
+
+// Jumlah maksimum riwayat reload yang disimpan
+const reloadHistorySize = 20
+
+// Jeda untuk menggabungkan beberapa event file dari satu penyimpanan
+const reloadDebounce = 200 * time.Millisecond
+
+// Satu catatan riwayat reload
+type ReloadEvent struct {
+	// Versi konfigurasi yang aktif setelah reload
+	Version int       `json:"version"`
+	At      time.Time `json:"at"`
+	Trigger string    `json:"trigger"`
+	Status  string    `json:"status"`
+	// Key yang berubah dan sudah diterapkan
+	Changed []string `json:"changed,omitempty"`
+	// Key yang berubah tetapi baru berlaku setelah restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Status konfigurasi aktif untuk endpoint admin
+type Status struct {
+	Version  int                    `json:"version"`
+	LoadedAt time.Time              `json:"loaded_at"`
+	File     string                 `json:"file,omitempty"`
+	Config   map[string]interface{} `json:"config"`
+	// Key yang diterapkan saat reload, key lain baru berlaku setelah restart
+	Reloadable []string      `json:"reloadable"`
+	History    []ReloadEvent `json:"history"`
+}
+
+// Konfigurasi aktif beserta versinya
+type snapshot struct {
+	version  int
+	loadedAt time.Time
+	config   *Config
+}
+
+// Menyimpan konfigurasi aktif dan menerapkan reload.
+// Hanya field dengan tag reload yang diganti saat aplikasi berjalan, perubahan field lain
+// dicatat sebagai restart_required. Konfigurasi yang tidak valid ditolak seluruhnya.
+type Manager struct {
+	loader  *Loader
+	current atomic.Pointer[snapshot]
+
+	// Menjaga reload tetap berurutan beserta riwayat dan listener
+	mu        sync.Mutex
+	history   []ReloadEvent
+	listeners []func(previous, current *Config)
+}
+
+// Membuat instance baru dari Manager dengan konfigurasi awal dari loader
+func NewManager(loader *Loader, initial *Config) *Manager {
+	m := &Manager{loader: loader}
+	now := time.Now().UTC()
+	m.current.Store(&snapshot{version: 1, loadedAt: now, config: initial})
+	m.history = []ReloadEvent{{Version: 1, At: now, Trigger: TriggerStartup, Status: ReloadApplied}}
+	return m
+}
+
+// Konfigurasi aktif. Nilai yang dikembalikan tidak boleh diubah.
+func (m *Manager) Current() *Config {
+	return m.current.Load().config
+}
+
+// Menambahkan fungsi yang dipanggil setelah reload diterapkan
+func (m *Manager) OnReload(listener func(previous, current *Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// Membaca ulang konfigurasi dari loader dan menerapkan key yang bisa di-reload
+func (m *Manager) Reload(trigger string) ReloadEvent {
+	event := m.reload(trigger)
+	logReloadEvent(event)
+	return event
+}
+
+func (m *Manager) reload(trigger string) ReloadEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active := m.current.Load()
+	event := ReloadEvent{Version: active.version, At: time.Now().UTC(), Trigger: trigger}
+
+	loaded, err := m.loader.Load()
+	if err != nil {
+		event.Status = ReloadRejected
+		event.Error = err.Error()
+		m.record(event)
+		return event
+	}
+
+	next := *active.config
+	for _, field := range configFields() {
+		previousValue, loadedValue := field.value(active.config), field.value(loaded)
+		if reflect.DeepEqual(previousValue.Interface(), loadedValue.Interface()) {
+			continue
+		}
+		if !field.reloadable {
+			event.RestartRequired = append(event.RestartRequired, field.key)
+			continue
+		}
+		field.value(&next).Set(loadedValue)
+		event.Changed = append(event.Changed, field.key)
+	}
+	if len(event.Changed) == 0 {
+		event.Status = ReloadUnchanged
+		m.record(event)
+		return event
+	}
+
+	event.Status = ReloadApplied
+	event.Version = active.version + 1
+	m.current.Store(&snapshot{version: event.Version, loadedAt: event.At, config: &next})
+	m.record(event)
+	for _, listener := range m.listeners {
+		listener(active.config, &next)
+	}
+	return event
+}
+
+// Menyimpan event ke riwayat, riwayat lama dibuang
+func (m *Manager) record(event ReloadEvent) {
+	m.history = append(m.history, event)
+	if len(m.history) > reloadHistorySize {
+		m.history = m.history[len(m.history)-reloadHistorySize:]
+	}
+}
+
+// Versi, konfigurasi aktif (secret disamarkan) dan riwayat reload terbaru lebih dulu
+func (m *Manager) Status() (Status, error) {
+	m.mu.Lock()
+	active := m.current.Load()
+	history := make([]ReloadEvent, len(m.history))
+	for i, event := range m.history {
+		history[len(m.history)-1-i] = event
+	}
+	m.mu.Unlock()
+
+	// Round-trip YAML agar key dan format durasi sama dengan file konfigurasi
+	data, err := yaml.Marshal(active.config.Redacted())
+	if err != nil {
+		return Status{}, err
+	}
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return Status{}, err
+	}
+	var reloadable []string
+	for _, field := range configFields() {
+		if field.reloadable {
+			reloadable = append(reloadable, field.key)
+		}
+	}
+	return Status{
+		Version:    active.version,
+		LoadedAt:   active.loadedAt,
+		File:       m.loader.File,
+		Config:     values,
+		Reloadable: reloadable,
+		History:    history,
+	}, nil
+}
+
+// Melakukan reload saat file konfigurasi berubah atau proses menerima SIGHUP, sampai ctx selesai.
+// Direktori file yang dipantau agar penggantian file secara atomik (rename, symlink ConfigMap) terdeteksi.
+func (m *Manager) Watch(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	var fileEvents <-chan fsnotify.Event
+	var fileErrors <-chan error
+	if m.loader.File != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		defer watcher.Close()
+		if err := watcher.Add(filepath.Dir(m.loader.File)); err != nil {
+			return err
+		}
+		fileEvents, fileErrors = watcher.Events, watcher.Errors
+	}
+
+	file := filepath.Clean(m.loader.File)
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			debounce.Stop()
+			return ctx.Err()
+		case <-signals:
+			m.Reload(TriggerSignal)
+		case event := <-fileEvents:
+			if filepath.Clean(event.Name) == file || filepath.Base(event.Name) == "..data" {
+				debounce.Reset(reloadDebounce)
+			}
+		case err := <-fileErrors:
+			slog.Warn("config watcher error", "error", err)
+		case <-debounce.C:
+			m.Reload(TriggerFile)
+		}
+	}
+}
+
+// Mencatat hasil reload ke log
+func logReloadEvent(event ReloadEvent) {
+	switch event.Status {
+	case ReloadRejected:
+		slog.Error("config reload rejected, keeping previous config", "trigger", event.Trigger, "version", event.Version, "error", event.Error)
+	case ReloadApplied:
+		slog.Info("config reloaded", "trigger", event.Trigger, "version", event.Version, "changed", event.Changed)
+	default:
+		slog.Info("config reload found no reloadable changes", "trigger", event.Trigger, "version", event.Version)
+	}
+	if len(event.RestartRequired) > 0 {
+		slog.Warn("config changes require restart", "keys", event.RestartRequired)
+	}
+}
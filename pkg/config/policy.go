@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Isi file policy otorisasi, contoh:
+//
+//	roles:
+//	  viewer: [products:read]
+//	  stock_clerk: [products:read, products:adjust_stock]
+//	  admin: ["*"]
+type policyFile struct {
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// Membaca pemetaan role -> permission dari file YAML
+func LoadPolicyFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy policyFile
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	if len(policy.Roles) == 0 {
+		return nil, fmt.Errorf("policy file %s defines no roles", path)
+	}
+	return policy.Roles, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+// Nilai pengganti untuk secret
+const Redacted = "[REDACTED]"
+
+// Salinan konfigurasi dengan secret disamarkan. Untuk DSN MySQL dan URI MongoDB
+// hanya password yang disamarkan agar host dan database tetap terlihat.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, field := range configFields() {
+		value := field.value(&redacted)
+		if field.secret == "" || value.Kind() != reflect.String || value.String() == "" {
+			continue
+		}
+		switch field.secret {
+		case "dsn":
+			value.SetString(redactDSN(value.String()))
+		case "uri":
+			value.SetString(redactURI(value.String()))
+		default:
+			value.SetString(Redacted)
+		}
+	}
+	return &redacted
+}
+
+// Menulis konfigurasi efektif dalam format YAML dengan secret disamarkan
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func redactDSN(dsn string) string {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return Redacted
+	}
+	if cfg.Passwd != "" {
+		cfg.Passwd = Redacted
+	}
+	return cfg.FormatDSN()
+}
+
+func redactURI(raw string) string {
+	uri, err := url.Parse(raw)
+	if err != nil {
+		return Redacted
+	}
+	if _, hasPassword := uri.User.Password(); hasPassword {
+		uri.User = url.UserPassword(uri.User.Username(), "REDACTED")
+	}
+	return uri.String()
+}
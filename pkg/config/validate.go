@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Kesalahan satu field konfigurasi
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+// Semua kesalahan konfigurasi yang ditemukan saat validasi
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, fieldErr := range e.Errors {
+		b.WriteString("\n  - " + fieldErr.Key + ": " + fieldErr.Message)
+	}
+	return b.String()
+}
+
+// Pengumpul kesalahan validasi
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) addf(key, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	quoted := make([]string, len(allowed))
+	for i, candidate := range allowed {
+		quoted[i] = fmt.Sprintf("%q", candidate)
+	}
+	v.addf(key, "must be one of %s (got %q)", strings.Join(quoted, ", "), value)
+}
+
+func (v *validator) positive(key string, value time.Duration) {
+	if value <= 0 {
+		v.addf(key, "must be greater than 0 (got %s)", value)
+	}
+}
+
+func (v *validator) nonNegative(key string, value int) {
+	if value < 0 {
+		v.addf(key, "must not be negative (got %d)", value)
+	}
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(key, "is required")
+	}
+}
+
+// Memeriksa seluruh konfigurasi dan mengembalikan *ValidationError berisi semua kesalahan
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.required("server_address", c.ServerAddress)
+	for key, value := range map[string]time.Duration{
+		"server_read_timeout":  c.ServerReadTimeout,
+		"server_write_timeout": c.ServerWriteTimeout,
+		"server_idle_timeout":  c.ServerIdleTimeout,
+		"shutdown_delay":       c.ShutdownDelay,
+	} {
+		if value < 0 {
+			v.addf(key, "must not be negative (got %s)", value)
+		}
+	}
+	if c.ServerBodyLimit <= 0 {
+		v.addf("server_body_limit", "must be greater than 0 (got %d)", c.ServerBodyLimit)
+	}
+
+	// Topologi store
+	if uri, err := url.Parse(c.MongoURI); err != nil || (uri.Scheme != "mongodb" && uri.Scheme != "mongodb+srv") {
+		v.addf("mongo_uri", "must be a mongodb:// or mongodb+srv:// URI")
+	}
+	v.required("mongo_database", c.MongoDatabaseName)
+	if _, err := mysql.ParseDSN(c.MySQLDSN); err != nil {
+		v.addf("mysql_dsn", "invalid DSN: %v", err)
+	}
+
+	// Pool dan timeout database
+	v.nonNegative("mysql_max_open_conns", c.MySQLMaxOpenConns)
+	v.nonNegative("mysql_max_idle_conns", c.MySQLMaxIdleConns)
+	if c.MySQLMaxOpenConns > 0 && c.MySQLMaxIdleConns > c.MySQLMaxOpenConns {
+		v.addf("mysql_max_idle_conns", "must not exceed mysql_max_open_conns (%d)", c.MySQLMaxOpenConns)
+	}
+	v.nonNegative("mongo_max_pool_size", c.MongoMaxPoolSize)
+	v.nonNegative("mongo_min_pool_size", c.MongoMinPoolSize)
+	if c.MongoMaxPoolSize > 0 && c.MongoMinPoolSize > c.MongoMaxPoolSize {
+		v.addf("mongo_min_pool_size", "must not exceed mongo_max_pool_size (%d)", c.MongoMaxPoolSize)
+	}
+	for key, value := range map[string]time.Duration{
+		"mysql_conn_max_lifetime":  c.MySQLConnMaxLifetime,
+		"mysql_conn_max_idle_time": c.MySQLConnMaxIdleTime,
+		"mysql_connect_timeout":    c.MySQLConnectTimeout,
+		"mysql_read_timeout":       c.MySQLReadTimeout,
+		"mysql_write_timeout":      c.MySQLWriteTimeout,
+		"mongo_max_conn_idle_time": c.MongoMaxConnIdleTime,
+		"mongo_connect_timeout":    c.MongoConnectTimeout,
+		"mongo_socket_timeout":     c.MongoSocketTimeout,
+	} {
+		if value < 0 {
+			v.addf(key, "must not be negative (got %s)", value)
+		}
+	}
+	v.positive("mongo_server_selection_timeout", c.MongoServerSelectionTimeout)
+	if c.DBConnectAttempts < 1 {
+		v.addf("db_connect_attempts", "must be at least 1 (got %d)", c.DBConnectAttempts)
+	}
+	v.positive("db_connect_backoff", c.DBConnectBackoff)
+	if c.DBConnectMaxBackoff < c.DBConnectBackoff {
+		v.addf("db_connect_max_backoff", "must not be shorter than db_connect_backoff (%s)", c.DBConnectBackoff)
+	}
+
+	if c.ResilienceEnabled {
+		v.nonNegative("breaker_failure_threshold", c.BreakerFailureThreshold)
+		if c.BreakerFailureThreshold > 0 {
+			v.positive("breaker_open_timeout", c.BreakerOpenTimeout)
+		}
+		if c.BreakerSuccessThreshold < 1 {
+			v.addf("breaker_success_threshold", "must be at least 1 (got %d)", c.BreakerSuccessThreshold)
+		}
+		if c.RetryMaxAttempts < 1 {
+			v.addf("retry_max_attempts", "must be at least 1 (got %d)", c.RetryMaxAttempts)
+		}
+		if c.RetryMaxDelay < c.RetryBaseDelay {
+			v.addf("retry_max_delay", "must not be shorter than retry_base_delay (%s)", c.RetryBaseDelay)
+		}
+		for key, value := range map[string]time.Duration{
+			"retry_base_delay":   c.RetryBaseDelay,
+			"repository_timeout": c.RepositoryTimeout,
+			"bulkhead_max_wait":  c.BulkheadMaxWait,
+		} {
+			if value < 0 {
+				v.addf(key, "must not be negative (got %s)", value)
+			}
+		}
+		v.nonNegative("bulkhead_mongo_max_concurrent", c.BulkheadMongoMaxConcurrent)
+		v.nonNegative("bulkhead_mysql_max_concurrent", c.BulkheadMySQLMaxConcurrent)
+	}
+
+	v.oneOf("write_consistency", c.WriteConsistency, "primary", "all", "quorum")
+	if c.WriteTimeout < 0 {
+		v.addf("write_timeout", "must not be negative (got %s)", c.WriteTimeout)
+	}
+
+	v.oneOf("id_strategy", c.IDStrategy, "objectid", "uuidv7", "ulid", "snowflake")
+	if c.IDStrategy == "snowflake" && (c.SnowflakeNodeID < 0 || c.SnowflakeNodeID > 1023) {
+		v.addf("snowflake_node_id", "must be between 0 and 1023 (got %d)", c.SnowflakeNodeID)
+	}
+
+	if c.ChangeStreamSyncEnabled {
+		v.required("change_stream_sync_name", c.ChangeStreamSyncName)
+	}
+
+	for route, value := range c.ReadFallback {
+		key := "read_fallback." + route
+		v.oneOf(key, route, "products.get", "products.list")
+		if timeout, err := time.ParseDuration(value); err != nil || timeout < 0 {
+			v.addf(key, "must be a non-negative duration (got %q)", value)
+		}
+	}
+
+	v.oneOf("cache_backend", c.CacheBackend, "", "memory", "redis")
+	if c.CacheBackend != "" {
+		v.positive("cache_ttl", c.CacheTTL)
+	}
+	if c.CacheBackend == "memory" && c.CacheMaxEntries <= 0 {
+		v.addf("cache_max_entries", "must be greater than 0 for the memory cache (got %d)", c.CacheMaxEntries)
+	}
+	if c.CacheBackend == "redis" || c.RateLimitBackend == "redis" || c.IdempotencyBackend == "redis" {
+		v.required("redis_addr", c.RedisAddr)
+	}
+	v.nonNegative("redis_db", c.RedisDB)
+
+	// Autentikasi
+	if c.AuthEnabled && c.JWTHS256Secret == "" && c.JWKSFile == "" && c.JWKSURL == "" {
+		v.addf("auth_enabled", "requires jwt_hs256_secret, jwks_file or jwks_url")
+	}
+	if c.JWKSFile != "" && c.JWKSURL != "" {
+		v.addf("jwks_url", "cannot be combined with jwks_file")
+	}
+	if c.JWTHS256Secret != "" && len(c.JWTHS256Secret) < 32 {
+		v.addf("jwt_hs256_secret", "must be at least 32 bytes")
+	}
+	if c.JWTLeeway < 0 {
+		v.addf("jwt_leeway", "must not be negative (got %s)", c.JWTLeeway)
+	}
+	v.required("jwt_tenant_claim", c.JWTTenantClaim)
+	if _, invalid := parsePrefixes(c.ActorTrustedProxies); len(invalid) > 0 {
+		v.addf("actor_trusted_proxies", "must be a comma separated list of IPs or CIDRs (invalid: %s)", strings.Join(invalid, ", "))
+	}
+
+	v.oneOf("rate_limit_backend", c.RateLimitBackend, "", "memory", "redis")
+	v.nonNegative("rate_limit_read", c.RateLimitRead)
+	v.nonNegative("rate_limit_write", c.RateLimitWrite)
+	v.nonNegative("rate_limit_tenant_read", c.RateLimitTenantRead)
+	v.nonNegative("rate_limit_tenant_write", c.RateLimitTenantWrite)
+	v.nonNegative("rate_limit_ip", c.RateLimitIP)
+	if c.RateLimitBackend != "" {
+		v.positive("rate_limit_period", c.RateLimitPeriod)
+	}
+
+	v.oneOf("idempotency_backend", c.IdempotencyBackend, "", "memory", "redis")
+	if c.IdempotencyBackend != "" {
+		v.positive("idempotency_ttl", c.IdempotencyTTL)
+	}
+
+	// Observability
+	v.oneOf("log_level", strings.ToLower(c.LogLevel), "debug", "info", "warn", "error")
+	v.oneOf("log_format", strings.ToLower(c.LogFormat), "json", "text")
+	if c.MetricsEnabled && !strings.HasPrefix(c.MetricsPath, "/") {
+		v.addf("metrics_path", "must start with / (got %q)", c.MetricsPath)
+	}
+	v.nonNegative("low_stock_threshold", c.LowStockThreshold)
+	v.oneOf("tracing_exporter", c.TracingExporter, "", "otlp", "stdout")
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		v.addf("tracing_sample_ratio", "must be between 0 and 1 (got %g)", c.TracingSampleRatio)
+	}
+	if c.TracingExporter != "" {
+		v.required("tracing_service_name", c.TracingServiceName)
+	}
+
+	v.positive("health_check_timeout", c.HealthCheckTimeout)
+	v.positive("shutdown_timeout", c.ShutdownTimeout)
+	if c.ShutdownDelay >= c.ShutdownTimeout && c.ShutdownTimeout > 0 {
+		v.addf("shutdown_delay", "must be shorter than shutdown_timeout (%s)", c.ShutdownTimeout)
+	}
+
+	for id, tenant := range c.Tenants {
+		if tenant.CacheTTL < 0 {
+			v.addf("tenants."+id+".cache_ttl", "must not be negative (got %s)", tenant.CacheTTL)
+		}
+	}
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errors, func(i, j int) bool { return v.errors[i].Key < v.errors[j].Key })
+	return &ValidationError{Errors: v.errors}
+}
@@ -2,19 +2,59 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func NewMongoDBConnection(uri string) (*mongo.Client, error) {
-    client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
-    if err != nil {
-        return nil, err
-    }
-    err = client.Ping(context.Background(), nil)
-    if err != nil {
-        return nil, err
-    }
-    return client, nil
-}
\ No newline at end of file
+// Pengaturan pool dan timeout koneksi MongoDB, nilai 0 memakai default driver.
+// Parameter yang sama pada URI (maxPoolSize, connectTimeoutMS, retryWrites, dan seterusnya) diutamakan.
+type MongoOptions struct {
+	// Jumlah koneksi maksimum dan minimum per server
+	MaxPoolSize uint64
+	MinPoolSize uint64
+	// Lama koneksi boleh idle sebelum ditutup
+	MaxConnIdleTime time.Duration
+	// Timeout dial, operasi socket dan pemilihan server
+	ConnectTimeout         time.Duration
+	SocketTimeout          time.Duration
+	ServerSelectionTimeout time.Duration
+	// Mengulang sekali operasi tulis dan baca yang gagal karena error jaringan atau failover,
+	// berbeda dengan default driver nilai false menonaktifkannya
+	RetryWrites bool
+	RetryReads  bool
+}
+
+func NewMongoDBConnection(ctx context.Context, uri string, opts MongoOptions) (*mongo.Client, error) {
+	clientOpts := options.Client().
+		SetMinPoolSize(opts.MinPoolSize).
+		SetRetryWrites(opts.RetryWrites).
+		SetRetryReads(opts.RetryReads)
+	if opts.MaxPoolSize > 0 {
+		clientOpts.SetMaxPoolSize(opts.MaxPoolSize)
+	}
+	if opts.MaxConnIdleTime > 0 {
+		clientOpts.SetMaxConnIdleTime(opts.MaxConnIdleTime)
+	}
+	if opts.ConnectTimeout > 0 {
+		clientOpts.SetConnectTimeout(opts.ConnectTimeout)
+	}
+	if opts.SocketTimeout > 0 {
+		clientOpts.SetSocketTimeout(opts.SocketTimeout)
+	}
+	if opts.ServerSelectionTimeout > 0 {
+		clientOpts.SetServerSelectionTimeout(opts.ServerSelectionTimeout)
+	}
+	clientOpts.ApplyURI(uri)
+
+	client, err := mongo.Connect(ctx, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client, nil
+}
@@ -1,18 +1,58 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
-func NewMySQLConnection(dsn string) (*sql.DB, error) {
-    db, err := sql.Open("mysql", dsn)
-    if err != nil {
-        return nil, err
-    }
-    if err = db.Ping(); err != nil {
-        return nil, err
-    }
-    return db, nil
-}
\ No newline at end of file
+// Pengaturan pool dan timeout koneksi MySQL, nilai 0 memakai default database/sql dan driver
+type MySQLOptions struct {
+	// Jumlah koneksi terbuka dan idle maksimum
+	MaxOpenConns int
+	MaxIdleConns int
+	// Umur maksimum koneksi dan lama koneksi boleh idle sebelum ditutup
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// Timeout dial, baca dan tulis. Parameter timeout, readTimeout dan writeTimeout pada DSN diutamakan.
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+}
+
+func NewMySQLConnection(ctx context.Context, dsn string, opts MySQLOptions) (*sql.DB, error) {
+	// Kolom DATETIME harus di-scan sebagai time.Time
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ParseTime = true
+	// UPDATE melaporkan jumlah baris yang cocok, bukan yang berubah, agar produk yang tidak ada bisa dikenali
+	cfg.ClientFoundRows = true
+	if cfg.Timeout == 0 {
+		cfg.Timeout = opts.ConnectTimeout
+	}
+	if cfg.ReadTimeout == 0 {
+		cfg.ReadTimeout = opts.ReadTimeout
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = opts.WriteTimeout
+	}
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Pengaturan percobaan ulang koneksi database saat startup
+type RetryOptions struct {
+	// Jumlah percobaan maksimum, 1 berarti tanpa percobaan ulang
+	Attempts int
+	// Jeda setelah percobaan pertama gagal, naik dua kali lipat sampai MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Menjalankan connect sampai berhasil, percobaan habis atau ctx selesai.
+// Error dari percobaan terakhir dikembalikan jika semua percobaan gagal.
+func ConnectWithRetry[T any](ctx context.Context, name string, opts RetryOptions, connect func(context.Context) (T, error)) (T, error) {
+	backoff := opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				slog.Info("database connected", "database", name, "attempt", attempt)
+			}
+			return conn, nil
+		}
+		if attempt >= opts.Attempts {
+			return conn, err
+		}
+
+		slog.Warn("database connection failed, retrying", "database", name, "attempt", attempt, "backoff", backoff, "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return conn, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if opts.MaxBackoff > 0 && backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}